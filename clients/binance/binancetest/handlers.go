@@ -0,0 +1,635 @@
+package binancetest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+const precision = 8
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	params, err := requestParams(r)
+	if err != nil {
+		writeError(w, &Error{Status: http.StatusBadRequest, Code: CodeBadParameter, Msg: err.Error()})
+		return
+	}
+
+	route := r.Method + " " + r.URL.Path
+
+	s.mu.Lock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Params: params})
+
+	for key, values := range s.headers {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+
+	if injected, ok := s.errors[route]; ok {
+		if injected.times > 0 {
+			injected.times--
+			if injected.times == 0 {
+				delete(s.errors, route)
+			}
+		}
+
+		s.mu.Unlock()
+		writeError(w, injected.err)
+
+		return
+	}
+
+	if apiErr := s.checkTimestamp(params); apiErr != nil {
+		s.mu.Unlock()
+		writeError(w, apiErr)
+
+		return
+	}
+
+	custom, ok := s.handlers[route]
+
+	s.mu.Unlock()
+
+	if ok {
+		custom(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		res    interface{}
+		apiErr *Error
+	)
+
+	switch route {
+	case "GET /api/v3/ping":
+		res = struct{}{}
+	case "GET /api/v3/time":
+		res = map[string]int64{"serverTime": s.serverTime()}
+	case "GET /api/v3/ticker/price":
+		res, apiErr = s.tickerPrice(params)
+	case "GET /api/v3/ticker/bookTicker":
+		res, apiErr = s.bookTicker(params)
+	case "GET /api/v3/exchangeInfo":
+		res, apiErr = s.exchangeInfo(params)
+	case "GET /api/v3/account":
+		res = s.account()
+	case "POST /api/v3/order":
+		res, apiErr = s.createOrder(params)
+	case "GET /api/v3/order":
+		res, apiErr = s.getOrder(params)
+	case "DELETE /api/v3/order":
+		res, apiErr = s.cancelOrder(params)
+	case "GET /api/v3/openOrders":
+		res = s.openOrders(params)
+	case "GET /api/v3/klines":
+		res = s.klinesResponse(params)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) tickerPrice(params url.Values) (interface{}, *Error) {
+	name := params.Get("symbol")
+
+	sym, ok := s.symbols[name]
+	if !ok {
+		return nil, invalidSymbol()
+	}
+
+	return gobinance.SymbolPrice{Symbol: name, Price: sym.price.StringFixed(precision)}, nil
+}
+
+func (s *Server) bookTicker(params url.Values) (interface{}, *Error) {
+	name := params.Get("symbol")
+	if name == "" {
+		return s.bookTickers(), nil
+	}
+
+	sym, ok := s.symbols[name]
+	if !ok {
+		return nil, invalidSymbol()
+	}
+
+	return symbolBookTicker(name, sym), nil
+}
+
+// bookTickers lists the book tickers of all the symbols like Binance does without a symbol
+func (s *Server) bookTickers() []gobinance.BookTicker {
+	names := make([]string, 0, len(s.symbols))
+	for name := range s.symbols {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	tickers := make([]gobinance.BookTicker, 0, len(names))
+	for _, name := range names {
+		tickers = append(tickers, symbolBookTicker(name, s.symbols[name]))
+	}
+
+	return tickers
+}
+
+func symbolBookTicker(name string, sym *symbol) gobinance.BookTicker {
+	bid, ask := sym.bid, sym.ask
+	if bid.IsZero() {
+		bid = sym.price
+	}
+
+	if ask.IsZero() {
+		ask = sym.price
+	}
+
+	return gobinance.BookTicker{
+		Symbol:      name,
+		BidPrice:    bid.StringFixed(precision),
+		BidQuantity: decimal.NewFromInt(1).StringFixed(precision),
+		AskPrice:    ask.StringFixed(precision),
+		AskQuantity: decimal.NewFromInt(1).StringFixed(precision),
+	}
+}
+
+func (s *Server) exchangeInfo(params url.Values) (interface{}, *Error) {
+	var names []string
+
+	switch {
+	case params.Get("symbol") != "":
+		names = []string{params.Get("symbol")}
+	case params.Get("symbols") != "":
+		if err := json.Unmarshal([]byte(params.Get("symbols")), &names); err != nil {
+			return nil, badParameter("symbols")
+		}
+	default:
+		for name := range s.symbols {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+	}
+
+	info := gobinance.ExchangeInfo{
+		Timezone:   "UTC",
+		ServerTime: s.clock,
+		RateLimits: append([]gobinance.RateLimit{}, s.rateLimits...),
+		Symbols:    []gobinance.Symbol{},
+	}
+
+	for _, name := range names {
+		sym, ok := s.symbols[name]
+		if !ok {
+			return nil, invalidSymbol()
+		}
+
+		info.Symbols = append(info.Symbols, gobinance.Symbol{
+			Symbol:     name,
+			Status:     "TRADING",
+			BaseAsset:  sym.baseAsset,
+			QuoteAsset: sym.quoteAsset,
+			Filters: []map[string]interface{}{
+				{"filterType": "PRICE_FILTER", "tickSize": sym.tickSize.StringFixed(precision)},
+				{"filterType": "LOT_SIZE", "stepSize": sym.stepSize.StringFixed(precision), "minQty": sym.stepSize.StringFixed(precision)},
+				{"filterType": "NOTIONAL", "minNotional": sym.minNotional.StringFixed(precision)},
+			},
+		})
+	}
+
+	return info, nil
+}
+
+func (s *Server) account() interface{} {
+	account := gobinance.Account{CanTrade: true, AccountType: "SPOT", Balances: []gobinance.Balance{}}
+
+	for asset, b := range s.balances {
+		account.Balances = append(account.Balances, gobinance.Balance{
+			Asset:  asset,
+			Free:   b.free.StringFixed(precision),
+			Locked: b.locked.StringFixed(precision),
+		})
+	}
+
+	return account
+}
+
+func (s *Server) createOrder(params url.Values) (interface{}, *Error) {
+	sym, ok := s.symbols[params.Get("symbol")]
+	if !ok {
+		return nil, invalidSymbol()
+	}
+
+	quantity, err := decimal.NewFromString(params.Get("quantity"))
+	if err != nil || !quantity.IsPositive() {
+		return nil, badParameter("quantity")
+	}
+
+	respType, ok := orderRespType(params)
+	if !ok {
+		return nil, badParameter("newOrderRespType")
+	}
+
+	order := &models.Order{
+		Symbol:       params.Get("symbol"),
+		Side:         models.SideType(params.Get("side")),
+		Type:         models.OrderType(params.Get("type")),
+		TimeInForce:  models.TimeInForceType(params.Get("timeInForce")),
+		OrigQuantity: quantity,
+		Status:       models.OrderStatusTypeNew,
+		IsWorking:    true,
+	}
+
+	if order.Type != models.OrderTypeMarket {
+		order.Price, err = decimal.NewFromString(params.Get("price"))
+		if err != nil || !order.Price.IsPositive() {
+			return nil, badParameter("price")
+		}
+	}
+
+	if iceberg := params.Get("icebergQty"); iceberg != "" {
+		order.IcebergQuantity, err = decimal.NewFromString(iceberg)
+		if err != nil || !order.IcebergQuantity.IsPositive() || order.IcebergQuantity.GreaterThanOrEqual(quantity) {
+			return nil, badParameter("icebergQty")
+		}
+	}
+
+	if notional := s.notional(sym, order); notional.LessThan(sym.minNotional) {
+		return nil, &Error{
+			Status: http.StatusBadRequest,
+			Code:   CodeFilterFailure,
+			Msg:    "Filter failure: NOTIONAL",
+		}
+	}
+
+	if order.Type == models.OrderTypeLimitMaker && crosses(order, sym.price) {
+		return nil, &Error{
+			Status: http.StatusBadRequest,
+			Code:   CodeInsufficientBalance,
+			Msg:    "Order would immediately match and take.",
+		}
+	}
+
+	if !s.reserve(sym, order) {
+		return nil, &Error{
+			Status: http.StatusBadRequest,
+			Code:   CodeInsufficientBalance,
+			Msg:    "Account has insufficient balance for requested action.",
+		}
+	}
+
+	s.nextID++
+	order.OrderID = s.nextID
+	order.ClientOrderID = "binancetest-" + strconv.FormatInt(order.OrderID, 10)
+	order.Time = s.tick()
+	order.UpdateTime = order.Time
+	s.orders[order.OrderID] = order
+
+	switch {
+	case order.Type == models.OrderTypeMarket:
+		s.fill(order, sym.price)
+	case crosses(order, sym.price):
+		s.fill(order, sym.price)
+	case order.TimeInForce == models.TimeInForceTypeIOC || order.TimeInForce == models.TimeInForceTypeFOK:
+		s.release(sym, order)
+		order.Status = models.OrderStatusTypeExpired
+		order.IsWorking = false
+	}
+
+	if respType == gobinance.NewOrderRespTypeACK {
+		return orderAck{
+			Symbol:        order.Symbol,
+			OrderID:       order.OrderID,
+			OrderListID:   -1,
+			ClientOrderID: order.ClientOrderID,
+			TransactTime:  order.Time,
+		}, nil
+	}
+
+	return gobinance.CreateOrderResponse{
+		Symbol:                   order.Symbol,
+		OrderID:                  order.OrderID,
+		ClientOrderID:            order.ClientOrderID,
+		TransactTime:             order.Time,
+		Price:                    order.Price.StringFixed(precision),
+		OrigQuantity:             order.OrigQuantity.StringFixed(precision),
+		ExecutedQuantity:         order.ExecutedQuantity.StringFixed(precision),
+		CummulativeQuoteQuantity: order.CummulativeQuoteQuantity.StringFixed(precision),
+		Status:                   gobinance.OrderStatusType(order.Status),
+		TimeInForce:              gobinance.TimeInForceType(orderTimeInForce(order)),
+		Type:                     gobinance.OrderType(order.Type),
+		Side:                     gobinance.SideType(order.Side),
+		Fills:                    []*gobinance.Fill{},
+	}, nil
+}
+
+// orderAck is the ACK response to a new order, it only identifies the order
+type orderAck struct {
+	Symbol        string `json:"symbol"`
+	OrderID       int64  `json:"orderId"`
+	OrderListID   int64  `json:"orderListId"`
+	ClientOrderID string `json:"clientOrderId"`
+	TransactTime  int64  `json:"transactTime"`
+}
+
+// orderRespType mirrors Binance answering market and limit orders sent without
+// a response type with the FULL response and any other order with the ACK one
+func orderRespType(params url.Values) (gobinance.NewOrderRespType, bool) {
+	respType := gobinance.NewOrderRespType(params.Get("newOrderRespType"))
+
+	switch respType {
+	case gobinance.NewOrderRespTypeACK, gobinance.NewOrderRespTypeRESULT, gobinance.NewOrderRespTypeFULL:
+		return respType, true
+	case "":
+		orderType := models.OrderType(params.Get("type"))
+		if orderType == models.OrderTypeMarket || orderType == models.OrderTypeLimit {
+			return gobinance.NewOrderRespTypeFULL, true
+		}
+
+		return gobinance.NewOrderRespTypeACK, true
+	default:
+		return "", false
+	}
+}
+
+// notional is the value of the order, market orders are valued at the last price
+func (s *Server) notional(sym *symbol, order *models.Order) decimal.Decimal {
+	if order.Type == models.OrderTypeMarket {
+		return sym.price.Mul(order.OrigQuantity)
+	}
+
+	return order.Price.Mul(order.OrigQuantity)
+}
+
+// reserve checks the balance for the order and locks it for limit orders
+func (s *Server) reserve(sym *symbol, order *models.Order) bool {
+	base, quote := s.balance(sym.baseAsset), s.balance(sym.quoteAsset)
+
+	switch {
+	case order.Side == models.SideTypeBuy && order.Type == models.OrderTypeMarket:
+		return quote.free.GreaterThanOrEqual(sym.price.Mul(order.OrigQuantity))
+	case order.Side == models.SideTypeBuy:
+		notional := order.Price.Mul(order.OrigQuantity)
+		if quote.free.LessThan(notional) {
+			return false
+		}
+
+		quote.free = quote.free.Sub(notional)
+		quote.locked = quote.locked.Add(notional)
+	case order.Type == models.OrderTypeMarket:
+		return base.free.GreaterThanOrEqual(order.OrigQuantity)
+	default:
+		if base.free.LessThan(order.OrigQuantity) {
+			return false
+		}
+
+		base.free = base.free.Sub(order.OrigQuantity)
+		base.locked = base.locked.Add(order.OrigQuantity)
+	}
+
+	return true
+}
+
+// release unlocks the funds of a limit order leaving the book unfilled
+func (s *Server) release(sym *symbol, order *models.Order) {
+	if order.Side == models.SideTypeBuy {
+		quote := s.balance(sym.quoteAsset)
+		notional := order.Price.Mul(order.OrigQuantity)
+		quote.locked = quote.locked.Sub(notional)
+		quote.free = quote.free.Add(notional)
+
+		return
+	}
+
+	base := s.balance(sym.baseAsset)
+	base.locked = base.locked.Sub(order.OrigQuantity)
+	base.free = base.free.Add(order.OrigQuantity)
+}
+
+func (s *Server) getOrder(params url.Values) (interface{}, *Error) {
+	order, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return orderResponse(order), nil
+}
+
+func (s *Server) cancelOrder(params url.Values) (interface{}, *Error) {
+	order, apiErr := s.findOrder(params)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if order.Status != models.OrderStatusTypeNew {
+		return nil, unknownOrder()
+	}
+
+	s.release(s.symbols[order.Symbol], order)
+	order.Status = models.OrderStatusTypeCanceled
+	order.UpdateTime = s.tick()
+	order.IsWorking = false
+
+	return gobinance.CancelOrderResponse{
+		Symbol:                   order.Symbol,
+		OrigClientOrderID:        order.ClientOrderID,
+		OrderID:                  order.OrderID,
+		OrderListID:              -1,
+		ClientOrderID:            order.ClientOrderID,
+		TransactTime:             order.UpdateTime,
+		Price:                    order.Price.StringFixed(precision),
+		OrigQuantity:             order.OrigQuantity.StringFixed(precision),
+		ExecutedQuantity:         order.ExecutedQuantity.StringFixed(precision),
+		CummulativeQuoteQuantity: order.CummulativeQuoteQuantity.StringFixed(precision),
+		Status:                   gobinance.OrderStatusType(order.Status),
+		TimeInForce:              gobinance.TimeInForceType(orderTimeInForce(order)),
+		Type:                     gobinance.OrderType(order.Type),
+		Side:                     gobinance.SideType(order.Side),
+	}, nil
+}
+
+func (s *Server) findOrder(params url.Values) (*models.Order, *Error) {
+	id, err := strconv.ParseInt(params.Get("orderId"), 10, 64)
+	if err != nil {
+		return nil, badParameter("orderId")
+	}
+
+	order, ok := s.orders[id]
+	if !ok || order.Symbol != params.Get("symbol") {
+		return nil, unknownOrder()
+	}
+
+	return order, nil
+}
+
+func (s *Server) openOrders(params url.Values) interface{} {
+	orders := []*gobinance.Order{}
+
+	for _, order := range s.sortedOrders() {
+		if order.Status != models.OrderStatusTypeNew {
+			continue
+		}
+
+		if name := params.Get("symbol"); name != "" && order.Symbol != name {
+			continue
+		}
+
+		orders = append(orders, orderResponse(order))
+	}
+
+	return orders
+}
+
+func (s *Server) klinesResponse(params url.Values) interface{} {
+	klines := s.klines[params.Get("symbol")+params.Get("interval")]
+	res := make([][]interface{}, len(klines))
+
+	for i, k := range klines {
+		res[i] = []interface{}{
+			k.OpenTime,
+			decimal.NewFromFloat(k.Open).StringFixed(precision),
+			decimal.NewFromFloat(k.High).StringFixed(precision),
+			decimal.NewFromFloat(k.Low).StringFixed(precision),
+			decimal.NewFromFloat(k.Close).StringFixed(precision),
+			decimal.NewFromFloat(k.Volume).StringFixed(precision),
+			k.CloseTime,
+			decimal.NewFromFloat(k.QuoteAssetVolume).StringFixed(precision),
+			k.TradeNum,
+			decimal.NewFromFloat(k.TakerBuyBaseAssetVolume).StringFixed(precision),
+			decimal.NewFromFloat(k.TakerBuyQuoteAssetVolume).StringFixed(precision),
+			"0",
+		}
+	}
+
+	return res
+}
+
+func orderResponse(order *models.Order) *gobinance.Order {
+	return &gobinance.Order{
+		Symbol:                   order.Symbol,
+		OrderID:                  order.OrderID,
+		OrderListId:              -1,
+		ClientOrderID:            order.ClientOrderID,
+		Price:                    order.Price.StringFixed(precision),
+		OrigQuantity:             order.OrigQuantity.StringFixed(precision),
+		ExecutedQuantity:         order.ExecutedQuantity.StringFixed(precision),
+		CummulativeQuoteQuantity: order.CummulativeQuoteQuantity.StringFixed(precision),
+		Status:                   gobinance.OrderStatusType(order.Status),
+		TimeInForce:              gobinance.TimeInForceType(orderTimeInForce(order)),
+		Type:                     gobinance.OrderType(order.Type),
+		Side:                     gobinance.SideType(order.Side),
+		StopPrice:                order.StopPrice.StringFixed(precision),
+		IcebergQuantity:          order.IcebergQuantity.StringFixed(precision),
+		Time:                     order.Time,
+		UpdateTime:               order.UpdateTime,
+		IsWorking:                order.IsWorking,
+		OrigQuoteOrderQuantity:   "0.00000000",
+	}
+}
+
+// orderTimeInForce mirrors Binance reporting GTC for orders sent without one
+func orderTimeInForce(order *models.Order) models.TimeInForceType {
+	if order.TimeInForce == "" {
+		return models.TimeInForceTypeGTC
+	}
+
+	return order.TimeInForce
+}
+
+// requestParams merges query and form parameters, the form of DELETE
+// requests isn't parsed by net/http so it's read by hand
+func requestParams(r *http.Request) (url.Values, error) {
+	params := r.URL.Query()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range form {
+		params[k] = append(params[k], v...)
+	}
+
+	return params, nil
+}
+
+func invalidSymbol() *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeInvalidSymbol, Msg: "Invalid symbol."}
+}
+
+func (s *Server) serverTime() int64 {
+	return time.Now().Add(s.clockOffset).UnixMilli()
+}
+
+// checkTimestamp rejects signed requests the way binance does: the timestamp
+// may be at most a second ahead of the server time and at most recvWindow
+// behind it, 5 seconds by default
+func (s *Server) checkTimestamp(params url.Values) *Error {
+	if params.Get("timestamp") == "" {
+		return nil
+	}
+
+	timestamp, err := strconv.ParseInt(params.Get("timestamp"), 10, 64)
+	if err != nil {
+		return badParameter("timestamp")
+	}
+
+	recvWindow := int64(5000)
+	if params.Get("recvWindow") != "" {
+		if recvWindow, err = strconv.ParseInt(params.Get("recvWindow"), 10, 64); err != nil {
+			return badParameter("recvWindow")
+		}
+	}
+
+	if now := s.serverTime(); timestamp >= now+1000 || now-timestamp > recvWindow {
+		return &Error{
+			Status: http.StatusBadRequest,
+			Code:   CodeInvalidTimestamp,
+			Msg:    "Timestamp for this request is outside of the recvWindow.",
+		}
+	}
+
+	return nil
+}
+
+func unknownOrder() *Error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeUnknownOrder, Msg: "Unknown order sent."}
+}
+
+func badParameter(name string) *Error {
+	return &Error{
+		Status: http.StatusBadRequest,
+		Code:   CodeBadParameter,
+		Msg:    "Mandatory parameter '" + name + "' was not sent, was empty/null, or malformed.",
+	}
+}
+
+func writeError(w http.ResponseWriter, err *Error) {
+	writeJSON(w, err.Status, err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,416 @@
+// Package binancetest provides an in-process HTTP server emulating the part of
+// the Binance spot REST API used by binance.BinanceClient, so clients and
+// strategies can be tested offline.
+package binancetest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// Binance error codes returned by the server
+const (
+	CodeUnknownOrder        int64 = -2011
+	CodeInsufficientBalance int64 = -2010
+	CodeInvalidSymbol       int64 = -1121
+	CodeBadParameter        int64 = -1102
+	CodeTooManyRequests     int64 = -1003
+	CodeFilterFailure       int64 = -1013
+	CodeInvalidTimestamp    int64 = -1021
+)
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Params url.Values
+}
+
+// Error is a Binance API error returned instead of the regular response
+type Error struct {
+	Status int    `json:"-"`
+	Code   int64  `json:"code"`
+	Msg    string `json:"msg"`
+}
+
+type symbol struct {
+	baseAsset  string
+	quoteAsset string
+	price      decimal.Decimal
+	bid        decimal.Decimal // best bid, the price if zero
+	ask        decimal.Decimal // best ask, the price if zero
+
+	tickSize    decimal.Decimal
+	stepSize    decimal.Decimal
+	minNotional decimal.Decimal // orders below are rejected, zero disables
+}
+
+type balance struct {
+	free   decimal.Decimal
+	locked decimal.Decimal
+}
+
+type injectedError struct {
+	err   *Error
+	times int // how many requests fail, 0 means every request
+}
+
+// Server is a fake Binance spot REST API. Limit orders rest in the book until
+// SetPrice crosses them or FillOrder is called, market orders fill at once.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	clock    int64
+	nextID   int64
+	symbols  map[string]*symbol
+	balances map[string]*balance
+	orders   map[int64]*models.Order
+	klines   map[string][]*models.Kline
+	errors   map[string]*injectedError
+	handlers map[string]http.HandlerFunc
+	requests []Request
+
+	rateLimits  []gobinance.RateLimit
+	headers     http.Header
+	clockOffset time.Duration
+}
+
+// NewServer starts a fake server, callers should Close it
+func NewServer() *Server {
+	s := &Server{
+		clock:    time.Now().UnixMilli(),
+		symbols:  make(map[string]*symbol),
+		balances: make(map[string]*balance),
+		orders:   make(map[int64]*models.Order),
+		klines:   make(map[string][]*models.Kline),
+		errors:   make(map[string]*injectedError),
+		handlers: make(map[string]http.HandlerFunc),
+		headers:  make(http.Header),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// AddSymbol lists a trading pair, e.g. BTCUSDT with BTC base and USDT quote asset
+func (s *Server) AddSymbol(name, baseAsset, quoteAsset, price string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.symbols[name] = &symbol{
+		baseAsset:  baseAsset,
+		quoteAsset: quoteAsset,
+		price:      decimal.RequireFromString(price),
+	}
+}
+
+// SetPrice changes the last price of a symbol and fills the limit orders it
+// crosses, the best bid and ask become the price until SetBookTicker
+func (s *Server) SetPrice(name, price string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sym, ok := s.symbols[name]
+	if !ok {
+		panic("binancetest: unknown symbol " + name)
+	}
+
+	sym.price = decimal.RequireFromString(price)
+	sym.bid, sym.ask = decimal.Zero, decimal.Zero
+
+	for _, order := range byDistance(s.sortedOrders()) {
+		if order.Symbol == name && order.Status == models.OrderStatusTypeNew && crosses(order, sym.price) {
+			s.fill(order, order.Price)
+		}
+	}
+}
+
+// SetBookTicker sets the best bid and ask of a symbol
+func (s *Server) SetBookTicker(name, bid, ask string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sym, ok := s.symbols[name]
+	if !ok {
+		panic("binancetest: unknown symbol " + name)
+	}
+
+	sym.bid = decimal.RequireFromString(bid)
+	sym.ask = decimal.RequireFromString(ask)
+}
+
+// SetFilters sets the price step, the quantity step and the minimal notional
+// of a symbol reported by the exchange info, orders below the minimal notional
+// are rejected
+func (s *Server) SetFilters(name, tickSize, stepSize, minNotional string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sym, ok := s.symbols[name]
+	if !ok {
+		panic("binancetest: unknown symbol " + name)
+	}
+
+	sym.tickSize = decimal.RequireFromString(tickSize)
+	sym.stepSize = decimal.RequireFromString(stepSize)
+	sym.minNotional = decimal.RequireFromString(minNotional)
+}
+
+// SetBalance sets free and locked amounts of an asset
+func (s *Server) SetBalance(asset, free, locked string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.balances[asset] = &balance{
+		free:   decimal.RequireFromString(free),
+		locked: decimal.RequireFromString(locked),
+	}
+}
+
+// Balance returns free and locked amounts of an asset
+func (s *Server) Balance(asset string) (decimal.Decimal, decimal.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.balance(asset)
+
+	return b.free, b.locked
+}
+
+// SetKlines sets the klines returned for a symbol and interval
+func (s *Server) SetKlines(name, interval string, klines []*models.Kline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.klines[name+interval] = klines
+}
+
+// SetCloses sets klines built from close prices, one minute apart
+func (s *Server) SetCloses(name, interval string, closes []float64) {
+	klines := make([]*models.Kline, len(closes))
+	start := time.Now().Add(-time.Duration(len(closes)) * time.Minute).UnixMilli()
+
+	for i, cl := range closes {
+		openTime := start + int64(i)*time.Minute.Milliseconds()
+
+		klines[i] = &models.Kline{
+			OpenTime:  openTime,
+			Open:      cl,
+			High:      cl,
+			Low:       cl,
+			Close:     cl,
+			Volume:    1,
+			CloseTime: openTime + time.Minute.Milliseconds() - 1,
+		}
+	}
+
+	s.SetKlines(name, interval, klines)
+}
+
+// Orders returns all orders ever placed sorted by id
+func (s *Server) Orders() []*models.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := s.sortedOrders()
+	res := make([]*models.Order, len(orders))
+
+	for i, order := range orders {
+		copied := *order
+		res[i] = &copied
+	}
+
+	return res
+}
+
+// FillOrder fills an open order at its own price
+func (s *Server) FillOrder(orderID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok || order.Status != models.OrderStatusTypeNew {
+		return fmt.Errorf("order %d is not open", orderID)
+	}
+
+	s.fill(order, order.Price)
+
+	return nil
+}
+
+// FailNext makes the next times requests to method and path fail with err,
+// times <= 0 makes every request fail until ClearErrors is called
+func (s *Server) FailNext(method, path string, times int, err *Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err.Status == 0 {
+		err.Status = http.StatusBadRequest
+	}
+
+	s.errors[method+" "+path] = &injectedError{err: err, times: times}
+}
+
+// ClearErrors removes all injected errors
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = make(map[string]*injectedError)
+}
+
+// Handle replaces the emulated endpoint with a custom handler
+func (s *Server) Handle(method, path string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method+" "+path] = h
+}
+
+// SetRateLimits sets the rate limits reported by the exchange info
+func (s *Server) SetRateLimits(limits ...gobinance.RateLimit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimits = limits
+}
+
+// SetHeader adds a header to every response, e.g. X-MBX-USED-WEIGHT-1M or
+// Retry-After, an empty value removes it
+func (s *Server) SetHeader(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if value == "" {
+		s.headers.Del(key)
+		return
+	}
+
+	s.headers.Set(key, value)
+}
+
+// SetClockOffset moves the server time away from the local one, signed
+// requests with a timestamp outside of their recvWindow are rejected
+func (s *Server) SetClockOffset(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clockOffset = d
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo counts the requests received by method and path
+func (s *Server) RequestsTo(method, path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+
+	for _, r := range s.requests {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (s *Server) sortedOrders() []*models.Order {
+	orders := make([]*models.Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		orders = append(orders, order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderID < orders[j].OrderID })
+
+	return orders
+}
+
+func (s *Server) balance(asset string) *balance {
+	b, ok := s.balances[asset]
+	if !ok {
+		b = &balance{}
+		s.balances[asset] = b
+	}
+
+	return b
+}
+
+func (s *Server) tick() int64 {
+	s.clock++
+	return s.clock
+}
+
+// byDistance sorts orders in the sequence a moving price reaches them:
+// buys from the highest price, sells from the lowest one
+func byDistance(orders []*models.Order) []*models.Order {
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].Side != orders[j].Side {
+			return orders[i].Side == models.SideTypeBuy
+		}
+
+		if orders[i].Side == models.SideTypeBuy {
+			return orders[i].Price.GreaterThan(orders[j].Price)
+		}
+
+		return orders[i].Price.LessThan(orders[j].Price)
+	})
+
+	return orders
+}
+
+// crosses reports whether a resting limit order is executable at price
+func crosses(order *models.Order, price decimal.Decimal) bool {
+	if order.Side == models.SideTypeBuy {
+		return price.LessThanOrEqual(order.Price)
+	}
+
+	return price.GreaterThanOrEqual(order.Price)
+}
+
+// fill executes the order at price moving the funds between the assets
+func (s *Server) fill(order *models.Order, price decimal.Decimal) {
+	sym := s.symbols[order.Symbol]
+	base, quote := s.balance(sym.baseAsset), s.balance(sym.quoteAsset)
+	notional := price.Mul(order.OrigQuantity)
+
+	switch {
+	case order.Side == models.SideTypeBuy && order.Type == models.OrderTypeMarket:
+		quote.free = quote.free.Sub(notional)
+		base.free = base.free.Add(order.OrigQuantity)
+	case order.Side == models.SideTypeBuy:
+		quote.locked = quote.locked.Sub(order.Price.Mul(order.OrigQuantity))
+		quote.free = quote.free.Add(order.Price.Mul(order.OrigQuantity)).Sub(notional)
+		base.free = base.free.Add(order.OrigQuantity)
+	case order.Type == models.OrderTypeMarket:
+		base.free = base.free.Sub(order.OrigQuantity)
+		quote.free = quote.free.Add(notional)
+	default:
+		base.locked = base.locked.Sub(order.OrigQuantity)
+		quote.free = quote.free.Add(notional)
+	}
+
+	order.Status = models.OrderStatusTypeFilled
+	order.ExecutedQuantity = order.OrigQuantity
+	order.CummulativeQuoteQuantity = notional
+	order.UpdateTime = s.tick()
+	order.IsWorking = false
+}
@@ -1,6 +1,10 @@
 package binance
 
-import "github.com/caarlos0/env/v6"
+import (
+	"time"
+
+	"github.com/caarlos0/env/v6"
+)
 
 type Config struct {
 	Key    string `env:"BINANCE_KEY"    envDefault:""`
@@ -8,6 +12,14 @@ type Config struct {
 
 	TestKey    string `env:"BINANCE_TEST_KEY"    envDefault:""`
 	TestSecret string `env:"BINANCE_TEST_SECRET" envDefault:""`
+
+	RateLimitShare   float64       `env:"BINANCE_RATE_LIMIT_SHARE"    envDefault:"0.9"` // share of the rate limits the bot may use
+	RateLimitMaxWait time.Duration `env:"BINANCE_RATE_LIMIT_MAX_WAIT" envDefault:"30s"` // requests that would wait longer are rejected
+
+	RecvWindow       time.Duration `env:"BINANCE_RECV_WINDOW"        envDefault:"5s"`  // validity of a signed request after its timestamp
+	TimeSyncInterval time.Duration `env:"BINANCE_TIME_SYNC_INTERVAL" envDefault:"10m"` // how often the offset from the server time is measured
+	TimeDriftWarn    time.Duration `env:"BINANCE_TIME_DRIFT_WARN"    envDefault:"1s"`  // a larger offset is logged, 0 disables
+	TimeDriftHalt    time.Duration `env:"BINANCE_TIME_DRIFT_HALT"    envDefault:"0"`   // orders halt while the offset is larger, 0 disables
 }
 
 func NewBinanceConfig() (*Config, error) {
@@ -1,21 +1,72 @@
 package binance
 
 import (
-	"fmt"
-	"regexp"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/adshao/go-binance/v2/common"
 )
 
-var pattern = regexp.MustCompile(`msg=(.*).$`)
+// Binance error codes, see https://binance-docs.github.io/apidocs/spot/en/#error-codes
+const (
+	codeDisconnected    int64 = -1001
+	codeTimeout         int64 = -1007
+	codeTooManyRequests int64 = -1003
+	codeTooManyOrders   int64 = -1015
+	codeInvalidTime     int64 = -1021
+	codeFilterFailure   int64 = -1013
+	codeOrderRejected   int64 = -2010
+	codeCancelRejected  int64 = -2011
+	codeNoSuchOrder     int64 = -2013
+)
 
+var codeKinds = map[int64]error{
+	codeDisconnected:    clients.ErrNetwork,
+	codeTimeout:         clients.ErrNetwork,
+	codeTooManyRequests: clients.ErrRateLimited,
+	codeTooManyOrders:   clients.ErrRateLimited,
+	codeInvalidTime:     clients.ErrTimestamp,
+	codeFilterFailure:   clients.ErrFilterFailure,
+	codeCancelRejected:  clients.ErrUnknownOrder,
+	codeNoSuchOrder:     clients.ErrUnknownOrder,
+}
+
+// ParseError turns an error of the binance client into a *clients.Error with
+// the code and the message of the API and the kind of the error
 func ParseError(err error) error {
-	errStr := err.Error()
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		return &clients.Error{
+			Kind: codeKind(apiErr),
+			Code: apiErr.Code,
+			Msg:  strings.TrimSuffix(apiErr.Message, "."),
+			Err:  err,
+		}
+	}
 
-	parsedErrors := pattern.FindStringSubmatch(errStr)
+	var (
+		urlErr *url.Error
+		netErr net.Error
+	)
+
+	switch {
+	case errors.Is(err, clients.ErrRateLimited):
+		return &clients.Error{Kind: clients.ErrRateLimited, Msg: err.Error(), Err: err}
+	case errors.As(err, &urlErr), errors.As(err, &netErr):
+		return &clients.Error{Kind: clients.ErrNetwork, Msg: err.Error(), Err: err}
+	}
+
+	return err
+}
 
-	l := len(parsedErrors)
-	if l == 0 {
-		return err
+func codeKind(apiErr *common.APIError) error {
+	// binance rejects orders for many reasons with the same code
+	if apiErr.Code == codeOrderRejected && strings.Contains(strings.ToLower(apiErr.Message), "insufficient balance") {
+		return clients.ErrInsufficientBalance
 	}
 
-	return fmt.Errorf(parsedErrors[l-1])
+	return codeKinds[apiErr.Code]
 }
@@ -0,0 +1,118 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+		wantCode int64
+		wantMsg  string
+	}{
+		{
+			name:     "insufficient balance",
+			err:      &common.APIError{Code: -2010, Message: "Account has insufficient balance for requested action."},
+			wantKind: clients.ErrInsufficientBalance,
+			wantCode: -2010,
+			wantMsg:  "Account has insufficient balance for requested action",
+		},
+		{
+			name:     "other order rejection",
+			err:      &common.APIError{Code: -2010, Message: "Order would immediately match and take."},
+			wantCode: -2010,
+			wantMsg:  "Order would immediately match and take",
+		},
+		{
+			name:     "filter failure",
+			err:      &common.APIError{Code: -1013, Message: "Filter failure: NOTIONAL"},
+			wantKind: clients.ErrFilterFailure,
+			wantCode: -1013,
+			wantMsg:  "Filter failure: NOTIONAL",
+		},
+		{
+			name:     "unknown order",
+			err:      &common.APIError{Code: -2011, Message: "Unknown order sent."},
+			wantKind: clients.ErrUnknownOrder,
+			wantCode: -2011,
+			wantMsg:  "Unknown order sent",
+		},
+		{
+			name:     "rate limited",
+			err:      &common.APIError{Code: -1003, Message: "Too many requests."},
+			wantKind: clients.ErrRateLimited,
+			wantCode: -1003,
+			wantMsg:  "Too many requests",
+		},
+		{
+			name:     "timestamp",
+			err:      &common.APIError{Code: -1021, Message: "Timestamp for this request is outside of the recvWindow."},
+			wantKind: clients.ErrTimestamp,
+			wantCode: -1021,
+			wantMsg:  "Timestamp for this request is outside of the recvWindow",
+		},
+		{
+			name:     "network",
+			err:      &url.Error{Op: "Get", URL: "https://api.binance.com/api/v3/ping", Err: errors.New("connection refused")},
+			wantKind: clients.ErrNetwork,
+			wantMsg:  `Get "https://api.binance.com/api/v3/ping": connection refused`,
+		},
+		{
+			name:     "limiter",
+			err:      &url.Error{Op: "Get", URL: "/", Err: fmt.Errorf("%w: too long", clients.ErrRateLimited)},
+			wantKind: clients.ErrRateLimited,
+			wantMsg:  `Get "/": rate limited: too long`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseError(tt.err)
+
+			var e *clients.Error
+			if !errors.As(err, &e) {
+				t.Fatalf("ParseError() = %T, want *clients.Error", err)
+			}
+
+			if e.Kind != tt.wantKind || e.Code != tt.wantCode || e.Msg != tt.wantMsg {
+				t.Errorf("ParseError() = %+v, want kind %v, code %d, msg %q", e, tt.wantKind, tt.wantCode, tt.wantMsg)
+			}
+
+			if tt.wantKind != nil && !errors.Is(err, tt.wantKind) {
+				t.Errorf("errors.Is(%v, %v) = false", err, tt.wantKind)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseError() lost the cause %v", tt.err)
+			}
+		})
+	}
+
+	if err := errors.New("other"); ParseError(err) != err {
+		t.Error("ParseError() changed an unknown error")
+	}
+}
+
+func TestBinanceClient_TypedErrors(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := c.NewMarketSellOrder(ctx, "BTCUSDT", decimal.NewFromInt(5))
+	if !errors.Is(err, clients.ErrInsufficientBalance) || clients.Code(err) != -2010 {
+		t.Errorf("NewMarketSellOrder() above balance error = %v, code %d, want insufficient balance", err, clients.Code(err))
+	}
+
+	if err := c.CloseOrder(ctx, "BTCUSDT", 42); !errors.Is(err, clients.ErrUnknownOrder) {
+		t.Errorf("CloseOrder() of an unknown order error = %v, want unknown order", err)
+	}
+}
@@ -3,23 +3,39 @@ package binance
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/Minish144/crypto-trading-bot/models"
 	"github.com/Minish144/crypto-trading-bot/utils"
 	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
 )
 
 const (
-	pricePrecision    int = 6
-	quantityPrecision     = 8
+	pricePrecision    uint = 8
+	quantityPrecision uint = 8
 )
 
 type BinanceClient struct {
 	*gobinance.Client
+
+	limiter  *Limiter
+	timeSync timeSync
 }
 
 func NewBinanceClient(c *Config, test bool) *BinanceClient {
-	client := &BinanceClient{}
+	client := &BinanceClient{
+		timeSync: timeSync{
+			recvWindow: c.RecvWindow,
+			interval:   c.TimeSyncInterval,
+			warn:       c.TimeDriftWarn,
+			halt:       c.TimeDriftHalt,
+			now:        time.Now,
+			z:          zap.S().With("context", "binance.TimeSync"),
+		},
+	}
 
 	if test {
 		gobinance.UseTestnet = true
@@ -28,29 +44,115 @@ func NewBinanceClient(c *Config, test bool) *BinanceClient {
 		client.Client = gobinance.NewClient(c.Key, c.Secret)
 	}
 
+	// the default http client is shared, the limiter gets its own
+	client.limiter = NewLimiter(client.HTTPClient.Transport, c.RateLimitShare, c.RateLimitMaxWait)
+	client.HTTPClient = &http.Client{Transport: client.limiter}
+
 	return client
 }
 
+// LoadRateLimits replaces the default rate limits of the limiter with the ones
+// of the exchange info
+func (c *BinanceClient) LoadRateLimits(ctx context.Context) error {
+	info, err := c.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("c.NewExchangeInfoService.Do: %w", ParseError(err))
+	}
+
+	if err := c.limiter.SetRateLimits(info.RateLimits); err != nil {
+		return fmt.Errorf("c.limiter.SetRateLimits: %w", err)
+	}
+
+	return nil
+}
+
 func (c *BinanceClient) Ping(ctx context.Context) error {
-	return c.NewPingService().Do(ctx, gobinance.WithHeader("mock", "mock", false))
+	if err := c.NewPingService().Do(ctx, gobinance.WithHeader("mock", "mock", false)); err != nil {
+		return fmt.Errorf("c.NewPingService.Do: %w", ParseError(err))
+	}
+
+	return nil
 }
 
-func (c *BinanceClient) GetPrice(ctx context.Context, symbol string) (float64, error) {
+func (c *BinanceClient) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
 	prices, err := c.NewListPricesService().
 		Symbol(symbol).
 		Do(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("c.NewListPricesService.Do: %w", ParseError(err))
+		return decimal.Zero, fmt.Errorf("c.NewListPricesService.Do: %w", ParseError(err))
 	} else if len(prices) == 0 {
-		return 0, fmt.Errorf("c.NewListPricesService.Do: empty prices array received")
+		return decimal.Zero, fmt.Errorf("c.NewListPricesService.Do: empty prices array received")
+	}
+
+	price, err := utils.StringToDecimal(prices[len(prices)-1].Price)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("utils.StringToDecimal: %w", err)
+	}
+
+	return price, nil
+}
+
+func (c *BinanceClient) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	tickers, err := c.NewListBookTickersService().
+		Symbol(symbol).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("c.NewListBookTickersService.Do: %w", ParseError(err))
+	} else if len(tickers) == 0 {
+		return nil, fmt.Errorf("c.NewListBookTickersService.Do: empty tickers array received")
+	}
+
+	ticker, err := BookTickerToModel(tickers[len(tickers)-1])
+	if err != nil {
+		return nil, fmt.Errorf("BookTickerToModel: %w", err)
 	}
 
-	pricef64, err := utils.StringToFloat64(prices[len(prices)-1].Price)
+	return ticker, nil
+}
+
+// GetBookTickers returns the best bids and asks of every symbol in one request
+func (c *BinanceClient) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	tickers, err := c.NewListBookTickersService().Do(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("utils.StringToFloat64: %w", err)
+		return nil, fmt.Errorf("c.NewListBookTickersService.Do: %w", ParseError(err))
+	}
+
+	result := make([]*models.BookTicker, 0, len(tickers))
+
+	for _, t := range tickers {
+		ticker, err := BookTickerToModel(t)
+		if err != nil {
+			return nil, fmt.Errorf("BookTickerToModel: %w", err)
+		}
+
+		result = append(result, ticker)
+	}
+
+	return result, nil
+}
+
+// GetSymbols returns the trading rules of the symbols, all of them if none is given
+func (c *BinanceClient) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	request := c.NewExchangeInfoService()
+	if len(symbols) > 0 {
+		request = request.Symbols(symbols...)
+	}
+
+	info, err := request.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("c.NewExchangeInfoService.Do: %w", ParseError(err))
+	}
+
+	res := make([]*models.Symbol, len(info.Symbols))
+
+	for i := range info.Symbols {
+		res[i], err = SymbolToModel(&info.Symbols[i])
+		if err != nil {
+			return nil, fmt.Errorf("SymbolToModel: %w", err)
+		}
 	}
 
-	return pricef64, nil
+	return res, nil
 }
 
 func (c *BinanceClient) NewOrder(
@@ -59,21 +161,21 @@ func (c *BinanceClient) NewOrder(
 	sideType models.SideType,
 	orderType models.OrderType,
 	tif models.TimeInForceType,
-	price, quantity float64,
-) error {
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
 	side, ok := stFromModels[sideType]
 	if !ok {
-		return fmt.Errorf("failed to convert side to binance-type field")
+		return nil, fmt.Errorf("failed to convert side to binance-type field")
 	}
 
 	oType, ok := otFromModels[orderType]
 	if !ok {
-		return fmt.Errorf("failed to convert orderType to binance-type field")
+		return nil, fmt.Errorf("failed to convert orderType to binance-type field")
 	}
 
 	tifType, ok := tiftFromModels[tif]
 	if !ok {
-		return fmt.Errorf("failed to convert timeInForce to binance-type field")
+		return nil, fmt.Errorf("failed to convert timeInForce to binance-type field")
 	}
 
 	return c.newBinanceOrder(
@@ -84,10 +186,15 @@ func (c *BinanceClient) NewOrder(
 		tifType,
 		price,
 		quantity,
+		decimal.Zero,
 	)
 }
 
-func (c *BinanceClient) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity float64) error {
+func (c *BinanceClient) NewLimitBuyOrder(
+	ctx context.Context,
+	symbol string,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
 	return c.newBinanceOrder(
 		ctx,
 		symbol,
@@ -96,6 +203,7 @@ func (c *BinanceClient) NewLimitBuyOrder(ctx context.Context, symbol string, pri
 		gobinance.TimeInForceTypeGTC,
 		price,
 		quantity,
+		decimal.Zero,
 	)
 }
 
@@ -103,8 +211,8 @@ func (c *BinanceClient) NewLimitSellOrder(
 	ctx context.Context,
 	symbol string,
 	price,
-	quantity float64,
-) error {
+	quantity decimal.Decimal,
+) (*models.Order, error) {
 	return c.newBinanceOrder(
 		ctx,
 		symbol,
@@ -113,30 +221,63 @@ func (c *BinanceClient) NewLimitSellOrder(
 		gobinance.TimeInForceTypeGTC,
 		price,
 		quantity,
+		decimal.Zero,
+	)
+}
+
+// NewIcebergOrder places a LIMIT or LIMIT_MAKER order that shows only
+// icebergQuantity of its quantity in the book
+func (c *BinanceClient) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	side, ok := stFromModels[sideType]
+	if !ok {
+		return nil, fmt.Errorf("failed to convert side to binance-type field")
+	}
+
+	if orderType != models.OrderTypeLimit && orderType != models.OrderTypeLimitMaker {
+		return nil, fmt.Errorf("iceberg orders must be LIMIT or LIMIT_MAKER, got %s", orderType)
+	}
+
+	return c.newBinanceOrder(
+		ctx,
+		symbol,
+		side,
+		otFromModels[orderType],
+		gobinance.TimeInForceTypeGTC,
+		price,
+		quantity,
+		icebergQuantity,
 	)
 }
 
-func (c *BinanceClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity float64) error {
+func (c *BinanceClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
 	return c.newBinanceOrder(
 		ctx,
 		symbol,
 		gobinance.SideTypeBuy,
 		gobinance.OrderTypeMarket,
 		gobinance.TimeInForceTypeFOK,
-		0,
+		decimal.Zero,
 		quantity,
+		decimal.Zero,
 	)
 }
 
-func (c *BinanceClient) NewMarketSellOrder(ctx context.Context, symbol string, quantity float64) error {
+func (c *BinanceClient) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
 	return c.newBinanceOrder(
 		ctx,
 		symbol,
 		gobinance.SideTypeSell,
 		gobinance.OrderTypeMarket,
 		gobinance.TimeInForceTypeFOK,
-		0,
+		decimal.Zero,
 		quantity,
+		decimal.Zero,
 	)
 }
 
@@ -146,48 +287,68 @@ func (c *BinanceClient) newBinanceOrder(
 	sideType gobinance.SideType,
 	orderType gobinance.OrderType,
 	tif gobinance.TimeInForceType,
-	price, quantity float64,
-) error {
-	request := c.NewCreateOrderService().
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	if err := c.tradingAllowed(); err != nil {
+		return nil, err
+	}
+
+	request := c.synced().NewCreateOrderService().
 		Symbol(symbol).
 		Side(sideType).
 		Type(orderType).
-		Quantity(utils.Float64ToString(quantity, quantityPrecision))
+		Quantity(utils.DecimalToString(quantity, quantityPrecision)).
+		// binance answers orders other than market and limit ones with an ACK
+		// missing the price, quantities and status of the order
+		NewOrderRespType(gobinance.NewOrderRespTypeRESULT)
 
 	if orderType != gobinance.OrderTypeMarket {
-		request = request.Price(utils.Float64ToString(price, pricePrecision))
+		request = request.Price(utils.PriceToString(price, pricePrecision))
+	}
+
+	if icebergQuantity.IsPositive() {
+		request = request.IcebergQuantity(utils.DecimalToString(icebergQuantity, quantityPrecision))
+	}
+
+	// binance rejects the time in force of market and limit maker orders
+	if orderType != gobinance.OrderTypeMarket && orderType != gobinance.OrderTypeLimitMaker {
 		request = request.TimeInForce(tif)
 	}
 
-	_, err := request.Do(ctx)
+	response, err := request.Do(ctx, c.signed()...)
 	if err != nil {
-		return fmt.Errorf("c.NewCreateOrderService.Do: %v", ParseError(err))
+		return nil, fmt.Errorf("c.NewCreateOrderService.Do: %w", ParseError(err))
 	}
 
-	return nil
+	order, err := CreateOrderResponseToModel(response)
+	if err != nil {
+		return nil, fmt.Errorf("CreateOrderResponseToModel: %w", err)
+	}
+
+	return order, nil
 }
 
-func (c *BinanceClient) GetBalance(ctx context.Context, coin string) (float64, float64, error) {
-	balances, err := c.NewGetAccountService().Do(ctx)
+func (c *BinanceClient) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	balances, err := c.synced().NewGetAccountService().Do(ctx, c.signed()...)
 	if err != nil {
-		return 0, 0, fmt.Errorf("c.NewGetAccountService.Do: %w", ParseError(err))
+		return decimal.Zero, decimal.Zero, fmt.Errorf("c.NewGetAccountService.Do: %w", ParseError(err))
 	}
 
 	var (
-		balance float64 = 0.0
-		locked  float64 = 0.0
+		balance = decimal.Zero
+		locked  = decimal.Zero
 	)
 
 	for _, asset := range balances.Balances {
 		if asset.Asset == coin {
-			balance, err = utils.StringToFloat64(asset.Free)
+			balance, err = utils.StringToDecimal(asset.Free)
 			if err != nil {
-				return 0, 0, fmt.Errorf("utils.StringToFloat64: %w", err)
+				return decimal.Zero, decimal.Zero, fmt.Errorf("utils.StringToDecimal: %w", err)
 			}
 
-			locked, err = utils.StringToFloat64(asset.Locked)
+			locked, err = utils.StringToDecimal(asset.Locked)
 			if err != nil {
-				return 0, 0, fmt.Errorf("utils.StringToFloat64: %w", err)
+				return decimal.Zero, decimal.Zero, fmt.Errorf("utils.StringToDecimal: %w", err)
 			}
 
 			break
@@ -198,7 +359,7 @@ func (c *BinanceClient) GetBalance(ctx context.Context, coin string) (float64, f
 }
 
 func (c *BinanceClient) GetAssets(ctx context.Context) ([]models.Asset, error) {
-	account, err := c.NewGetAccountService().Do(ctx)
+	account, err := c.synced().NewGetAccountService().Do(ctx, c.signed()...)
 	if err != nil {
 		return nil, fmt.Errorf("c.NewGetAccountService.Do: %w", ParseError(err))
 	}
@@ -206,14 +367,14 @@ func (c *BinanceClient) GetAssets(ctx context.Context) ([]models.Asset, error) {
 	assets := make([]models.Asset, len(account.Balances))
 
 	for i, asset := range account.Balances {
-		free, err := utils.StringToFloat64(asset.Free)
+		free, err := utils.StringToDecimal(asset.Free)
 		if err != nil {
-			return nil, fmt.Errorf("utils.StringToFloat64: %w", err)
+			return nil, fmt.Errorf("utils.StringToDecimal: %w", err)
 		}
 
-		locked, err := utils.StringToFloat64(asset.Locked)
+		locked, err := utils.StringToDecimal(asset.Locked)
 		if err != nil {
-			return nil, fmt.Errorf("utils.StringToFloat64: %w", err)
+			return nil, fmt.Errorf("utils.StringToDecimal: %w", err)
 		}
 
 		assets[i] = models.Asset{
@@ -226,10 +387,27 @@ func (c *BinanceClient) GetAssets(ctx context.Context) ([]models.Asset, error) {
 	return assets, nil
 }
 
+func (c *BinanceClient) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	binanceOrder, err := c.synced().NewGetOrderService().
+		Symbol(symbol).
+		OrderID(orderId).
+		Do(ctx, c.signed()...)
+	if err != nil {
+		return nil, fmt.Errorf("c.NewGetOrderService.Do: %w", ParseError(err))
+	}
+
+	order, err := OrdersToModel(binanceOrder)
+	if err != nil {
+		return nil, fmt.Errorf("OrdersToModel: %w", err)
+	}
+
+	return order, nil
+}
+
 func (c *BinanceClient) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
-	binanceOrders, err := c.NewListOpenOrdersService().
+	binanceOrders, err := c.synced().NewListOpenOrdersService().
 		Symbol(symbol).
-		Do(ctx)
+		Do(ctx, c.signed()...)
 	if err != nil {
 		return nil, fmt.Errorf("c.NewListOpenOrdersService.Do: %w", ParseError(err))
 	}
@@ -247,10 +425,10 @@ func (c *BinanceClient) GetOpenOrders(ctx context.Context, symbol string) ([]*mo
 }
 
 func (c *BinanceClient) CloseOrder(ctx context.Context, symbol string, orderId int64) error {
-	if _, err := c.NewCancelOrderService().
+	if _, err := c.synced().NewCancelOrderService().
 		Symbol(symbol).
 		OrderID(orderId).
-		Do(ctx); err != nil {
+		Do(ctx, c.signed()...); err != nil {
 		return fmt.Errorf("c.NewCancelOrderService.Do: %w", ParseError(err))
 	}
 
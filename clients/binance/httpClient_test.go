@@ -0,0 +1,436 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/binance/binancetest"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+func newTestClient(t *testing.T) (*BinanceClient, *binancetest.Server) {
+	t.Helper()
+
+	srv := binancetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddSymbol("BTCUSDT", "BTC", "USDT", "20000")
+	srv.SetBalance("USDT", "1000", "0")
+	srv.SetBalance("BTC", "1", "0")
+
+	c := NewBinanceClient(&Config{Key: "key", Secret: "secret"}, false)
+	c.BaseURL = srv.URL
+
+	return c, srv
+}
+
+func TestBinanceClient_Ping(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	if err := c.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+}
+
+func TestBinanceClient_GetPrice(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.SetPrice("BTCUSDT", "20123.45")
+
+	price, err := c.GetPrice(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetPrice() error = %v", err)
+	}
+
+	if !price.Equal(decimal.RequireFromString("20123.45")) {
+		t.Errorf("GetPrice() = %v, want 20123.45", price)
+	}
+
+	if _, err := c.GetPrice(context.Background(), "NOPE"); err == nil {
+		t.Error("GetPrice() of unknown symbol returned no error")
+	}
+}
+
+func TestBinanceClient_GetBookTicker(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.SetBookTicker("BTCUSDT", "19999.5", "20000.5")
+
+	ticker, err := c.GetBookTicker(context.Background(), "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetBookTicker() error = %v", err)
+	}
+
+	if !ticker.BidPrice.Equal(decimal.RequireFromString("19999.5")) || !ticker.AskPrice.Equal(decimal.RequireFromString("20000.5")) {
+		t.Errorf("GetBookTicker() = %+v, want 19999.5/20000.5", ticker)
+	}
+
+	if _, err := c.GetBookTicker(context.Background(), "NOPE"); err == nil {
+		t.Error("GetBookTicker() of unknown symbol returned no error")
+	}
+}
+
+func TestBinanceClient_GetBookTickers(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.AddSymbol("ETHUSDT", "ETH", "USDT", "1500")
+	srv.SetBookTicker("BTCUSDT", "19999.5", "20000.5")
+
+	tickers, err := c.GetBookTickers(context.Background())
+	if err != nil {
+		t.Fatalf("GetBookTickers() error = %v", err)
+	}
+
+	if len(tickers) != 2 || tickers[0].Symbol != "BTCUSDT" || tickers[1].Symbol != "ETHUSDT" {
+		t.Fatalf("GetBookTickers() = %+v, want BTCUSDT and ETHUSDT", tickers)
+	}
+
+	if !tickers[0].AskPrice.Equal(decimal.RequireFromString("20000.5")) || !tickers[1].BidPrice.Equal(decimal.NewFromInt(1500)) {
+		t.Errorf("GetBookTickers() = %+v %+v, want the set prices", tickers[0], tickers[1])
+	}
+}
+
+func TestBinanceClient_GetBalanceAndAssets(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.SetBalance("ETH", "0.5", "0.25")
+
+	free, locked, err := c.GetBalance(context.Background(), "ETH")
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	if !free.Equal(decimal.RequireFromString("0.5")) || !locked.Equal(decimal.RequireFromString("0.25")) {
+		t.Errorf("GetBalance() = %v, %v, want 0.5, 0.25", free, locked)
+	}
+
+	assets, err := c.GetAssets(context.Background())
+	if err != nil {
+		t.Fatalf("GetAssets() error = %v", err)
+	}
+
+	if len(assets) != 3 {
+		t.Errorf("GetAssets() returned %d assets, want 3", len(assets))
+	}
+}
+
+func TestBinanceClient_LimitOrderLifecycle(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewLimitBuyOrder(ctx, "BTCUSDT", decimal.NewFromInt(19000), decimal.RequireFromString("0.01"))
+	if err != nil {
+		t.Fatalf("NewLimitBuyOrder() error = %v", err)
+	}
+
+	if order.Status != models.OrderStatusTypeNew || order.Side != models.SideTypeBuy {
+		t.Errorf("NewLimitBuyOrder() = %+v, want a new buy order", order)
+	}
+
+	open, err := c.GetOpenOrders(ctx, "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetOpenOrders() error = %v", err)
+	}
+
+	if len(open) != 1 || open[0].OrderID != order.OrderID || !open[0].Price.Equal(decimal.NewFromInt(19000)) {
+		t.Fatalf("GetOpenOrders() = %+v, want the placed order", open)
+	}
+
+	if _, locked := srv.Balance("USDT"); !locked.Equal(decimal.NewFromInt(190)) {
+		t.Errorf("locked USDT = %v, want 190", locked)
+	}
+
+	if err := c.CloseOrder(ctx, "BTCUSDT", order.OrderID); err != nil {
+		t.Fatalf("CloseOrder() error = %v", err)
+	}
+
+	got, err := c.GetOrder(ctx, "BTCUSDT", order.OrderID)
+	if err != nil {
+		t.Fatalf("GetOrder() error = %v", err)
+	}
+
+	if got.Status != models.OrderStatusTypeCanceled {
+		t.Errorf("GetOrder() status = %v, want CANCELED", got.Status)
+	}
+
+	if err := c.CloseOrder(ctx, "BTCUSDT", order.OrderID); err == nil {
+		t.Error("CloseOrder() of canceled order returned no error")
+	}
+}
+
+func TestBinanceClient_LimitOrderFilledByPrice(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewLimitSellOrder(ctx, "BTCUSDT", decimal.NewFromInt(21000), decimal.RequireFromString("0.5"))
+	if err != nil {
+		t.Fatalf("NewLimitSellOrder() error = %v", err)
+	}
+
+	srv.SetPrice("BTCUSDT", "21500")
+
+	got, err := c.GetOrder(ctx, "BTCUSDT", order.OrderID)
+	if err != nil {
+		t.Fatalf("GetOrder() error = %v", err)
+	}
+
+	if got.Status != models.OrderStatusTypeFilled || !got.ExecutedQuantity.Equal(decimal.RequireFromString("0.5")) {
+		t.Errorf("GetOrder() = %+v, want filled order", got)
+	}
+
+	if free, _ := srv.Balance("USDT"); !free.Equal(decimal.NewFromInt(11500)) {
+		t.Errorf("free USDT = %v, want 11500", free)
+	}
+}
+
+func TestBinanceClient_LimitMakerOrder(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewOrder(
+		ctx,
+		"BTCUSDT",
+		models.SideTypeBuy,
+		models.OrderTypeLimitMaker,
+		models.TimeInForceTypeGTC,
+		decimal.NewFromInt(19900),
+		decimal.RequireFromString("0.01"),
+	)
+	if err != nil {
+		t.Fatalf("NewOrder() error = %v", err)
+	}
+
+	if order.Type != models.OrderTypeLimitMaker || order.Status != models.OrderStatusTypeNew {
+		t.Errorf("NewOrder() = %+v, want a new limit maker order", order)
+	}
+
+	requests := srv.Requests()
+	if got := requests[len(requests)-1].Params.Get("timeInForce"); got != "" {
+		t.Errorf("sent timeInForce = %s, want none", got)
+	}
+
+	// without a response type binance only acknowledges limit maker orders
+	if got := requests[len(requests)-1].Params.Get("newOrderRespType"); got != "RESULT" {
+		t.Errorf("sent newOrderRespType = %s, want RESULT", got)
+	}
+
+	if _, err := c.NewOrder(
+		ctx,
+		"BTCUSDT",
+		models.SideTypeBuy,
+		models.OrderTypeLimitMaker,
+		models.TimeInForceTypeGTC,
+		decimal.NewFromInt(20100),
+		decimal.RequireFromString("0.01"),
+	); err == nil {
+		t.Error("NewOrder() of a limit maker taking liquidity returned no error")
+	}
+}
+
+func TestBinanceClient_IcebergOrder(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewIcebergOrder(
+		ctx,
+		"BTCUSDT",
+		models.SideTypeSell,
+		models.OrderTypeLimitMaker,
+		decimal.NewFromInt(20100),
+		decimal.RequireFromString("0.5"),
+		decimal.RequireFromString("0.1"),
+	)
+	if err != nil {
+		t.Fatalf("NewIcebergOrder() error = %v", err)
+	}
+
+	requests := srv.Requests()
+	if got := requests[len(requests)-1].Params.Get("icebergQty"); got != "0.10000000" {
+		t.Errorf("sent icebergQty = %s, want 0.10000000", got)
+	}
+
+	got, err := c.GetOrder(ctx, "BTCUSDT", order.OrderID)
+	if err != nil {
+		t.Fatalf("GetOrder() error = %v", err)
+	}
+
+	if !got.IcebergQuantity.Equal(decimal.RequireFromString("0.1")) || got.Type != models.OrderTypeLimitMaker {
+		t.Errorf("GetOrder() = %+v, want a limit maker order showing 0.1", got)
+	}
+
+	if _, err := c.NewIcebergOrder(
+		ctx,
+		"BTCUSDT",
+		models.SideTypeSell,
+		models.OrderTypeMarket,
+		decimal.Zero,
+		decimal.RequireFromString("0.5"),
+		decimal.RequireFromString("0.1"),
+	); err == nil {
+		t.Error("NewIcebergOrder() of a market order returned no error")
+	}
+
+	if _, err := c.NewIcebergOrder(
+		ctx,
+		"BTCUSDT",
+		models.SideTypeSell,
+		models.OrderTypeLimit,
+		decimal.NewFromInt(20100),
+		decimal.RequireFromString("0.1"),
+		decimal.RequireFromString("0.1"),
+	); err == nil {
+		t.Error("NewIcebergOrder() showing the whole quantity returned no error")
+	}
+}
+
+func TestBinanceClient_MarketOrders(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.01"))
+	if err != nil {
+		t.Fatalf("NewMarketBuyOrder() error = %v", err)
+	}
+
+	if order.Status != models.OrderStatusTypeFilled || !order.CummulativeQuoteQuantity.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("NewMarketBuyOrder() = %+v, want filled for 200 USDT", order)
+	}
+
+	if free, _ := srv.Balance("BTC"); !free.Equal(decimal.RequireFromString("1.01")) {
+		t.Errorf("free BTC = %v, want 1.01", free)
+	}
+
+	if _, err := c.NewMarketSellOrder(ctx, "BTCUSDT", decimal.NewFromInt(5)); err == nil {
+		t.Error("NewMarketSellOrder() above balance returned no error")
+	}
+}
+
+func TestBinanceClient_GetSymbols(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	srv.AddSymbol("ETHUSDT", "ETH", "USDT", "1500")
+	srv.SetFilters("BTCUSDT", "0.01", "0.00001", "10")
+
+	symbols, err := c.GetSymbols(ctx, "BTCUSDT")
+	if err != nil {
+		t.Fatalf("GetSymbols() error = %v", err)
+	}
+
+	if len(symbols) != 1 {
+		t.Fatalf("GetSymbols() returned %d symbols, want 1", len(symbols))
+	}
+
+	want := &models.Symbol{
+		Symbol:      "BTCUSDT",
+		Status:      "TRADING",
+		BaseAsset:   "BTC",
+		QuoteAsset:  "USDT",
+		TickSize:    decimal.RequireFromString("0.01"),
+		StepSize:    decimal.RequireFromString("0.00001"),
+		MinQuantity: decimal.RequireFromString("0.00001"),
+		MinNotional: decimal.NewFromInt(10),
+	}
+
+	got := symbols[0]
+	if got.Symbol != want.Symbol || got.Status != want.Status || got.BaseAsset != want.BaseAsset || got.QuoteAsset != want.QuoteAsset ||
+		!got.TickSize.Equal(want.TickSize) || !got.StepSize.Equal(want.StepSize) ||
+		!got.MinQuantity.Equal(want.MinQuantity) || !got.MinNotional.Equal(want.MinNotional) {
+		t.Errorf("GetSymbols() = %+v, want %+v", got, want)
+	}
+
+	if all, err := c.GetSymbols(ctx); err != nil || len(all) != 2 {
+		t.Errorf("GetSymbols() of all symbols = %d symbols, %v, want 2", len(all), err)
+	}
+
+	if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.0001")); err == nil {
+		t.Error("NewMarketBuyOrder() below min notional returned no error")
+	}
+}
+
+func TestBinanceClient_QuantityFormatting(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	// 1/3 can't be sent as is, the extra digits must be dropped rather than rounded up
+	quantity := decimal.NewFromInt(1).Div(decimal.NewFromInt(3))
+
+	if _, err := c.NewLimitSellOrder(context.Background(), "BTCUSDT", decimal.NewFromInt(25000), quantity); err != nil {
+		t.Fatalf("NewLimitSellOrder() error = %v", err)
+	}
+
+	requests := srv.Requests()
+	if got := requests[len(requests)-1].Params.Get("quantity"); got != "0.33333333" {
+		t.Errorf("sent quantity = %s, want 0.33333333", got)
+	}
+}
+
+func TestBinanceClient_PriceFormatting(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	// a price is rounded to the closest one that can be sent
+	price := decimal.RequireFromString("25000.123456789")
+
+	if _, err := c.NewLimitSellOrder(context.Background(), "BTCUSDT", price, decimal.RequireFromString("0.01")); err != nil {
+		t.Fatalf("NewLimitSellOrder() error = %v", err)
+	}
+
+	requests := srv.Requests()
+	if got := requests[len(requests)-1].Params.Get("price"); got != "25000.12345679" {
+		t.Errorf("sent price = %s, want 25000.12345679", got)
+	}
+}
+
+func TestBinanceClient_GetKlines(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.SetCloses("BTCUSDT", "15m", []float64{1, 2.5, 3})
+
+	klines, err := c.GetKlines(context.Background(), "BTCUSDT", "15m")
+	if err != nil {
+		t.Fatalf("GetKlines() error = %v", err)
+	}
+
+	if len(klines) != 3 || klines[1].Close != 2.5 {
+		t.Errorf("GetKlines() = %+v, want 3 klines", klines)
+	}
+
+	closes, err := c.GetKlinesCloses(context.Background(), "BTCUSDT", "15m")
+	if err != nil {
+		t.Fatalf("GetKlinesCloses() error = %v", err)
+	}
+
+	if len(closes) != 3 || closes[2] != 3 {
+		t.Errorf("GetKlinesCloses() = %v, want [1 2.5 3]", closes)
+	}
+}
+
+func TestBinanceClient_InjectedErrors(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	srv.FailNext(http.MethodGet, "/api/v3/account", 1, &binancetest.Error{
+		Status: http.StatusTooManyRequests,
+		Code:   binancetest.CodeTooManyRequests,
+		Msg:    "Too many requests.",
+	})
+
+	_, _, err := c.GetBalance(ctx, "USDT")
+	if err == nil || err.Error() != "c.NewGetAccountService.Do: Too many requests" {
+		t.Errorf("GetBalance() error = %v, want the injected one", err)
+	}
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Errorf("GetBalance() after the injected error = %v", err)
+	}
+
+	srv.FailNext(http.MethodPost, "/api/v3/order", 0, &binancetest.Error{Code: -1013, Msg: "Filter failure: LOT_SIZE"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.NewFromInt(1)); err == nil {
+			t.Errorf("NewMarketBuyOrder() #%d returned no error", i)
+		}
+	}
+
+	srv.ClearErrors()
+
+	if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.001")); err != nil {
+		t.Errorf("NewMarketBuyOrder() after ClearErrors = %v", err)
+	}
+}
@@ -0,0 +1,416 @@
+package binance
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/metrics"
+	"github.com/Minish144/crypto-trading-bot/models"
+	gobinance "github.com/adshao/go-binance/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	exchangeName = "binance"
+
+	usedWeightHeader = "X-Mbx-Used-Weight-"
+	orderCountHeader = "X-Mbx-Order-Count-"
+)
+
+// endpointWeight is the request weight of an endpoint, all is used when the
+// request has no symbol. The weights are the ones of the binance spot API
+// docs, an endpoint missing here weighs 1.
+type endpointWeight struct {
+	symbol int64
+	all    int64
+}
+
+var (
+	endpointWeights = map[string]endpointWeight{
+		"GET /api/v3/ping":              {1, 1},
+		"GET /api/v3/time":              {1, 1},
+		"GET /api/v3/exchangeInfo":      {20, 20},
+		"GET /api/v3/ticker/price":      {2, 4},
+		"GET /api/v3/ticker/bookTicker": {2, 4},
+		"GET /api/v3/klines":            {2, 2},
+		"GET /api/v3/account":           {20, 20},
+		"POST /api/v3/order":            {1, 1},
+		"GET /api/v3/order":             {4, 4},
+		"DELETE /api/v3/order":          {1, 1},
+		"GET /api/v3/openOrders":        {6, 80},
+	}
+
+	// defaultRateLimits are used until the ones of the exchange info are loaded
+	defaultRateLimits = []gobinance.RateLimit{
+		{
+			RateLimitType: string(gobinance.RateLimitTypeRequestWeight),
+			Interval:      string(gobinance.RateLimitIntervalMinute),
+			IntervalNum:   1,
+			Limit:         6000,
+		},
+		{
+			RateLimitType: string(gobinance.RateLimitTypeOrders),
+			Interval:      string(gobinance.RateLimitIntervalSecond),
+			IntervalNum:   10,
+			Limit:         100,
+		},
+		{
+			RateLimitType: string(gobinance.RateLimitTypeOrders),
+			Interval:      string(gobinance.RateLimitIntervalDay),
+			IntervalNum:   1,
+			Limit:         200000,
+		},
+		{
+			RateLimitType: string(gobinance.RateLimitTypeRawRequests),
+			Interval:      string(gobinance.RateLimitIntervalMinute),
+			IntervalNum:   5,
+			Limit:         61000,
+		},
+	}
+
+	intervalDurations = map[models.Interval]time.Duration{
+		models.IntervalSecond: time.Second,
+		models.IntervalMinute: time.Minute,
+		models.IntervalDay:    24 * time.Hour,
+	}
+
+	headerUnits = map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+	}
+)
+
+type rateLimit struct {
+	kind   gobinance.RateLimitType
+	window time.Duration
+	max    int64
+	used   int64
+	start  time.Time // of the current window
+}
+
+// roll starts a new window once the current one is over, binance windows are
+// aligned to the clock
+func (l *rateLimit) roll(now time.Time) {
+	if start := now.Truncate(l.window); start.After(l.start) {
+		l.start = start
+		l.used = 0
+	}
+}
+
+// cost is what a request takes from the limit
+func (l *rateLimit) cost(weight int64, order bool) int64 {
+	switch l.kind {
+	case gobinance.RateLimitTypeRequestWeight:
+		return weight
+	case gobinance.RateLimitTypeOrders:
+		if order {
+			return 1
+		}
+
+		return 0
+	default:
+		return 1
+	}
+}
+
+// interval formats the window the way the usage headers do, e.g. 1m or 10s
+func (l *rateLimit) interval() string {
+	for _, unit := range []byte{'d', 'h', 'm', 's'} {
+		if l.window%headerUnits[unit] == 0 {
+			return strconv.FormatInt(int64(l.window/headerUnits[unit]), 10) + string(unit)
+		}
+	}
+
+	return l.window.String()
+}
+
+// Limiter keeps the requests of a client under the binance rate limits. It
+// wraps the transport of the client: a request reserves the weight of its
+// endpoint first and waits for the next window if it doesn't fit, or fails
+// with clients.ErrRateLimited if the wait is longer than the max one. The used weight
+// and order counts of the response headers replace the local estimates, and
+// a 429 or 418 response with Retry-After holds every request until then.
+type Limiter struct {
+	next    http.RoundTripper
+	share   float64
+	maxWait time.Duration
+	z       *zap.SugaredLogger
+
+	mu          sync.Mutex
+	limits      []*rateLimit
+	bannedUntil time.Time
+
+	// now and after are the clock of the limiter, tests replace them
+	now   func() time.Time
+	after func(d time.Duration) <-chan time.Time
+}
+
+// NewLimiter wraps the transport, the default one if it is nil. A share out of
+// (0, 1] uses the whole limits.
+func NewLimiter(next http.RoundTripper, share float64, maxWait time.Duration) *Limiter {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if share <= 0 || share > 1 {
+		share = 1
+	}
+
+	l := &Limiter{
+		next:    next,
+		share:   share,
+		maxWait: maxWait,
+		z:       zap.S().With("context", "binance.Limiter"),
+		now:     time.Now,
+		after:   time.After,
+	}
+
+	// the defaults are valid
+	_ = l.SetRateLimits(defaultRateLimits)
+
+	return l
+}
+
+// SetRateLimits replaces the limits, usually with the ones of the exchange
+// info. The usage of the limits that stay is kept.
+func (l *Limiter) SetRateLimits(rateLimits []gobinance.RateLimit) error {
+	limits := make([]*rateLimit, 0, len(rateLimits))
+
+	for _, rl := range rateLimits {
+		interval, ok := intervalToModels[gobinance.RateLimitInterval(rl.Interval)]
+		if !ok {
+			return fmt.Errorf("unknown rate limit interval %s", rl.Interval)
+		}
+
+		if rl.IntervalNum <= 0 || rl.Limit <= 0 {
+			return fmt.Errorf("invalid %s rate limit %d per %d %s", rl.RateLimitType, rl.Limit, rl.IntervalNum, rl.Interval)
+		}
+
+		limits = append(limits, &rateLimit{
+			kind:   gobinance.RateLimitType(rl.RateLimitType),
+			window: time.Duration(rl.IntervalNum) * intervalDurations[interval],
+			max:    rl.Limit,
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, limit := range limits {
+		if old := l.find(limit.kind, limit.window); old != nil {
+			limit.used, limit.start = old.used, old.start
+		}
+	}
+
+	l.limits = limits
+	l.observe()
+
+	return nil
+}
+
+func (l *Limiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := l.acquire(req); err != nil {
+		// a round tripper closes the body even if it doesn't send the request
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	res, err := l.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	l.update(res)
+
+	return res, nil
+}
+
+// acquire waits until the request fits into every limit
+func (l *Limiter) acquire(req *http.Request) error {
+	weight, order := requestWeight(req)
+
+	for {
+		wait, kind := l.reserve(weight, order)
+		if wait <= 0 {
+			return nil
+		}
+
+		if wait > l.maxWait {
+			metrics.RateLimitThrottled.WithLabelValues(exchangeName, kind, "reject").Inc()
+			return fmt.Errorf("%w: %s %s would wait %s for %s", clients.ErrRateLimited, req.Method, req.URL.Path, wait, kind)
+		}
+
+		metrics.RateLimitThrottled.WithLabelValues(exchangeName, kind, "wait").Inc()
+		l.z.Debugw("waiting for rate limit", "method", req.Method, "path", req.URL.Path, "type", kind, "wait", wait)
+
+		select {
+		case <-l.after(wait):
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+}
+
+// reserve takes the cost of a request from every limit if it fits into all of
+// them, otherwise it returns how long to wait and for which limit
+func (l *Limiter) reserve(weight int64, order bool) (time.Duration, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.bannedUntil) {
+		return l.bannedUntil.Sub(now), "BAN"
+	}
+
+	var (
+		wait time.Duration
+		kind string
+	)
+
+	for _, limit := range l.limits {
+		cost := limit.cost(weight, order)
+		if cost == 0 {
+			continue
+		}
+
+		limit.roll(now)
+
+		// a request larger than the allowed share still goes into an empty window
+		if limit.used > 0 && float64(limit.used+cost) > l.share*float64(limit.max) {
+			if w := limit.start.Add(limit.window).Sub(now); w > wait {
+				wait, kind = w, string(limit.kind)
+			}
+		}
+	}
+
+	if wait > 0 {
+		return wait, kind
+	}
+
+	for _, limit := range l.limits {
+		limit.used += limit.cost(weight, order)
+	}
+
+	l.observe()
+
+	return 0, ""
+}
+
+// update takes the usage reported by the exchange and the bans
+func (l *Limiter) update(res *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for key, values := range res.Header {
+		kind, window, ok := parseUsageHeader(key)
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		used, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if limit := l.find(kind, window); limit != nil {
+			limit.roll(now)
+			limit.used = used
+		}
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusTeapot {
+		metrics.RateLimitThrottled.WithLabelValues(exchangeName, "BAN", strconv.Itoa(res.StatusCode)).Inc()
+
+		if seconds, err := strconv.ParseInt(res.Header.Get("Retry-After"), 10, 64); err == nil && seconds > 0 {
+			l.bannedUntil = now.Add(time.Duration(seconds) * time.Second)
+
+			l.z.Warnw(
+				"rate limit exceeded, requests are held",
+				"status", res.StatusCode,
+				"until", l.bannedUntil,
+			)
+		}
+	}
+
+	l.observe()
+}
+
+func (l *Limiter) find(kind gobinance.RateLimitType, window time.Duration) *rateLimit {
+	for _, limit := range l.limits {
+		if limit.kind == kind && limit.window == window {
+			return limit
+		}
+	}
+
+	return nil
+}
+
+func (l *Limiter) observe() {
+	for _, limit := range l.limits {
+		metrics.RateLimitUsed.WithLabelValues(exchangeName, string(limit.kind), limit.interval()).Set(float64(limit.used))
+		metrics.RateLimitMax.WithLabelValues(exchangeName, string(limit.kind), limit.interval()).Set(float64(limit.max))
+	}
+}
+
+// requestWeight returns the weight of the request and whether it places an order
+func requestWeight(req *http.Request) (int64, bool) {
+	route := req.Method + " " + req.URL.Path
+
+	weight, ok := endpointWeights[route]
+	if !ok {
+		return 1, false
+	}
+
+	if req.URL.Query().Get("symbol") == "" {
+		return weight.all, route == "POST /api/v3/order"
+	}
+
+	return weight.symbol, route == "POST /api/v3/order"
+}
+
+// parseUsageHeader parses headers like X-MBX-USED-WEIGHT-1M and X-MBX-ORDER-COUNT-10S
+func parseUsageHeader(key string) (gobinance.RateLimitType, time.Duration, bool) {
+	key = http.CanonicalHeaderKey(key)
+
+	var (
+		kind     gobinance.RateLimitType
+		interval string
+	)
+
+	switch {
+	case strings.HasPrefix(key, usedWeightHeader):
+		kind, interval = gobinance.RateLimitTypeRequestWeight, strings.TrimPrefix(key, usedWeightHeader)
+	case strings.HasPrefix(key, orderCountHeader):
+		kind, interval = gobinance.RateLimitTypeOrders, strings.TrimPrefix(key, orderCountHeader)
+	default:
+		return "", 0, false
+	}
+
+	if len(interval) < 2 {
+		return "", 0, false
+	}
+
+	unit, ok := headerUnits[strings.ToLower(interval)[len(interval)-1]]
+	if !ok {
+		return "", 0, false
+	}
+
+	n, err := strconv.ParseInt(interval[:len(interval)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return "", 0, false
+	}
+
+	return kind, time.Duration(n) * unit, true
+}
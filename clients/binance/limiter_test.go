@@ -0,0 +1,250 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/binance/binancetest"
+	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// newLimitedClient returns a client whose limiter runs on a fake clock, a
+// wait moves the clock instead of sleeping and is recorded
+func newLimitedClient(
+	t *testing.T,
+	maxWait time.Duration,
+	limits ...gobinance.RateLimit,
+) (*BinanceClient, *binancetest.Server, *[]time.Duration) {
+	t.Helper()
+
+	c, srv := newTestClient(t)
+
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	waits := &[]time.Duration{}
+
+	c.limiter.maxWait = maxWait
+	c.limiter.now = func() time.Time { return now }
+	c.limiter.after = func(d time.Duration) <-chan time.Time {
+		*waits = append(*waits, d)
+		now = now.Add(d)
+
+		ch := make(chan time.Time, 1)
+		ch <- now
+
+		return ch
+	}
+
+	if err := c.limiter.SetRateLimits(limits); err != nil {
+		t.Fatalf("SetRateLimits() error = %v", err)
+	}
+
+	return c, srv, waits
+}
+
+func weightLimit(limit int64) gobinance.RateLimit {
+	return gobinance.RateLimit{
+		RateLimitType: string(gobinance.RateLimitTypeRequestWeight),
+		Interval:      string(gobinance.RateLimitIntervalMinute),
+		IntervalNum:   1,
+		Limit:         limit,
+	}
+}
+
+func TestLimiter_WaitsForNextWindow(t *testing.T) {
+	c, _, waits := newLimitedClient(t, time.Minute, weightLimit(22))
+	ctx := context.Background()
+
+	// the account endpoint weighs 20, the ping 1
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if len(*waits) != 0 {
+		t.Fatalf("waits = %v, want none within the limit", *waits)
+	}
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Fatalf("GetBalance() above the limit error = %v", err)
+	}
+
+	if len(*waits) != 1 || (*waits)[0] != time.Minute {
+		t.Errorf("waits = %v, want one of 1m", *waits)
+	}
+}
+
+func TestLimiter_RejectsLongWaits(t *testing.T) {
+	c, srv, _ := newLimitedClient(t, time.Second, weightLimit(22))
+	ctx := context.Background()
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	requests := len(srv.Requests())
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); !errors.Is(err, clients.ErrRateLimited) {
+		t.Errorf("GetBalance() error = %v, want ErrRateLimited", err)
+	}
+
+	if got := len(srv.Requests()); got != requests {
+		t.Errorf("requests sent = %d, want the rejected one not sent", got-requests)
+	}
+}
+
+func TestLimiter_UsesHeaders(t *testing.T) {
+	c, srv, waits := newLimitedClient(t, time.Minute, weightLimit(1200))
+	ctx := context.Background()
+
+	srv.SetHeader("X-MBX-USED-WEIGHT-1M", "1195")
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if used := c.limiter.limits[0].used; used != 1195 {
+		t.Errorf("used weight = %d, want 1195 from the header", used)
+	}
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	if len(*waits) != 1 {
+		t.Errorf("waits = %v, want one for the reported weight", *waits)
+	}
+}
+
+func TestLimiter_HoldsRequestsAfterBan(t *testing.T) {
+	c, srv, waits := newLimitedClient(t, time.Minute, weightLimit(1200))
+	ctx := context.Background()
+
+	srv.SetHeader("Retry-After", "30")
+	srv.FailNext(http.MethodGet, "/api/v3/ping", 1, &binancetest.Error{
+		Status: http.StatusTooManyRequests,
+		Code:   binancetest.CodeTooManyRequests,
+		Msg:    "Too many requests.",
+	})
+
+	if err := c.Ping(ctx); err == nil {
+		t.Fatal("Ping() returned no error for 429")
+	}
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() after the ban error = %v", err)
+	}
+
+	if len(*waits) != 1 || (*waits)[0] != 30*time.Second {
+		t.Errorf("waits = %v, want one of 30s", *waits)
+	}
+}
+
+func TestLimiter_CountsOrders(t *testing.T) {
+	orders := gobinance.RateLimit{
+		RateLimitType: string(gobinance.RateLimitTypeOrders),
+		Interval:      string(gobinance.RateLimitIntervalSecond),
+		IntervalNum:   10,
+		Limit:         1,
+	}
+
+	c, _, waits := newLimitedClient(t, time.Minute, weightLimit(1200), orders)
+	ctx := context.Background()
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.01")); err != nil {
+		t.Fatalf("NewMarketBuyOrder() error = %v", err)
+	}
+
+	if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.01")); err != nil {
+		t.Fatalf("NewMarketBuyOrder() error = %v", err)
+	}
+
+	if len(*waits) != 1 || (*waits)[0] != 10*time.Second {
+		t.Errorf("waits = %v, want one of 10s for the second order", *waits)
+	}
+}
+
+func TestBinanceClient_LoadRateLimits(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.SetRateLimits(weightLimit(6000))
+
+	if err := c.LoadRateLimits(context.Background()); err != nil {
+		t.Fatalf("LoadRateLimits() error = %v", err)
+	}
+
+	if limits := c.limiter.limits; len(limits) != 1 || limits[0].max != 6000 || limits[0].window != time.Minute {
+		t.Errorf("limits = %+v, want 6000 per minute", limits)
+	}
+
+	srv.SetRateLimits(gobinance.RateLimit{RateLimitType: "REQUEST_WEIGHT", Interval: "WEEK", IntervalNum: 1, Limit: 1})
+
+	if err := c.LoadRateLimits(context.Background()); err == nil {
+		t.Error("LoadRateLimits() with an unknown interval returned no error")
+	}
+}
+
+func TestRequestWeight(t *testing.T) {
+	tests := []struct {
+		method     string
+		url        string
+		wantWeight int64
+		wantOrder  bool
+	}{
+		{method: http.MethodGet, url: "/api/v3/ticker/bookTicker?symbol=BTCUSDT", wantWeight: 2},
+		{method: http.MethodGet, url: "/api/v3/ticker/bookTicker", wantWeight: 4},
+		{method: http.MethodGet, url: "/api/v3/openOrders?symbol=BTCUSDT", wantWeight: 6},
+		{method: http.MethodGet, url: "/api/v3/openOrders", wantWeight: 80},
+		{method: http.MethodGet, url: "/api/v3/order?symbol=BTCUSDT&orderId=1", wantWeight: 4},
+		{method: http.MethodGet, url: "/api/v3/account", wantWeight: 20},
+		{method: http.MethodGet, url: "/api/v3/exchangeInfo", wantWeight: 20},
+		{method: http.MethodGet, url: "/api/v3/klines?symbol=BTCUSDT&interval=1m", wantWeight: 2},
+		{method: http.MethodPost, url: "/api/v3/order", wantWeight: 1, wantOrder: true},
+		{method: http.MethodGet, url: "/api/v3/unknown", wantWeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			weight, order := requestWeight(httptest.NewRequest(tt.method, tt.url, nil))
+			if weight != tt.wantWeight || order != tt.wantOrder {
+				t.Errorf("requestWeight() = %d, %v, want %d, %v", weight, order, tt.wantWeight, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestParseUsageHeader(t *testing.T) {
+	tests := []struct {
+		key        string
+		wantKind   gobinance.RateLimitType
+		wantWindow time.Duration
+		wantOK     bool
+	}{
+		{key: "X-MBX-USED-WEIGHT-1M", wantKind: gobinance.RateLimitTypeRequestWeight, wantWindow: time.Minute, wantOK: true},
+		{key: "X-Mbx-Order-Count-10s", wantKind: gobinance.RateLimitTypeOrders, wantWindow: 10 * time.Second, wantOK: true},
+		{key: "X-MBX-ORDER-COUNT-1D", wantKind: gobinance.RateLimitTypeOrders, wantWindow: 24 * time.Hour, wantOK: true},
+		{key: "X-MBX-USED-WEIGHT"},
+		{key: "X-MBX-USED-WEIGHT-1W"},
+		{key: "Content-Type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			kind, window, ok := parseUsageHeader(tt.key)
+			if kind != tt.wantKind || window != tt.wantWindow || ok != tt.wantOK {
+				t.Errorf("parseUsageHeader() = %s, %s, %v, want %s, %s, %v", kind, window, ok, tt.wantKind, tt.wantWindow, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/metrics"
+	gobinance "github.com/adshao/go-binance/v2"
+	"go.uber.org/zap"
+)
+
+// timeSync is the state of the server time synchronization of a client
+type timeSync struct {
+	recvWindow time.Duration
+	interval   time.Duration
+	warn       time.Duration
+	halt       time.Duration
+
+	halted int32        // 1 while the offset is above the halt threshold
+	offset atomic.Value // time.Duration, the last measured one
+
+	// now is the local clock, tests replace it
+	now func() time.Time
+	z   *zap.SugaredLogger
+}
+
+// SyncTime measures the offset of the local clock from the server time and
+// applies it to the timestamps of the signed requests. The server time is
+// taken as the middle of the round trip. Orders are rejected while the offset
+// is above the halt threshold.
+func (c *BinanceClient) SyncTime(ctx context.Context) (time.Duration, error) {
+	sent := c.timeSync.now()
+
+	serverTime, err := c.NewServerTimeService().Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("c.NewServerTimeService.Do: %w", ParseError(err))
+	}
+
+	received := c.timeSync.now()
+
+	local := sent.Add(received.Sub(sent) / 2)
+	offset := local.Sub(time.UnixMilli(serverTime))
+
+	c.timeSync.offset.Store(offset)
+
+	metrics.ClockOffset.WithLabelValues(exchangeName).Set(offset.Seconds())
+
+	drift := offset
+	if drift < 0 {
+		drift = -drift
+	}
+
+	if c.timeSync.warn > 0 && drift > c.timeSync.warn {
+		c.timeSync.z.Warnw("local clock drifts from the server time", "offset", offset, "threshold", c.timeSync.warn)
+	}
+
+	if c.timeSync.halt > 0 && drift > c.timeSync.halt {
+		if atomic.SwapInt32(&c.timeSync.halted, 1) == 0 {
+			c.timeSync.z.Errorw(
+				"trading halted, local clock drifts too far from the server time",
+				"offset", offset,
+				"threshold", c.timeSync.halt,
+			)
+		}
+	} else if atomic.SwapInt32(&c.timeSync.halted, 0) == 1 {
+		c.timeSync.z.Infow("trading resumed, local clock is back in sync", "offset", offset)
+	}
+
+	return offset, nil
+}
+
+// StartTimeSync syncs the time every interval until the context is done
+func (c *BinanceClient) StartTimeSync(ctx context.Context) {
+	if c.timeSync.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.timeSync.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := c.SyncTime(ctx); err != nil {
+				c.timeSync.z.Warnw("failed to sync time", "error", err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// tradingAllowed fails while the clock drift halts trading
+func (c *BinanceClient) tradingAllowed() error {
+	if atomic.LoadInt32(&c.timeSync.halted) == 0 {
+		return nil
+	}
+
+	offset, _ := c.timeSync.offset.Load().(time.Duration)
+
+	return &clients.Error{
+		Kind: clients.ErrTimestamp,
+		Msg:  fmt.Sprintf("trading halted: local clock is %s off the server time", offset),
+	}
+}
+
+// synced returns a copy of the go-binance client applying the last measured
+// offset to the timestamps it signs. The shared client reads its offset
+// without a lock, so it is never written once requests are made.
+func (c *BinanceClient) synced() *gobinance.Client {
+	client := *c.Client
+
+	offset, _ := c.timeSync.offset.Load().(time.Duration)
+	client.TimeOffset = offset.Milliseconds()
+
+	return &client
+}
+
+// signed returns the options of the signed requests
+func (c *BinanceClient) signed() []gobinance.RequestOption {
+	if c.timeSync.recvWindow <= 0 {
+		return nil
+	}
+
+	return []gobinance.RequestOption{gobinance.WithRecvWindow(c.timeSync.recvWindow.Milliseconds())}
+}
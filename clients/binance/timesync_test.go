@@ -0,0 +1,123 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/shopspring/decimal"
+)
+
+func TestBinanceClient_SyncTime(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	// the local clock is 10 seconds ahead of the server one
+	srv.SetClockOffset(-10 * time.Second)
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); !errors.Is(err, clients.ErrTimestamp) || clients.Code(err) != -1021 {
+		t.Fatalf("GetBalance() before the sync error = %v, want the timestamp one", err)
+	}
+
+	offset, err := c.SyncTime(ctx)
+	if err != nil {
+		t.Fatalf("SyncTime() error = %v", err)
+	}
+
+	if offset < 9*time.Second || offset > 11*time.Second {
+		t.Errorf("SyncTime() = %s, want about 10s", offset)
+	}
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Errorf("GetBalance() after the sync error = %v", err)
+	}
+}
+
+func TestBinanceClient_SyncTimeHaltsTrading(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	c.timeSync.halt = 5 * time.Second
+	srv.SetClockOffset(time.Minute)
+
+	if _, err := c.SyncTime(ctx); err != nil {
+		t.Fatalf("SyncTime() error = %v", err)
+	}
+
+	requests := srv.RequestsTo(http.MethodPost, "/api/v3/order")
+
+	if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.01")); !errors.Is(err, clients.ErrTimestamp) {
+		t.Errorf("NewMarketBuyOrder() while halted error = %v, want the timestamp one", err)
+	}
+
+	if got := srv.RequestsTo(http.MethodPost, "/api/v3/order"); got != requests {
+		t.Errorf("orders sent while halted = %d, want none", got-requests)
+	}
+
+	// reading and canceling go on
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Errorf("GetBalance() while halted error = %v", err)
+	}
+
+	srv.SetClockOffset(0)
+
+	if _, err := c.SyncTime(ctx); err != nil {
+		t.Fatalf("SyncTime() error = %v", err)
+	}
+
+	if _, err := c.NewMarketBuyOrder(ctx, "BTCUSDT", decimal.RequireFromString("0.01")); err != nil {
+		t.Errorf("NewMarketBuyOrder() after the clock is back error = %v", err)
+	}
+}
+
+func TestBinanceClient_RecvWindow(t *testing.T) {
+	c, srv := newTestClient(t)
+	c.timeSync.recvWindow = 10 * time.Second
+
+	// 8 seconds behind the server fits only into the longer window
+	srv.SetClockOffset(8 * time.Second)
+
+	if _, _, err := c.GetBalance(context.Background(), "USDT"); err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	requests := srv.Requests()
+	if got := requests[len(requests)-1].Params.Get("recvWindow"); got != "10000" {
+		t.Errorf("sent recvWindow = %s, want 10000", got)
+	}
+}
+
+// the periodic sync runs next to the signed requests of the strategies, run
+// with -race
+func TestBinanceClient_SyncTimeConcurrent(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	srv.SetClockOffset(-10 * time.Second)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 10; i++ {
+			if _, err := c.SyncTime(ctx); err != nil {
+				t.Errorf("SyncTime() error = %v", err)
+			}
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		// the requests made before the first sync are rejected
+		_, _, _ = c.GetBalance(ctx, "USDT")
+	}
+
+	<-done
+
+	if _, _, err := c.GetBalance(ctx, "USDT"); err != nil {
+		t.Errorf("GetBalance() after the sync error = %v", err)
+	}
+}
@@ -6,27 +6,28 @@ import (
 	"github.com/Minish144/crypto-trading-bot/models"
 	"github.com/Minish144/crypto-trading-bot/utils"
 	gobinance "github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
 )
 
 func OrdersToModel(o *gobinance.Order) (*models.Order, error) {
-	price, err := utils.StringToFloat64(o.Price)
+	price, err := utils.StringToDecimal(o.Price)
 	if err != nil {
-		return nil, fmt.Errorf("StringToFloat64 price: %w", err)
+		return nil, fmt.Errorf("StringToDecimal price: %w", err)
 	}
 
-	origQuantity, err := utils.StringToFloat64(o.OrigQuantity)
+	origQuantity, err := utils.StringToDecimal(o.OrigQuantity)
 	if err != nil {
-		return nil, fmt.Errorf("StringToFloat64 origQuantity: %w", err)
+		return nil, fmt.Errorf("StringToDecimal origQuantity: %w", err)
 	}
 
-	executedQuantity, err := utils.StringToFloat64(o.ExecutedQuantity)
+	executedQuantity, err := utils.StringToDecimal(o.ExecutedQuantity)
 	if err != nil {
-		return nil, fmt.Errorf("StringToFloat64 executedQuantity: %w", err)
+		return nil, fmt.Errorf("StringToDecimal executedQuantity: %w", err)
 	}
 
-	cummulativeQuoteQuantity, err := utils.StringToFloat64(o.CummulativeQuoteQuantity)
+	cummulativeQuoteQuantity, err := utils.StringToDecimal(o.CummulativeQuoteQuantity)
 	if err != nil {
-		return nil, fmt.Errorf("StringToFloat64 cummulativeQuoteQuantity: %w", err)
+		return nil, fmt.Errorf("StringToDecimal cummulativeQuoteQuantity: %w", err)
 	}
 
 	status, ok := ostToModels[o.Status]
@@ -49,14 +50,14 @@ func OrdersToModel(o *gobinance.Order) (*models.Order, error) {
 		return nil, fmt.Errorf("failed to map sideType to model")
 	}
 
-	stopPrice, err := utils.StringToFloat64(o.StopPrice)
+	stopPrice, err := utils.StringToDecimal(o.StopPrice)
 	if err != nil {
-		return nil, fmt.Errorf("StringToFloat64 stopPrice: %w", err)
+		return nil, fmt.Errorf("StringToDecimal stopPrice: %w", err)
 	}
 
-	iceberg, err := utils.StringToFloat64(o.IcebergQuantity)
+	iceberg, err := utils.StringToDecimal(o.IcebergQuantity)
 	if err != nil {
-		return nil, fmt.Errorf("StringToFloat64 icebergQuantity: %w", err)
+		return nil, fmt.Errorf("StringToDecimal icebergQuantity: %w", err)
 	}
 
 	oModel := models.Order{
@@ -82,6 +83,61 @@ func OrdersToModel(o *gobinance.Order) (*models.Order, error) {
 	return &oModel, nil
 }
 
+func CreateOrderResponseToModel(r *gobinance.CreateOrderResponse) (*models.Order, error) {
+	price, err := utils.StringToDecimal(r.Price)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal price: %w", err)
+	}
+
+	origQuantity, err := utils.StringToDecimal(r.OrigQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal origQuantity: %w", err)
+	}
+
+	executedQuantity, err := utils.StringToDecimal(r.ExecutedQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal executedQuantity: %w", err)
+	}
+
+	cummulativeQuoteQuantity, err := utils.StringToDecimal(r.CummulativeQuoteQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal cummulativeQuoteQuantity: %w", err)
+	}
+
+	status, ok := ostToModels[r.Status]
+	if !ok {
+		return nil, fmt.Errorf("failed to map status to model")
+	}
+
+	ot, ok := otToModels[r.Type]
+	if !ok {
+		return nil, fmt.Errorf("failed to map orderType to model")
+	}
+
+	side, ok := stToModels[r.Side]
+	if !ok {
+		return nil, fmt.Errorf("failed to map sideType to model")
+	}
+
+	oModel := models.Order{
+		Symbol:                   r.Symbol,
+		OrderID:                  r.OrderID,
+		ClientOrderID:            r.ClientOrderID,
+		Price:                    price,
+		OrigQuantity:             origQuantity,
+		ExecutedQuantity:         executedQuantity,
+		CummulativeQuoteQuantity: cummulativeQuoteQuantity,
+		Status:                   status,
+		TimeInForce:              tiftToModels[r.TimeInForce],
+		Type:                     ot,
+		Side:                     side,
+		Time:                     r.TransactTime,
+		UpdateTime:               r.TransactTime,
+	}
+
+	return &oModel, nil
+}
+
 func OrdersFromModel(o *models.Order) (*gobinance.Order, error) {
 	status, ok := ostFromModels[o.Status]
 	if !ok {
@@ -108,16 +164,16 @@ func OrdersFromModel(o *models.Order) (*gobinance.Order, error) {
 		OrderID:                  o.OrderID,
 		OrderListId:              o.OrderListId,
 		ClientOrderID:            o.ClientOrderID,
-		Price:                    utils.Float64ToString(o.Price, pricePrecision),
-		OrigQuantity:             utils.Float64ToString(o.OrigQuantity, quantityPrecision),
-		ExecutedQuantity:         utils.Float64ToString(o.ExecutedQuantity, quantityPrecision),
-		CummulativeQuoteQuantity: utils.Float64ToString(o.CummulativeQuoteQuantity, quantityPrecision),
+		Price:                    utils.PriceToString(o.Price, pricePrecision),
+		OrigQuantity:             utils.DecimalToString(o.OrigQuantity, quantityPrecision),
+		ExecutedQuantity:         utils.DecimalToString(o.ExecutedQuantity, quantityPrecision),
+		CummulativeQuoteQuantity: utils.DecimalToString(o.CummulativeQuoteQuantity, quantityPrecision),
 		Status:                   status,
 		TimeInForce:              tif,
 		Type:                     ot,
 		Side:                     side,
-		StopPrice:                utils.Float64ToString(o.StopPrice, pricePrecision),
-		IcebergQuantity:          utils.Float64ToString(o.IcebergQuantity, quantityPrecision),
+		StopPrice:                utils.PriceToString(o.StopPrice, pricePrecision),
+		IcebergQuantity:          utils.DecimalToString(o.IcebergQuantity, quantityPrecision),
 		Time:                     o.Time,
 		UpdateTime:               o.UpdateTime,
 		IsWorking:                o.IsWorking,
@@ -187,17 +243,85 @@ func KlineToModel(k *gobinance.Kline) (*models.Kline, error) {
 func KlineFromModel(k *models.Kline) *gobinance.Kline {
 	kline := gobinance.Kline{
 		OpenTime:                 k.OpenTime,
-		Open:                     utils.Float64ToString(k.Open, pricePrecision),
-		High:                     utils.Float64ToString(k.High, pricePrecision),
-		Low:                      utils.Float64ToString(k.Low, pricePrecision),
-		Close:                    utils.Float64ToString(k.Close, pricePrecision),
-		Volume:                   utils.Float64ToString(k.Volume, quantityPrecision),
+		Open:                     utils.Float64ToString(k.Open, int(pricePrecision)),
+		High:                     utils.Float64ToString(k.High, int(pricePrecision)),
+		Low:                      utils.Float64ToString(k.Low, int(pricePrecision)),
+		Close:                    utils.Float64ToString(k.Close, int(pricePrecision)),
+		Volume:                   utils.Float64ToString(k.Volume, int(quantityPrecision)),
 		CloseTime:                k.CloseTime,
-		QuoteAssetVolume:         utils.Float64ToString(k.QuoteAssetVolume, quantityPrecision),
+		QuoteAssetVolume:         utils.Float64ToString(k.QuoteAssetVolume, int(quantityPrecision)),
 		TradeNum:                 k.TradeNum,
-		TakerBuyBaseAssetVolume:  utils.Float64ToString(k.TakerBuyBaseAssetVolume, quantityPrecision),
-		TakerBuyQuoteAssetVolume: utils.Float64ToString(k.TakerBuyQuoteAssetVolume, quantityPrecision),
+		TakerBuyBaseAssetVolume:  utils.Float64ToString(k.TakerBuyBaseAssetVolume, int(quantityPrecision)),
+		TakerBuyQuoteAssetVolume: utils.Float64ToString(k.TakerBuyQuoteAssetVolume, int(quantityPrecision)),
 	}
 
 	return &kline
 }
+
+func BookTickerToModel(t *gobinance.BookTicker) (*models.BookTicker, error) {
+	bidPrice, err := utils.StringToDecimal(t.BidPrice)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal bidPrice: %w", err)
+	}
+
+	bidQuantity, err := utils.StringToDecimal(t.BidQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal bidQuantity: %w", err)
+	}
+
+	askPrice, err := utils.StringToDecimal(t.AskPrice)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal askPrice: %w", err)
+	}
+
+	askQuantity, err := utils.StringToDecimal(t.AskQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("StringToDecimal askQuantity: %w", err)
+	}
+
+	return &models.BookTicker{
+		Symbol:      t.Symbol,
+		BidPrice:    bidPrice,
+		BidQuantity: bidQuantity,
+		AskPrice:    askPrice,
+		AskQuantity: askQuantity,
+	}, nil
+}
+
+// SymbolToModel reads the price, lot size and notional filters of a symbol,
+// the notional filter is MIN_NOTIONAL on older and NOTIONAL on newer symbols
+func SymbolToModel(s *gobinance.Symbol) (*models.Symbol, error) {
+	symbol := &models.Symbol{
+		Symbol:     s.Symbol,
+		Status:     s.Status,
+		BaseAsset:  s.BaseAsset,
+		QuoteAsset: s.QuoteAsset,
+	}
+
+	fields := map[string]map[string]*decimal.Decimal{
+		string(gobinance.SymbolFilterTypePriceFilter): {"tickSize": &symbol.TickSize},
+		string(gobinance.SymbolFilterTypeLotSize):     {"stepSize": &symbol.StepSize, "minQty": &symbol.MinQuantity},
+		string(gobinance.SymbolFilterTypeMinNotional): {"minNotional": &symbol.MinNotional},
+		"NOTIONAL": {"minNotional": &symbol.MinNotional},
+	}
+
+	for _, filter := range s.Filters {
+		filterType, _ := filter["filterType"].(string)
+
+		for name, dst := range fields[filterType] {
+			value, ok := filter[name].(string)
+			if !ok {
+				continue
+			}
+
+			d, err := utils.StringToDecimal(value)
+			if err != nil {
+				return nil, fmt.Errorf("StringToDecimal %s: %w", name, err)
+			}
+
+			*dst = d
+		}
+	}
+
+	return symbol, nil
+}
@@ -2,13 +2,15 @@ package bybit
 
 import (
 	"context"
-	"errors"
 
+	"github.com/Minish144/crypto-trading-bot/clients"
 	"github.com/Minish144/crypto-trading-bot/models"
 	hirokisanBybit "github.com/hirokisan/bybit/v2"
+	"github.com/shopspring/decimal"
 )
 
-var ErrNotImplemented = errors.New("not implemented")
+// ErrNotImplemented is returned by every call, the client is a stub yet
+var ErrNotImplemented = clients.ErrNotImplemented
 
 type BybitClient struct {
 	*hirokisanBybit.Client
@@ -22,12 +24,24 @@ func (c *BybitClient) Ping(ctx context.Context) error {
 	return ErrNotImplemented
 }
 
-func (c *BybitClient) GetPrice(ctx context.Context, symbol string) (float64, error) {
-	return 0, ErrNotImplemented
+func (c *BybitClient) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	return decimal.Zero, ErrNotImplemented
 }
 
-func (c *BybitClient) GetBalance(ctx context.Context, coin string) (float64, float64, error) {
-	return 0, 0, ErrNotImplemented
+func (c *BybitClient) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	return nil, ErrNotImplemented
+}
+
+func (c *BybitClient) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	return nil, ErrNotImplemented
+}
+
+func (c *BybitClient) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	return nil, ErrNotImplemented
+}
+
+func (c *BybitClient) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	return decimal.Zero, decimal.Zero, ErrNotImplemented
 }
 
 func (c *BybitClient) NewOrder(
@@ -36,31 +50,45 @@ func (c *BybitClient) NewOrder(
 	sideType models.SideType,
 	orderType models.OrderType,
 	tif models.TimeInForceType,
-	price, quantity float64,
-) error {
-	return ErrNotImplemented
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	return nil, ErrNotImplemented
 }
 
-func (c *BybitClient) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity float64) error {
-	return ErrNotImplemented
+func (c *BybitClient) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return nil, ErrNotImplemented
 }
 
-func (c *BybitClient) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity float64) error {
-	return ErrNotImplemented
+func (c *BybitClient) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return nil, ErrNotImplemented
 }
 
-func (c *BybitClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity float64) error {
-	return ErrNotImplemented
+func (c *BybitClient) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	return nil, ErrNotImplemented
 }
 
-func (c *BybitClient) NewMarketSellOrder(ctx context.Context, symbol string, quantity float64) error {
-	return ErrNotImplemented
+func (c *BybitClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return nil, ErrNotImplemented
+}
+
+func (c *BybitClient) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return nil, ErrNotImplemented
 }
 
 func (c *BybitClient) GetAssets(ctx context.Context) ([]models.Asset, error) {
 	return nil, ErrNotImplemented
 }
 
+func (c *BybitClient) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	return nil, ErrNotImplemented
+}
+
 func (c *BybitClient) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
 	return nil, ErrNotImplemented
 }
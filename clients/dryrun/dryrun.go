@@ -0,0 +1,460 @@
+// Package dryrun wraps a clients.HttpClient to trade on paper: market data
+// stays live while the orders are only logged and simulated.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Client sends the requests that only read to the exchange and never sends an
+// order. The orders are logged and filled against the live book: market ones
+// at once, limit ones when the book crosses their price, checked on GetOrder
+// and GetOpenOrders. The balances are the live ones changed by the simulated
+// orders, so a strategy sees the result of its trades.
+//
+// Simulated orders have negative ids, so they never clash with real ones.
+// GetOrder of a real id is sent to the exchange, GetOpenOrders only returns
+// the simulated orders and CloseOrder never cancels a real one.
+type Client struct {
+	clients.HttpClient
+
+	mu       sync.Mutex
+	lastID   int64
+	orders   map[int64]*models.Order
+	symbols  map[string]*models.Symbol
+	balances map[string]*balance // changes made by the simulated orders
+
+	// now stamps the orders, tests replace it
+	now func() time.Time
+	z   *zap.SugaredLogger
+}
+
+type balance struct {
+	free   decimal.Decimal
+	locked decimal.Decimal
+}
+
+func NewClient(c clients.HttpClient) *Client {
+	return &Client{
+		HttpClient: c,
+		orders:     make(map[int64]*models.Order),
+		symbols:    make(map[string]*models.Symbol),
+		balances:   make(map[string]*balance),
+		now:        time.Now,
+		z:          zap.S().With("context", "dryrun.Client"),
+	}
+}
+
+// Middleware wraps clients with NewClient, every wrapped client keeps its own
+// orders and balances
+func Middleware() clients.Middleware {
+	return func(next clients.HttpClient) clients.HttpClient {
+		return NewClient(next)
+	}
+}
+
+func (c *Client) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	free, locked, err := c.HttpClient.GetBalance(ctx, coin)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if b, ok := c.balances[coin]; ok {
+		free, locked = free.Add(b.free), locked.Add(b.locked)
+	}
+
+	return free, locked, nil
+}
+
+func (c *Client) GetAssets(ctx context.Context) ([]models.Asset, error) {
+	assets, err := c.HttpClient.GetAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool, len(assets))
+
+	for i := range assets {
+		seen[assets[i].Coin] = true
+
+		if b, ok := c.balances[assets[i].Coin]; ok {
+			assets[i].Free = assets[i].Free.Add(b.free)
+			assets[i].Locked = assets[i].Locked.Add(b.locked)
+		}
+	}
+
+	coins := make([]string, 0, len(c.balances))
+	for coin := range c.balances {
+		if !seen[coin] {
+			coins = append(coins, coin)
+		}
+	}
+
+	sort.Strings(coins)
+
+	for _, coin := range coins {
+		assets = append(assets, models.Asset{Coin: coin, Free: c.balances[coin].free, Locked: c.balances[coin].locked})
+	}
+
+	return assets, nil
+}
+
+func (c *Client) NewOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	tif models.TimeInForceType,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	return c.place(ctx, &models.Order{
+		Symbol:       symbol,
+		Side:         sideType,
+		Type:         orderType,
+		TimeInForce:  tif,
+		Price:        price,
+		OrigQuantity: quantity,
+	})
+}
+
+func (c *Client) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return c.NewOrder(ctx, symbol, models.SideTypeBuy, models.OrderTypeLimit, models.TimeInForceTypeGTC, price, quantity)
+}
+
+func (c *Client) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return c.NewOrder(ctx, symbol, models.SideTypeSell, models.OrderTypeLimit, models.TimeInForceTypeGTC, price, quantity)
+}
+
+func (c *Client) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	if orderType != models.OrderTypeLimit && orderType != models.OrderTypeLimitMaker {
+		return nil, fmt.Errorf("iceberg orders must be LIMIT or LIMIT_MAKER, got %s", orderType)
+	}
+
+	if !icebergQuantity.IsPositive() || icebergQuantity.GreaterThanOrEqual(quantity) {
+		return nil, fmt.Errorf("invalid iceberg quantity %s", icebergQuantity)
+	}
+
+	return c.place(ctx, &models.Order{
+		Symbol:          symbol,
+		Side:            sideType,
+		Type:            orderType,
+		TimeInForce:     models.TimeInForceTypeGTC,
+		Price:           price,
+		OrigQuantity:    quantity,
+		IcebergQuantity: icebergQuantity,
+	})
+}
+
+func (c *Client) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return c.NewOrder(ctx, symbol, models.SideTypeBuy, models.OrderTypeMarket, models.TimeInForceTypeGTC, decimal.Zero, quantity)
+}
+
+func (c *Client) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return c.NewOrder(ctx, symbol, models.SideTypeSell, models.OrderTypeMarket, models.TimeInForceTypeGTC, decimal.Zero, quantity)
+}
+
+func (c *Client) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	if orderId > 0 {
+		return c.HttpClient.GetOrder(ctx, symbol, orderId)
+	}
+
+	if err := c.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	order, ok := c.orders[orderId]
+	if !ok || order.Symbol != symbol {
+		return nil, unknownOrder(orderId)
+	}
+
+	copied := *order
+
+	return &copied, nil
+}
+
+func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
+	if err := c.refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var orders []*models.Order
+
+	for _, order := range c.orders {
+		if order.Symbol == symbol && order.IsWorking {
+			copied := *order
+			orders = append(orders, &copied)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderID > orders[j].OrderID })
+
+	return orders, nil
+}
+
+func (c *Client) CloseOrder(ctx context.Context, symbol string, orderId int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	order, ok := c.orders[orderId]
+	if !ok || order.Symbol != symbol || !order.IsWorking {
+		return unknownOrder(orderId)
+	}
+
+	c.unlock(order)
+
+	order.Status = models.OrderStatusTypeCanceled
+	order.UpdateTime = c.now().UnixMilli()
+	order.IsWorking = false
+
+	c.z.Infow("dry run order canceled", "symbol", symbol, "orderId", orderId)
+
+	return nil
+}
+
+// place simulates a new order against the live book and logs it
+func (c *Client) place(ctx context.Context, order *models.Order) (*models.Order, error) {
+	if !order.OrigQuantity.IsPositive() {
+		return nil, fmt.Errorf("invalid quantity %s", order.OrigQuantity)
+	}
+
+	symbol, err := c.symbol(ctx, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker, err := c.HttpClient.GetBookTicker(ctx, order.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("c.HttpClient.GetBookTicker: %w", err)
+	}
+
+	// the price the order takes at, zero if it rests in the book
+	price := takePrice(order, ticker)
+
+	if order.Type == models.OrderTypeLimitMaker && !price.IsZero() {
+		// binance rejects it the same way
+		return nil, &clients.Error{Msg: "Order would immediately match and take"}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastID--
+
+	order.OrderID = c.lastID
+	order.OrderListId = -1
+	order.ClientOrderID = fmt.Sprintf("dryrun%d", -order.OrderID)
+	order.Status = models.OrderStatusTypeNew
+	order.Time = c.now().UnixMilli()
+	order.UpdateTime = order.Time
+	order.IsWorking = true
+
+	switch {
+	case !price.IsZero():
+		c.fill(symbol, order, price)
+	case order.Type == models.OrderTypeMarket:
+		return nil, fmt.Errorf("no %s price in the book of %s", order.Side, order.Symbol)
+	case order.TimeInForce == models.TimeInForceTypeIOC || order.TimeInForce == models.TimeInForceTypeFOK:
+		order.Status = models.OrderStatusTypeExpired
+		order.IsWorking = false
+	default:
+		c.lock(symbol, order)
+	}
+
+	c.orders[order.OrderID] = order
+
+	c.z.Infow(
+		"dry run order, not sent to the exchange",
+		"symbol", order.Symbol,
+		"orderId", order.OrderID,
+		"side", order.Side,
+		"type", order.Type,
+		"timeInForce", order.TimeInForce,
+		"price", order.Price,
+		"quantity", order.OrigQuantity,
+		"icebergQuantity", order.IcebergQuantity,
+		"quoteQuantity", order.Price.Mul(order.OrigQuantity),
+		"status", order.Status,
+	)
+
+	copied := *order
+
+	return &copied, nil
+}
+
+// refresh fills the resting orders of the symbol the live book has crossed
+func (c *Client) refresh(ctx context.Context, symbol string) error {
+	c.mu.Lock()
+	working := false
+	for _, order := range c.orders {
+		working = working || (order.Symbol == symbol && order.IsWorking)
+	}
+	c.mu.Unlock()
+
+	if !working {
+		return nil
+	}
+
+	ticker, err := c.HttpClient.GetBookTicker(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("c.HttpClient.GetBookTicker: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, order := range c.orders {
+		if order.Symbol != symbol || !order.IsWorking || takePrice(order, ticker).IsZero() {
+			continue
+		}
+
+		c.unlock(order)
+		c.fill(c.symbols[symbol], order, order.Price)
+
+		c.z.Infow(
+			"dry run order filled",
+			"symbol", symbol,
+			"orderId", order.OrderID,
+			"side", order.Side,
+			"price", order.Price,
+			"quantity", order.ExecutedQuantity,
+		)
+	}
+
+	return nil
+}
+
+// symbol returns the symbol with its assets, cached after the first request
+func (c *Client) symbol(ctx context.Context, name string) (*models.Symbol, error) {
+	c.mu.Lock()
+	symbol, ok := c.symbols[name]
+	c.mu.Unlock()
+
+	if ok {
+		return symbol, nil
+	}
+
+	symbols, err := c.HttpClient.GetSymbols(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("c.HttpClient.GetSymbols: %w", err)
+	}
+
+	for _, s := range symbols {
+		if s.Symbol == name {
+			c.mu.Lock()
+			c.symbols[name] = s
+			c.mu.Unlock()
+
+			return s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown symbol %s", name)
+}
+
+// fill executes the whole order at price and moves the balances
+func (c *Client) fill(symbol *models.Symbol, order *models.Order, price decimal.Decimal) {
+	quote := price.Mul(order.OrigQuantity)
+
+	order.Price = price
+	order.ExecutedQuantity = order.OrigQuantity
+	order.CummulativeQuoteQuantity = quote
+	order.Status = models.OrderStatusTypeFilled
+	order.UpdateTime = c.now().UnixMilli()
+	order.IsWorking = false
+
+	if order.Side == models.SideTypeBuy {
+		c.balance(symbol.BaseAsset).free = c.balance(symbol.BaseAsset).free.Add(order.OrigQuantity)
+		c.balance(symbol.QuoteAsset).free = c.balance(symbol.QuoteAsset).free.Sub(quote)
+	} else {
+		c.balance(symbol.BaseAsset).free = c.balance(symbol.BaseAsset).free.Sub(order.OrigQuantity)
+		c.balance(symbol.QuoteAsset).free = c.balance(symbol.QuoteAsset).free.Add(quote)
+	}
+}
+
+// lock moves the funds of a resting order from free to locked
+func (c *Client) lock(symbol *models.Symbol, order *models.Order) {
+	coin, amount := lockedFunds(symbol, order)
+
+	b := c.balance(coin)
+	b.free = b.free.Sub(amount)
+	b.locked = b.locked.Add(amount)
+}
+
+// unlock returns the funds of a resting order to free
+func (c *Client) unlock(order *models.Order) {
+	coin, amount := lockedFunds(c.symbols[order.Symbol], order)
+
+	b := c.balance(coin)
+	b.free = b.free.Add(amount)
+	b.locked = b.locked.Sub(amount)
+}
+
+func (c *Client) balance(coin string) *balance {
+	b, ok := c.balances[coin]
+	if !ok {
+		b = &balance{}
+		c.balances[coin] = b
+	}
+
+	return b
+}
+
+// lockedFunds returns the coin and the amount a resting order locks: the
+// quote coin for a buy, the base one for a sell
+func lockedFunds(symbol *models.Symbol, order *models.Order) (string, decimal.Decimal) {
+	if order.Side == models.SideTypeBuy {
+		return symbol.QuoteAsset, order.Price.Mul(order.OrigQuantity)
+	}
+
+	return symbol.BaseAsset, order.OrigQuantity
+}
+
+// takePrice returns the price the order matches the book at, zero if it
+// doesn't match: the best opposite price for a market order or a limit one
+// crossing the book
+func takePrice(order *models.Order, ticker *models.BookTicker) decimal.Decimal {
+	if order.Side == models.SideTypeBuy {
+		if ticker.AskPrice.IsPositive() && (order.Type == models.OrderTypeMarket || order.Price.GreaterThanOrEqual(ticker.AskPrice)) {
+			return ticker.AskPrice
+		}
+
+		return decimal.Zero
+	}
+
+	if ticker.BidPrice.IsPositive() && (order.Type == models.OrderTypeMarket || order.Price.LessThanOrEqual(ticker.BidPrice)) {
+		return ticker.BidPrice
+	}
+
+	return decimal.Zero
+}
+
+func unknownOrder(orderId int64) error {
+	return &clients.Error{Kind: clients.ErrUnknownOrder, Msg: fmt.Sprintf("unknown dry run order %d", orderId)}
+}
@@ -0,0 +1,219 @@
+package dryrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+const testSymbol = "BTCUSDT"
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func newTestClient(t *testing.T) (*Client, *mocks.HttpClient) {
+	t.Helper()
+
+	m := mocks.NewHttpClient()
+	m.SetSymbol(&models.Symbol{Symbol: testSymbol, Status: "TRADING", BaseAsset: "BTC", QuoteAsset: "USDT"})
+	m.SetBookTicker(testSymbol, "19990", "20010")
+	m.SetBalance("USDT", "1000", "0")
+
+	return NewClient(m), m
+}
+
+func assertBalance(t *testing.T, c *Client, coin, wantFree, wantLocked string) {
+	t.Helper()
+
+	free, locked, err := c.GetBalance(context.Background(), coin)
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+
+	if !free.Equal(d(wantFree)) || !locked.Equal(d(wantLocked)) {
+		t.Errorf("%s balance = %s, %s, want %s, %s", coin, free, locked, wantFree, wantLocked)
+	}
+}
+
+func TestClient_MarketOrders(t *testing.T) {
+	c, m := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewMarketBuyOrder(ctx, testSymbol, d("0.01"))
+	if err != nil {
+		t.Fatalf("NewMarketBuyOrder() error = %v", err)
+	}
+
+	if order.OrderID >= 0 || order.Status != models.OrderStatusTypeFilled || !order.Price.Equal(d("20010")) {
+		t.Errorf("order = %+v, want filled at the ask with a negative id", order)
+	}
+
+	if !order.CummulativeQuoteQuantity.Equal(d("200.1")) {
+		t.Errorf("quote quantity = %s, want 200.1", order.CummulativeQuoteQuantity)
+	}
+
+	assertBalance(t, c, "USDT", "799.9", "0")
+	assertBalance(t, c, "BTC", "0.01", "0")
+
+	if _, err := c.NewMarketSellOrder(ctx, testSymbol, d("0.01")); err != nil {
+		t.Fatalf("NewMarketSellOrder() error = %v", err)
+	}
+
+	assertBalance(t, c, "USDT", "999.8", "0")
+	assertBalance(t, c, "BTC", "0", "0")
+
+	for _, method := range []string{"NewOrder", "NewMarketBuyOrder", "NewMarketSellOrder"} {
+		if calls := m.CallsTo(method); len(calls) != 0 {
+			t.Errorf("%s sent to the exchange %d times", method, len(calls))
+		}
+	}
+}
+
+func TestClient_LimitOrders(t *testing.T) {
+	c, m := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewLimitBuyOrder(ctx, testSymbol, d("19900"), d("0.01"))
+	if err != nil {
+		t.Fatalf("NewLimitBuyOrder() error = %v", err)
+	}
+
+	if order.Status != models.OrderStatusTypeNew {
+		t.Fatalf("status = %s, want NEW below the ask", order.Status)
+	}
+
+	assertBalance(t, c, "USDT", "801", "199")
+
+	open, err := c.GetOpenOrders(ctx, testSymbol)
+	if err != nil || len(open) != 1 || open[0].OrderID != order.OrderID {
+		t.Fatalf("GetOpenOrders() = %v, %v, want the resting order", open, err)
+	}
+
+	m.SetBookTicker(testSymbol, "19880", "19890")
+
+	got, err := c.GetOrder(ctx, testSymbol, order.OrderID)
+	if err != nil {
+		t.Fatalf("GetOrder() error = %v", err)
+	}
+
+	if got.Status != models.OrderStatusTypeFilled || !got.Price.Equal(d("19900")) {
+		t.Errorf("order = %+v, want filled at its price once the ask crossed it", got)
+	}
+
+	assertBalance(t, c, "USDT", "801", "0")
+	assertBalance(t, c, "BTC", "0.01", "0")
+
+	// a limit order crossing the book takes at the best price
+	taker, err := c.NewLimitSellOrder(ctx, testSymbol, d("19000"), d("0.01"))
+	if err != nil {
+		t.Fatalf("NewLimitSellOrder() error = %v", err)
+	}
+
+	if taker.Status != models.OrderStatusTypeFilled || !taker.Price.Equal(d("19880")) {
+		t.Errorf("order = %+v, want filled at the bid", taker)
+	}
+}
+
+func TestClient_TimeInForce(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewOrder(ctx, testSymbol, models.SideTypeBuy, models.OrderTypeLimit, models.TimeInForceTypeIOC, d("19900"), d("0.01"))
+	if err != nil {
+		t.Fatalf("NewOrder() error = %v", err)
+	}
+
+	if order.Status != models.OrderStatusTypeExpired {
+		t.Errorf("IOC status = %s, want EXPIRED", order.Status)
+	}
+
+	if _, err := c.NewIcebergOrder(ctx, testSymbol, models.SideTypeBuy, models.OrderTypeLimitMaker, d("20010"), d("1"), d("0.1")); err == nil {
+		t.Error("NewIcebergOrder() LIMIT_MAKER taking the ask returned no error")
+	}
+
+	iceberg, err := c.NewIcebergOrder(ctx, testSymbol, models.SideTypeBuy, models.OrderTypeLimitMaker, d("19000"), d("0.02"), d("0.01"))
+	if err != nil {
+		t.Fatalf("NewIcebergOrder() error = %v", err)
+	}
+
+	if iceberg.Status != models.OrderStatusTypeNew || !iceberg.IcebergQuantity.Equal(d("0.01")) {
+		t.Errorf("order = %+v, want a resting iceberg", iceberg)
+	}
+}
+
+func TestClient_CloseOrder(t *testing.T) {
+	c, m := newTestClient(t)
+	ctx := context.Background()
+
+	order, err := c.NewLimitBuyOrder(ctx, testSymbol, d("19900"), d("0.01"))
+	if err != nil {
+		t.Fatalf("NewLimitBuyOrder() error = %v", err)
+	}
+
+	if err := c.CloseOrder(ctx, testSymbol, order.OrderID); err != nil {
+		t.Fatalf("CloseOrder() error = %v", err)
+	}
+
+	assertBalance(t, c, "USDT", "1000", "0")
+
+	if open, _ := c.GetOpenOrders(ctx, testSymbol); len(open) != 0 {
+		t.Errorf("open orders = %v, want none", open)
+	}
+
+	if err := c.CloseOrder(ctx, testSymbol, order.OrderID); !errors.Is(err, clients.ErrUnknownOrder) {
+		t.Errorf("CloseOrder() twice error = %v, want ErrUnknownOrder", err)
+	}
+
+	if err := c.CloseOrder(ctx, testSymbol, 42); !errors.Is(err, clients.ErrUnknownOrder) {
+		t.Errorf("CloseOrder() of a real order error = %v, want ErrUnknownOrder", err)
+	}
+
+	if calls := m.CallsTo("CloseOrder"); len(calls) != 0 {
+		t.Errorf("CloseOrder sent to the exchange %d times", len(calls))
+	}
+}
+
+func TestClient_GetAssets(t *testing.T) {
+	c, _ := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := c.NewMarketBuyOrder(ctx, testSymbol, d("0.01")); err != nil {
+		t.Fatalf("NewMarketBuyOrder() error = %v", err)
+	}
+
+	assets, err := c.GetAssets(ctx)
+	if err != nil {
+		t.Fatalf("GetAssets() error = %v", err)
+	}
+
+	balances := make(map[string]decimal.Decimal)
+	for _, a := range assets {
+		balances[a.Coin] = a.Free
+	}
+
+	if !balances["USDT"].Equal(d("799.9")) || !balances["BTC"].Equal(d("0.01")) {
+		t.Errorf("assets = %+v, want the bought BTC and the spent USDT", assets)
+	}
+}
+
+func TestClient_PassesReads(t *testing.T) {
+	c, m := newTestClient(t)
+	m.SetPrice(testSymbol, "20000")
+
+	price, err := c.GetPrice(context.Background(), testSymbol)
+	if err != nil || !price.Equal(d("20000")) {
+		t.Errorf("GetPrice() = %s, %v, want the live price", price, err)
+	}
+
+	m.Fail("GetBookTicker", clients.ErrNetwork, 1)
+
+	if _, err := c.NewMarketBuyOrder(context.Background(), testSymbol, d("0.01")); !errors.Is(err, clients.ErrNetwork) {
+		t.Errorf("NewMarketBuyOrder() error = %v, want the book ticker one", err)
+	}
+}
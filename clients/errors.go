@@ -0,0 +1,51 @@
+package clients
+
+import "errors"
+
+// Kinds of exchange errors, match them with errors.Is
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrFilterFailure       = errors.New("filter failure")
+	ErrUnknownOrder        = errors.New("unknown order")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrTimestamp           = errors.New("timestamp outside of the recv window")
+	ErrNetwork             = errors.New("network error")
+	ErrNotImplemented      = errors.New("not implemented") // the client of the exchange can't make the call yet
+)
+
+// Error is an error of an exchange with its code kept
+type Error struct {
+	Kind error  // one of the Err* kinds, nil if the error isn't classified
+	Code int64  // code of the exchange, 0 if the exchange didn't answer
+	Msg  string // message of the exchange or of the cause
+	Err  error  // cause
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is match the kind of the error
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// Code returns the exchange code of the error, 0 if it has none
+func Code(err error) int64 {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return 0
+}
+
+// Retryable reports whether a safe request failing with err may be sent again:
+// the exchange wasn't reached or asked to slow down
+func Retryable(err error) bool {
+	return errors.Is(err, ErrNetwork) || errors.Is(err, ErrRateLimited)
+}
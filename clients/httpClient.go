@@ -4,12 +4,16 @@ import (
 	"context"
 
 	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
 )
 
 type HttpClient interface {
 	Ping(ctx context.Context) error
-	GetPrice(ctx context.Context, symbol string) (float64, error)
-	GetBalance(ctx context.Context, coin string) (float64, float64, error)
+	GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error)
+	GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error)
+	GetBookTickers(ctx context.Context) ([]*models.BookTicker, error)
+	GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error)
+	GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error)
 	GetAssets(ctx context.Context) ([]models.Asset, error)
 	GetKlines(ctx context.Context, symbol, interval string) ([]*models.Kline, error)
 	GetKlinesCloses(ctx context.Context, symbol, interval string) ([]float64, error)
@@ -21,12 +25,20 @@ type HttpClient interface {
 		sideType models.SideType,
 		orderType models.OrderType,
 		tif models.TimeInForceType,
-		price, quantity float64,
-	) error
-	NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity float64) error
-	NewLimitSellOrder(ctx context.Context, symbol string, price, quantity float64) error
-	NewMarketBuyOrder(ctx context.Context, symbol string, quantity float64) error
-	NewMarketSellOrder(ctx context.Context, symbol string, quantity float64) error
+		price, quantity decimal.Decimal,
+	) (*models.Order, error)
+	NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error)
+	NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error)
+	NewIcebergOrder(
+		ctx context.Context,
+		symbol string,
+		sideType models.SideType,
+		orderType models.OrderType,
+		price, quantity, icebergQuantity decimal.Decimal,
+	) (*models.Order, error)
+	NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error)
+	NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error)
+	GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error)
 	GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error)
 	CloseOrder(ctx context.Context, symbol string, orderId int64) error
 }
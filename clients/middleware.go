@@ -0,0 +1,250 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+// Middleware wraps a client to add behavior around its calls, e.g. logging,
+// metrics or retries
+type Middleware func(next HttpClient) HttpClient
+
+// Chain wraps the client with the middlewares, the first one is the outermost
+// and sees a call first
+func Chain(c HttpClient, middlewares ...Middleware) HttpClient {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		c = middlewares[i](c)
+	}
+
+	return c
+}
+
+// Call is a call of an HttpClient method
+type Call struct {
+	Method string
+	Args   []interface{} // the arguments after the context
+}
+
+// Interceptor runs around every call of a client, next makes the call with
+// the context given to it and returns its error
+type Interceptor func(ctx context.Context, call Call, next func(ctx context.Context) error) error
+
+// Intercept returns a middleware running the interceptor around every call,
+// for the concerns that don't depend on the method
+func Intercept(i Interceptor) Middleware {
+	return func(next HttpClient) HttpClient {
+		return &intercepted{next: next, i: i}
+	}
+}
+
+type intercepted struct {
+	next HttpClient
+	i    Interceptor
+}
+
+func (c *intercepted) Ping(ctx context.Context) error {
+	return c.i(ctx, Call{Method: "Ping"}, func(ctx context.Context) error {
+		return c.next.Ping(ctx)
+	})
+}
+
+func (c *intercepted) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	var price decimal.Decimal
+
+	err := c.i(ctx, Call{Method: "GetPrice", Args: []interface{}{symbol}}, func(ctx context.Context) (err error) {
+		price, err = c.next.GetPrice(ctx, symbol)
+		return err
+	})
+
+	return price, err
+}
+
+func (c *intercepted) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	var ticker *models.BookTicker
+
+	err := c.i(ctx, Call{Method: "GetBookTicker", Args: []interface{}{symbol}}, func(ctx context.Context) (err error) {
+		ticker, err = c.next.GetBookTicker(ctx, symbol)
+		return err
+	})
+
+	return ticker, err
+}
+
+func (c *intercepted) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	var tickers []*models.BookTicker
+
+	err := c.i(ctx, Call{Method: "GetBookTickers"}, func(ctx context.Context) (err error) {
+		tickers, err = c.next.GetBookTickers(ctx)
+		return err
+	})
+
+	return tickers, err
+}
+
+func (c *intercepted) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	var result []*models.Symbol
+
+	err := c.i(ctx, Call{Method: "GetSymbols", Args: []interface{}{symbols}}, func(ctx context.Context) (err error) {
+		result, err = c.next.GetSymbols(ctx, symbols...)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *intercepted) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	var free, locked decimal.Decimal
+
+	err := c.i(ctx, Call{Method: "GetBalance", Args: []interface{}{coin}}, func(ctx context.Context) (err error) {
+		free, locked, err = c.next.GetBalance(ctx, coin)
+		return err
+	})
+
+	return free, locked, err
+}
+
+func (c *intercepted) GetAssets(ctx context.Context) ([]models.Asset, error) {
+	var assets []models.Asset
+
+	err := c.i(ctx, Call{Method: "GetAssets"}, func(ctx context.Context) (err error) {
+		assets, err = c.next.GetAssets(ctx)
+		return err
+	})
+
+	return assets, err
+}
+
+func (c *intercepted) GetKlines(ctx context.Context, symbol, interval string) ([]*models.Kline, error) {
+	var klines []*models.Kline
+
+	err := c.i(ctx, Call{Method: "GetKlines", Args: []interface{}{symbol, interval}}, func(ctx context.Context) (err error) {
+		klines, err = c.next.GetKlines(ctx, symbol, interval)
+		return err
+	})
+
+	return klines, err
+}
+
+func (c *intercepted) GetKlinesCloses(ctx context.Context, symbol, interval string) ([]float64, error) {
+	var closes []float64
+
+	err := c.i(ctx, Call{Method: "GetKlinesCloses", Args: []interface{}{symbol, interval}}, func(ctx context.Context) (err error) {
+		closes, err = c.next.GetKlinesCloses(ctx, symbol, interval)
+		return err
+	})
+
+	return closes, err
+}
+
+func (c *intercepted) NewOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	tif models.TimeInForceType,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	var order *models.Order
+
+	call := Call{Method: "NewOrder", Args: []interface{}{symbol, sideType, orderType, tif, price, quantity}}
+
+	err := c.i(ctx, call, func(ctx context.Context) (err error) {
+		order, err = c.next.NewOrder(ctx, symbol, sideType, orderType, tif, price, quantity)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	var order *models.Order
+
+	err := c.i(ctx, Call{Method: "NewLimitBuyOrder", Args: []interface{}{symbol, price, quantity}}, func(ctx context.Context) (err error) {
+		order, err = c.next.NewLimitBuyOrder(ctx, symbol, price, quantity)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	var order *models.Order
+
+	err := c.i(ctx, Call{Method: "NewLimitSellOrder", Args: []interface{}{symbol, price, quantity}}, func(ctx context.Context) (err error) {
+		order, err = c.next.NewLimitSellOrder(ctx, symbol, price, quantity)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	var order *models.Order
+
+	call := Call{Method: "NewIcebergOrder", Args: []interface{}{symbol, sideType, orderType, price, quantity, icebergQuantity}}
+
+	err := c.i(ctx, call, func(ctx context.Context) (err error) {
+		order, err = c.next.NewIcebergOrder(ctx, symbol, sideType, orderType, price, quantity, icebergQuantity)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	var order *models.Order
+
+	err := c.i(ctx, Call{Method: "NewMarketBuyOrder", Args: []interface{}{symbol, quantity}}, func(ctx context.Context) (err error) {
+		order, err = c.next.NewMarketBuyOrder(ctx, symbol, quantity)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	var order *models.Order
+
+	err := c.i(ctx, Call{Method: "NewMarketSellOrder", Args: []interface{}{symbol, quantity}}, func(ctx context.Context) (err error) {
+		order, err = c.next.NewMarketSellOrder(ctx, symbol, quantity)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	var order *models.Order
+
+	err := c.i(ctx, Call{Method: "GetOrder", Args: []interface{}{symbol, orderId}}, func(ctx context.Context) (err error) {
+		order, err = c.next.GetOrder(ctx, symbol, orderId)
+		return err
+	})
+
+	return order, err
+}
+
+func (c *intercepted) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
+	var orders []*models.Order
+
+	err := c.i(ctx, Call{Method: "GetOpenOrders", Args: []interface{}{symbol}}, func(ctx context.Context) (err error) {
+		orders, err = c.next.GetOpenOrders(ctx, symbol)
+		return err
+	})
+
+	return orders, err
+}
+
+func (c *intercepted) CloseOrder(ctx context.Context, symbol string, orderId int64) error {
+	return c.i(ctx, Call{Method: "CloseOrder", Args: []interface{}{symbol, orderId}}, func(ctx context.Context) error {
+		return c.next.CloseOrder(ctx, symbol, orderId)
+	})
+}
@@ -0,0 +1,85 @@
+// Package middleware contains clients.Middleware for the concerns shared by
+// every client: logging and metrics of the calls.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/metrics"
+	"go.uber.org/zap"
+)
+
+var kindResults = []struct {
+	kind   error
+	result string
+}{
+	{clients.ErrInsufficientBalance, "insufficient_balance"},
+	{clients.ErrFilterFailure, "filter_failure"},
+	{clients.ErrUnknownOrder, "unknown_order"},
+	{clients.ErrRateLimited, "rate_limited"},
+	{clients.ErrTimestamp, "timestamp"},
+	{clients.ErrNetwork, "network"},
+	{clients.ErrNotImplemented, "not_implemented"},
+}
+
+// Logging logs every call at debug level and the failed ones as warnings
+func Logging(z *zap.SugaredLogger) clients.Middleware {
+	return clients.Intercept(func(ctx context.Context, call clients.Call, next func(ctx context.Context) error) error {
+		started := time.Now()
+		err := next(ctx)
+
+		if err != nil {
+			z.Warnw(
+				"client call failed",
+				"method", call.Method,
+				"args", call.Args,
+				"duration", time.Since(started),
+				"error", err.Error(),
+			)
+
+			return err
+		}
+
+		z.Debugw(
+			"client call",
+			"method", call.Method,
+			"args", call.Args,
+			"duration", time.Since(started),
+		)
+
+		return nil
+	})
+}
+
+// Metrics counts the calls by their result and observes their duration, the
+// labels tell the exchange and the strategy making the calls
+func Metrics(exchange, strategy string) clients.Middleware {
+	return clients.Intercept(func(ctx context.Context, call clients.Call, next func(ctx context.Context) error) error {
+		started := time.Now()
+		err := next(ctx)
+
+		metrics.ClientCalls.WithLabelValues(exchange, strategy, call.Method, Result(err)).Inc()
+		metrics.ClientCallDuration.WithLabelValues(exchange, call.Method).Observe(time.Since(started).Seconds())
+
+		return err
+	})
+}
+
+// Result is the metrics label of a call result: ok, the kind of the error or
+// error if it has none
+func Result(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	for _, kr := range kindResults {
+		if errors.Is(err, kr.kind) {
+			return kr.result
+		}
+	}
+
+	return "error"
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogging(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	m := mocks.NewHttpClient()
+	m.SetPrice("BTCUSDT", "20000")
+	m.Fail("GetBookTicker", clients.ErrNetwork, 1)
+
+	c := Logging(zap.New(core).Sugar())(m)
+	ctx := context.Background()
+
+	if _, err := c.GetPrice(ctx, "BTCUSDT"); err != nil {
+		t.Fatalf("GetPrice() error = %v", err)
+	}
+
+	if _, err := c.GetBookTicker(ctx, "BTCUSDT"); err == nil {
+		t.Fatal("GetBookTicker() returned no error")
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+
+	if e := entries[0]; e.Level != zapcore.DebugLevel || e.ContextMap()["method"] != "GetPrice" {
+		t.Errorf("first entry = %s %v, want a debug one for GetPrice", e.Level, e.ContextMap())
+	}
+
+	if e := entries[1]; e.Level != zapcore.WarnLevel || e.ContextMap()["error"] != clients.ErrNetwork.Error() {
+		t.Errorf("second entry = %s %v, want a warning with the error", e.Level, e.ContextMap())
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	m := mocks.NewHttpClient()
+	m.SetPrice("BTCUSDT", "20000")
+	m.Fail("GetPrice", &clients.Error{Kind: clients.ErrRateLimited, Msg: "Too many requests"}, 1)
+
+	c := Metrics("test", "metrics")(m)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, _ = c.GetPrice(ctx, "BTCUSDT")
+	}
+
+	if got := testutil.ToFloat64(metrics.ClientCalls.WithLabelValues("test", "metrics", "GetPrice", "ok")); got != 2 {
+		t.Errorf("ok calls = %v, want 2", got)
+	}
+
+	if got := testutil.ToFloat64(metrics.ClientCalls.WithLabelValues("test", "metrics", "GetPrice", "rate_limited")); got != 1 {
+		t.Errorf("rate limited calls = %v, want 1", got)
+	}
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: "ok"},
+		{err: &clients.Error{Kind: clients.ErrInsufficientBalance}, want: "insufficient_balance"},
+		{err: fmt.Errorf("c.NewOrder: %w", &clients.Error{Kind: clients.ErrFilterFailure}), want: "filter_failure"},
+		{err: &clients.Error{Kind: clients.ErrUnknownOrder}, want: "unknown_order"},
+		{err: &clients.Error{Kind: clients.ErrTimestamp}, want: "timestamp"},
+		{err: &clients.Error{Code: -1100}, want: "error"},
+		{err: errors.New("failed"), want: "error"},
+	}
+
+	for _, tt := range tests {
+		if got := Result(tt.err); got != tt.want {
+			t.Errorf("Result(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package clients_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/shopspring/decimal"
+)
+
+type ctxKey struct{}
+
+// recording returns a middleware adding name to the calls it sees
+func recording(name string, seen *[]string) clients.Middleware {
+	return clients.Intercept(func(ctx context.Context, call clients.Call, next func(context.Context) error) error {
+		*seen = append(*seen, name+" "+call.Method)
+		return next(ctx)
+	})
+}
+
+func TestChain(t *testing.T) {
+	m := mocks.NewHttpClient()
+	m.SetPrice("BTCUSDT", "20000")
+
+	var seen []string
+
+	c := clients.Chain(m, recording("outer", &seen), recording("inner", &seen))
+
+	if _, err := c.GetPrice(context.Background(), "BTCUSDT"); err != nil {
+		t.Fatalf("GetPrice() error = %v", err)
+	}
+
+	if want := []string{"outer GetPrice", "inner GetPrice"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("calls = %v, want %v", seen, want)
+	}
+
+	if clients.Chain(m) != m {
+		t.Error("Chain() without middlewares wrapped the client")
+	}
+}
+
+// TestIntercept calls every method of the interface through the interceptor
+func TestIntercept(t *testing.T) {
+	m := mocks.NewHttpClient()
+
+	var calls []clients.Call
+
+	c := clients.Intercept(func(ctx context.Context, call clients.Call, next func(context.Context) error) error {
+		calls = append(calls, call)
+		return next(context.WithValue(ctx, ctxKey{}, call.Method))
+	})(m)
+
+	typ := reflect.TypeOf((*clients.HttpClient)(nil)).Elem()
+	value := reflect.ValueOf(c)
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		fn := value.MethodByName(method.Name)
+
+		args := []reflect.Value{reflect.ValueOf(context.Background())}
+		for j := 1; j < fn.Type().NumIn(); j++ {
+			if fn.Type().IsVariadic() && j == fn.Type().NumIn()-1 {
+				break
+			}
+
+			args = append(args, reflect.Zero(fn.Type().In(j)))
+		}
+
+		fn.Call(args)
+
+		if len(calls) != i+1 || calls[i].Method != method.Name {
+			t.Fatalf("%s wasn't intercepted, calls = %v", method.Name, calls)
+		}
+
+		if got := m.CallsTo(method.Name); len(got) != 1 {
+			t.Errorf("%s reached the client %d times, want 1", method.Name, len(got))
+		}
+	}
+}
+
+func TestIntercept_Errors(t *testing.T) {
+	m := mocks.NewHttpClient()
+	m.SetPrice("BTCUSDT", "20000")
+
+	failure := errors.New("blocked")
+
+	blocking := clients.Intercept(func(ctx context.Context, call clients.Call, next func(context.Context) error) error {
+		if call.Method == "NewMarketBuyOrder" {
+			return failure
+		}
+
+		return next(ctx)
+	})
+
+	c := blocking(m)
+
+	if order, err := c.NewMarketBuyOrder(context.Background(), "BTCUSDT", d("1")); !errors.Is(err, failure) || order != nil {
+		t.Errorf("NewMarketBuyOrder() = %v, %v, want the interceptor error", order, err)
+	}
+
+	if calls := m.CallsTo("NewMarketBuyOrder"); len(calls) != 0 {
+		t.Errorf("blocked call reached the client %d times", len(calls))
+	}
+
+	m.Fail("GetPrice", failure, 1)
+
+	if _, err := c.GetPrice(context.Background(), "BTCUSDT"); !errors.Is(err, failure) {
+		t.Errorf("GetPrice() error = %v, want the client one", err)
+	}
+}
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
@@ -0,0 +1,717 @@
+// Package mocks contains in-memory implementations of the clients interfaces for tests.
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+var _ clients.HttpClient = &HttpClient{}
+
+// Call is a recorded HttpClient method call
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+type balance struct {
+	free   decimal.Decimal
+	locked decimal.Decimal
+}
+
+type failure struct {
+	err   error
+	times int // how many calls fail, 0 means every call
+}
+
+// HttpClient is a deterministic in-memory clients.HttpClient. Prices, balances
+// and klines are what the test sets, limit orders stay open until Fill or
+// SetPrice crossing them, market orders are filled at once at the current
+// price, IOC and FOK limit orders are filled at once if they take and expire
+// otherwise. Balances only change through SetBalance.
+type HttpClient struct {
+	mu       sync.Mutex
+	clock    int64
+	nextID   int64
+	prices   map[string]decimal.Decimal
+	tickers  map[string]*models.BookTicker
+	symbols  map[string]*models.Symbol
+	balances map[string]*balance
+	klines   map[string][]*models.Kline
+	orders   map[int64]*models.Order
+	failures map[string]*failure
+	calls    []Call
+}
+
+func NewHttpClient() *HttpClient {
+	return &HttpClient{
+		prices:   make(map[string]decimal.Decimal),
+		tickers:  make(map[string]*models.BookTicker),
+		symbols:  make(map[string]*models.Symbol),
+		balances: make(map[string]*balance),
+		klines:   make(map[string][]*models.Kline),
+		orders:   make(map[int64]*models.Order),
+		failures: make(map[string]*failure),
+	}
+}
+
+// SetPrice sets the price of a symbol and fills the limit orders it crosses,
+// the best bid and ask become the price until SetBookTicker
+func (c *HttpClient) SetPrice(symbol, price string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prices[symbol] = decimal.RequireFromString(price)
+	delete(c.tickers, symbol)
+
+	for _, order := range byDistance(c.sortedOrders()) {
+		if order.Symbol == symbol && isOpen(order) && crosses(order, c.prices[symbol]) {
+			c.fill(order)
+		}
+	}
+}
+
+// SetBookTicker sets the best bid and ask of a symbol
+func (c *HttpClient) SetBookTicker(symbol, bid, ask string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tickers[symbol] = &models.BookTicker{
+		Symbol:      symbol,
+		BidPrice:    decimal.RequireFromString(bid),
+		BidQuantity: decimal.NewFromInt(1),
+		AskPrice:    decimal.RequireFromString(ask),
+		AskQuantity: decimal.NewFromInt(1),
+	}
+}
+
+// SetSymbol sets the trading rules of a symbol returned by GetSymbols
+func (c *HttpClient) SetSymbol(symbol *models.Symbol) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	copied := *symbol
+	c.symbols[symbol.Symbol] = &copied
+}
+
+func (c *HttpClient) SetBalance(coin, free, locked string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.balances[coin] = &balance{
+		free:   decimal.RequireFromString(free),
+		locked: decimal.RequireFromString(locked),
+	}
+}
+
+func (c *HttpClient) SetKlines(symbol, interval string, klines []*models.Kline) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.klines[symbol+interval] = klines
+}
+
+// SetCloses sets klines built from close prices, one minute apart
+func (c *HttpClient) SetCloses(symbol, interval string, closes []float64) {
+	klines := make([]*models.Kline, len(closes))
+	minute := time.Minute.Milliseconds()
+
+	for i, cl := range closes {
+		klines[i] = &models.Kline{
+			OpenTime:  int64(i) * minute,
+			Open:      cl,
+			High:      cl,
+			Low:       cl,
+			Close:     cl,
+			Volume:    1,
+			CloseTime: int64(i+1)*minute - 1,
+		}
+	}
+
+	c.SetKlines(symbol, interval, klines)
+}
+
+// Fail makes the next times calls of method return err,
+// times <= 0 makes every call fail until ClearFailures is called
+func (c *HttpClient) Fail(method string, err error, times int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures[method] = &failure{err: err, times: times}
+}
+
+func (c *HttpClient) ClearFailures() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures = make(map[string]*failure)
+}
+
+// Fill fills an open order at its price
+func (c *HttpClient) Fill(orderID int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	order, ok := c.orders[orderID]
+	if !ok || !isOpen(order) {
+		return fmt.Errorf("order %d is not open", orderID)
+	}
+
+	c.fill(order)
+
+	return nil
+}
+
+// Orders returns every order ever placed sorted by id
+func (c *HttpClient) Orders() []*models.Order {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return copyOrders(c.sortedOrders())
+}
+
+// OpenOrders returns open orders of a symbol sorted by price
+func (c *HttpClient) OpenOrders(symbol string) []*models.Order {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.openOrders(symbol)
+}
+
+// Calls returns all recorded calls
+func (c *HttpClient) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Call(nil), c.calls...)
+}
+
+// CallsTo returns recorded calls of a method
+func (c *HttpClient) CallsTo(method string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var calls []Call
+
+	for _, call := range c.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+func (c *HttpClient) ResetCalls() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = nil
+}
+
+func (c *HttpClient) Ping(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.record("Ping")
+}
+
+func (c *HttpClient) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetPrice", symbol); err != nil {
+		return decimal.Zero, err
+	}
+
+	price, ok := c.prices[symbol]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no price for %s", symbol)
+	}
+
+	return price, nil
+}
+
+func (c *HttpClient) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetBookTicker", symbol); err != nil {
+		return nil, err
+	}
+
+	ticker, ok := c.bookTicker(symbol)
+	if !ok {
+		return nil, fmt.Errorf("no book ticker for %s", symbol)
+	}
+
+	return ticker, nil
+}
+
+func (c *HttpClient) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetBookTickers"); err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[string]struct{}, len(c.prices)+len(c.tickers))
+	for symbol := range c.prices {
+		symbols[symbol] = struct{}{}
+	}
+
+	for symbol := range c.tickers {
+		symbols[symbol] = struct{}{}
+	}
+
+	tickers := make([]*models.BookTicker, 0, len(symbols))
+
+	for symbol := range symbols {
+		ticker, _ := c.bookTicker(symbol)
+		tickers = append(tickers, ticker)
+	}
+
+	sort.Slice(tickers, func(i, j int) bool { return tickers[i].Symbol < tickers[j].Symbol })
+
+	return tickers, nil
+}
+
+func (c *HttpClient) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetSymbols", symbols); err != nil {
+		return nil, err
+	}
+
+	if len(symbols) == 0 {
+		for name := range c.symbols {
+			symbols = append(symbols, name)
+		}
+
+		sort.Strings(symbols)
+	}
+
+	result := make([]*models.Symbol, 0, len(symbols))
+
+	for _, name := range symbols {
+		symbol, ok := c.symbols[name]
+		if !ok {
+			return nil, fmt.Errorf("no symbol %s", name)
+		}
+
+		copied := *symbol
+		result = append(result, &copied)
+	}
+
+	return result, nil
+}
+
+func (c *HttpClient) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetBalance", coin); err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+
+	b, ok := c.balances[coin]
+	if !ok {
+		return decimal.Zero, decimal.Zero, nil
+	}
+
+	return b.free, b.locked, nil
+}
+
+func (c *HttpClient) GetAssets(ctx context.Context) ([]models.Asset, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetAssets"); err != nil {
+		return nil, err
+	}
+
+	assets := make([]models.Asset, 0, len(c.balances))
+	for coin, b := range c.balances {
+		assets = append(assets, models.Asset{Coin: coin, Free: b.free, Locked: b.locked})
+	}
+
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Coin < assets[j].Coin })
+
+	return assets, nil
+}
+
+func (c *HttpClient) GetKlines(ctx context.Context, symbol, interval string) ([]*models.Kline, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetKlines", symbol, interval); err != nil {
+		return nil, err
+	}
+
+	klines := make([]*models.Kline, len(c.klines[symbol+interval]))
+
+	for i, k := range c.klines[symbol+interval] {
+		copied := *k
+		klines[i] = &copied
+	}
+
+	return klines, nil
+}
+
+func (c *HttpClient) GetKlinesCloses(ctx context.Context, symbol, interval string) ([]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetKlinesCloses", symbol, interval); err != nil {
+		return nil, err
+	}
+
+	closes := make([]float64, len(c.klines[symbol+interval]))
+	for i, k := range c.klines[symbol+interval] {
+		closes[i] = k.Close
+	}
+
+	return closes, nil
+}
+
+func (c *HttpClient) NewOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	tif models.TimeInForceType,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("NewOrder", symbol, sideType, orderType, tif, price, quantity); err != nil {
+		return nil, err
+	}
+
+	return c.newOrder(symbol, sideType, orderType, tif, price, quantity)
+}
+
+func (c *HttpClient) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("NewLimitBuyOrder", symbol, price, quantity); err != nil {
+		return nil, err
+	}
+
+	return c.newOrder(symbol, models.SideTypeBuy, models.OrderTypeLimit, models.TimeInForceTypeGTC, price, quantity)
+}
+
+func (c *HttpClient) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("NewLimitSellOrder", symbol, price, quantity); err != nil {
+		return nil, err
+	}
+
+	return c.newOrder(symbol, models.SideTypeSell, models.OrderTypeLimit, models.TimeInForceTypeGTC, price, quantity)
+}
+
+func (c *HttpClient) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("NewIcebergOrder", symbol, sideType, orderType, price, quantity, icebergQuantity); err != nil {
+		return nil, err
+	}
+
+	if orderType != models.OrderTypeLimit && orderType != models.OrderTypeLimitMaker {
+		return nil, fmt.Errorf("iceberg orders must be LIMIT or LIMIT_MAKER, got %s", orderType)
+	}
+
+	if !icebergQuantity.IsPositive() || icebergQuantity.GreaterThanOrEqual(quantity) {
+		return nil, fmt.Errorf("invalid iceberg quantity %s", icebergQuantity)
+	}
+
+	order, err := c.newOrder(symbol, sideType, orderType, models.TimeInForceTypeGTC, price, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	c.orders[order.OrderID].IcebergQuantity = icebergQuantity
+	order.IcebergQuantity = icebergQuantity
+
+	return order, nil
+}
+
+func (c *HttpClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("NewMarketBuyOrder", symbol, quantity); err != nil {
+		return nil, err
+	}
+
+	return c.newOrder(symbol, models.SideTypeBuy, models.OrderTypeMarket, models.TimeInForceTypeGTC, decimal.Zero, quantity)
+}
+
+func (c *HttpClient) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("NewMarketSellOrder", symbol, quantity); err != nil {
+		return nil, err
+	}
+
+	return c.newOrder(symbol, models.SideTypeSell, models.OrderTypeMarket, models.TimeInForceTypeGTC, decimal.Zero, quantity)
+}
+
+func (c *HttpClient) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetOrder", symbol, orderId); err != nil {
+		return nil, err
+	}
+
+	order, ok := c.orders[orderId]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("unknown order %d", orderId)
+	}
+
+	copied := *order
+
+	return &copied, nil
+}
+
+func (c *HttpClient) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("GetOpenOrders", symbol); err != nil {
+		return nil, err
+	}
+
+	return c.openOrders(symbol), nil
+}
+
+func (c *HttpClient) CloseOrder(ctx context.Context, symbol string, orderId int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.record("CloseOrder", symbol, orderId); err != nil {
+		return err
+	}
+
+	order, ok := c.orders[orderId]
+	if !ok || order.Symbol != symbol || !isOpen(order) {
+		return fmt.Errorf("unknown order %d", orderId)
+	}
+
+	c.clock++
+	order.Status = models.OrderStatusTypeCanceled
+	order.UpdateTime = c.clock
+	order.IsWorking = false
+
+	return nil
+}
+
+// record saves the call and returns the scripted failure if there is one
+func (c *HttpClient) record(method string, args ...interface{}) error {
+	c.calls = append(c.calls, Call{Method: method, Args: args})
+
+	f, ok := c.failures[method]
+	if !ok {
+		return nil
+	}
+
+	if f.times > 0 {
+		f.times--
+		if f.times == 0 {
+			delete(c.failures, method)
+		}
+	}
+
+	return f.err
+}
+
+func (c *HttpClient) newOrder(
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	tif models.TimeInForceType,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("invalid quantity %s", quantity)
+	}
+
+	if orderType == models.OrderTypeLimitMaker && c.takes(symbol, sideType, price) {
+		return nil, fmt.Errorf("order would immediately match and take")
+	}
+
+	c.nextID++
+	c.clock++
+
+	order := &models.Order{
+		Symbol:       symbol,
+		OrderID:      c.nextID,
+		Price:        price,
+		OrigQuantity: quantity,
+		Status:       models.OrderStatusTypeNew,
+		TimeInForce:  tif,
+		Type:         orderType,
+		Side:         sideType,
+		Time:         c.clock,
+		UpdateTime:   c.clock,
+		IsWorking:    true,
+	}
+
+	c.orders[order.OrderID] = order
+
+	switch {
+	case orderType == models.OrderTypeMarket:
+		order.Price = c.prices[symbol]
+		c.fill(order)
+	case tif == models.TimeInForceTypeIOC || tif == models.TimeInForceTypeFOK:
+		// the book is a single level deep, an order that takes is filled at once
+		if c.takes(symbol, sideType, price) {
+			c.fill(order)
+		} else {
+			c.clock++
+			order.Status = models.OrderStatusTypeExpired
+			order.UpdateTime = c.clock
+			order.IsWorking = false
+		}
+	}
+
+	copied := *order
+
+	return &copied, nil
+}
+
+// bookTicker returns a copy of the set book ticker or one built from the price
+func (c *HttpClient) bookTicker(symbol string) (*models.BookTicker, bool) {
+	if ticker, ok := c.tickers[symbol]; ok {
+		copied := *ticker
+		return &copied, true
+	}
+
+	price, ok := c.prices[symbol]
+	if !ok {
+		return nil, false
+	}
+
+	return &models.BookTicker{
+		Symbol:      symbol,
+		BidPrice:    price,
+		BidQuantity: decimal.NewFromInt(1),
+		AskPrice:    price,
+		AskQuantity: decimal.NewFromInt(1),
+	}, true
+}
+
+// takes reports whether a limit order at price would match the book at once
+func (c *HttpClient) takes(symbol string, side models.SideType, price decimal.Decimal) bool {
+	bid, ask := c.prices[symbol], c.prices[symbol]
+	if ticker, ok := c.tickers[symbol]; ok {
+		bid, ask = ticker.BidPrice, ticker.AskPrice
+	}
+
+	if side == models.SideTypeBuy {
+		return !ask.IsZero() && price.GreaterThanOrEqual(ask)
+	}
+
+	return !bid.IsZero() && price.LessThanOrEqual(bid)
+}
+
+func (c *HttpClient) fill(order *models.Order) {
+	c.clock++
+	order.Status = models.OrderStatusTypeFilled
+	order.ExecutedQuantity = order.OrigQuantity
+	order.CummulativeQuoteQuantity = order.Price.Mul(order.OrigQuantity)
+	order.UpdateTime = c.clock
+	order.IsWorking = false
+}
+
+func (c *HttpClient) openOrders(symbol string) []*models.Order {
+	var orders []*models.Order
+
+	for _, order := range c.orders {
+		if order.Symbol == symbol && isOpen(order) {
+			orders = append(orders, order)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Price.LessThan(orders[j].Price) })
+
+	return copyOrders(orders)
+}
+
+func (c *HttpClient) sortedOrders() []*models.Order {
+	orders := make([]*models.Order, 0, len(c.orders))
+	for _, order := range c.orders {
+		orders = append(orders, order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderID < orders[j].OrderID })
+
+	return orders
+}
+
+func isOpen(order *models.Order) bool {
+	return order.Status == models.OrderStatusTypeNew || order.Status == models.OrderStatusTypePartiallyFilled
+}
+
+// byDistance sorts orders in the sequence a moving price reaches them:
+// buys from the highest price, sells from the lowest one
+func byDistance(orders []*models.Order) []*models.Order {
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].Side != orders[j].Side {
+			return orders[i].Side == models.SideTypeBuy
+		}
+
+		if orders[i].Side == models.SideTypeBuy {
+			return orders[i].Price.GreaterThan(orders[j].Price)
+		}
+
+		return orders[i].Price.LessThan(orders[j].Price)
+	})
+
+	return orders
+}
+
+func crosses(order *models.Order, price decimal.Decimal) bool {
+	if order.Type == models.OrderTypeMarket {
+		return false
+	}
+
+	if order.Side == models.SideTypeBuy {
+		return price.LessThanOrEqual(order.Price)
+	}
+
+	return price.GreaterThanOrEqual(order.Price)
+}
+
+func copyOrders(orders []*models.Order) []*models.Order {
+	res := make([]*models.Order, len(orders))
+
+	for i, order := range orders {
+		copied := *order
+		res[i] = &copied
+	}
+
+	return res
+}
@@ -0,0 +1,228 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Player holds the entries of a recorded file
+type Player struct {
+	entries []*Entry
+}
+
+// NewPlayer reads the recorded file
+func NewPlayer(path string) (*Player, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.Open: %w", err)
+	}
+	defer f.Close()
+
+	p := &Player{}
+
+	scanner := bufio.NewScanner(f)
+	// the klines and the book tickers of all the symbols make long lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal of line %d: %w", line, err)
+		}
+
+		p.entries = append(p.entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanner.Err: %w", err)
+	}
+
+	return p, nil
+}
+
+// Client returns a client serving the recorded calls of the exchange
+func (p *Player) Client(exchange string) *Client {
+	c := &Client{
+		calls: make(map[string][]*Entry),
+		z:     zap.S().With("context", "replay.Client", "exchange", exchange),
+	}
+
+	for _, entry := range p.entries {
+		if entry.Exchange == exchange {
+			key := callKey(entry.Method, entry.Args)
+			c.calls[key] = append(c.calls[key], entry)
+		}
+	}
+
+	return c
+}
+
+// Client serves the recorded responses without sending a request. A call is
+// served the response of the first recorded call with the same method and
+// arguments it wasn't served yet, so the calls of the strategies running
+// concurrently don't have to come in the recorded sequence. A call that
+// wasn't recorded fails: the session went another way.
+type Client struct {
+	mu    sync.Mutex
+	calls map[string][]*Entry // by method and arguments, in the recorded order
+
+	z *zap.SugaredLogger
+}
+
+// Left returns how many recorded calls weren't served
+func (c *Client) Left() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	left := 0
+	for _, entries := range c.calls {
+		left += len(entries)
+	}
+
+	return left
+}
+
+func (c *Client) next(method string, args json.RawMessage) (*Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := callKey(method, args)
+
+	entries := c.calls[key]
+	if len(entries) == 0 {
+		c.z.Warnw("call wasn't recorded", "method", method, "args", string(args))
+		return nil, &clients.Error{Msg: fmt.Sprintf("replay: no recorded %s call with args %s left", method, args)}
+	}
+
+	c.calls[key] = entries[1:]
+
+	return entries[0], nil
+}
+
+// play serves the next recorded response of the call
+func play[T any](c *Client, method string, args ...interface{}) (T, error) {
+	var res T
+
+	encoded, err := encodeArgs(args)
+	if err != nil {
+		return res, fmt.Errorf("encodeArgs: %w", err)
+	}
+
+	entry, err := c.next(method, encoded)
+	if err != nil {
+		return res, err
+	}
+
+	if len(entry.Result) != 0 {
+		if err := json.Unmarshal(entry.Result, &res); err != nil {
+			return res, fmt.Errorf("json.Unmarshal of the %s result: %w", method, err)
+		}
+	}
+
+	return res, entry.Error.err()
+}
+
+func callKey(method string, args json.RawMessage) string {
+	return method + " " + string(args)
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := play[json.RawMessage](c, "Ping")
+	return err
+}
+
+func (c *Client) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	return play[decimal.Decimal](c, "GetPrice", symbol)
+}
+
+func (c *Client) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	return play[*models.BookTicker](c, "GetBookTicker", symbol)
+}
+
+func (c *Client) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	return play[[]*models.BookTicker](c, "GetBookTickers")
+}
+
+func (c *Client) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	return play[[]*models.Symbol](c, "GetSymbols", symbols)
+}
+
+func (c *Client) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	b, err := play[[2]decimal.Decimal](c, "GetBalance", coin)
+	return b[0], b[1], err
+}
+
+func (c *Client) GetAssets(ctx context.Context) ([]models.Asset, error) {
+	return play[[]models.Asset](c, "GetAssets")
+}
+
+func (c *Client) GetKlines(ctx context.Context, symbol, interval string) ([]*models.Kline, error) {
+	return play[[]*models.Kline](c, "GetKlines", symbol, interval)
+}
+
+func (c *Client) GetKlinesCloses(ctx context.Context, symbol, interval string) ([]float64, error) {
+	return play[[]float64](c, "GetKlinesCloses", symbol, interval)
+}
+
+func (c *Client) NewOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	tif models.TimeInForceType,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	return play[*models.Order](c, "NewOrder", symbol, sideType, orderType, tif, price, quantity)
+}
+
+func (c *Client) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return play[*models.Order](c, "NewLimitBuyOrder", symbol, price, quantity)
+}
+
+func (c *Client) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return play[*models.Order](c, "NewLimitSellOrder", symbol, price, quantity)
+}
+
+func (c *Client) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	return play[*models.Order](c, "NewIcebergOrder", symbol, sideType, orderType, price, quantity, icebergQuantity)
+}
+
+func (c *Client) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return play[*models.Order](c, "NewMarketBuyOrder", symbol, quantity)
+}
+
+func (c *Client) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return play[*models.Order](c, "NewMarketSellOrder", symbol, quantity)
+}
+
+func (c *Client) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	return play[*models.Order](c, "GetOrder", symbol, orderId)
+}
+
+func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
+	return play[[]*models.Order](c, "GetOpenOrders", symbol)
+}
+
+func (c *Client) CloseOrder(ctx context.Context, symbol string, orderId int64) error {
+	_, err := play[json.RawMessage](c, "CloseOrder", symbol, orderId)
+	return err
+}
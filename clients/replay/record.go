@@ -0,0 +1,236 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// Recorder writes the calls of the clients it wraps to a file, every entry is
+// written once the call returns
+type Recorder struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+
+	z *zap.SugaredLogger
+}
+
+// NewRecorder opens the file to append the calls to it
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("os.OpenFile: %w", err)
+	}
+
+	return &Recorder{
+		f:   f,
+		enc: json.NewEncoder(f),
+		z:   zap.S().With("context", "replay.Recorder"),
+	}, nil
+}
+
+// Middleware records the calls of the clients of the exchange, put it closest
+// to the exchange to record every request sent
+func (r *Recorder) Middleware(exchange string) clients.Middleware {
+	return func(next clients.HttpClient) clients.HttpClient {
+		return &recording{next: next, r: r, exchange: exchange}
+	}
+}
+
+// Close closes the file, the calls made after it aren't recorded
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.f.Close()
+}
+
+func (r *Recorder) write(entry *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.enc.Encode(entry); err != nil {
+		r.z.Warnw("failed to record call", "method", entry.Method, "error", err.Error())
+	}
+}
+
+// record makes the call and writes its entry, a failure to record never fails
+// the call
+func record[T any](c *recording, method string, args []interface{}, f func() (T, error)) (T, error) {
+	started := time.Now()
+	res, err := f()
+
+	entry := &Entry{
+		Time:     started,
+		Duration: time.Since(started),
+		Exchange: c.exchange,
+		Method:   method,
+		Error:    newError(err),
+	}
+
+	var encErr error
+
+	if entry.Args, encErr = encodeArgs(args); encErr == nil {
+		entry.Result, encErr = json.Marshal(res)
+	}
+
+	if string(entry.Result) == "null" {
+		entry.Result = nil
+	}
+
+	if encErr != nil {
+		c.r.z.Warnw("failed to encode call", "method", method, "error", encErr.Error())
+		return res, err
+	}
+
+	c.r.write(entry)
+
+	return res, err
+}
+
+type recording struct {
+	next     clients.HttpClient
+	r        *Recorder
+	exchange string
+}
+
+func (c *recording) Ping(ctx context.Context) error {
+	_, err := record(c, "Ping", nil, func() (json.RawMessage, error) {
+		return nil, c.next.Ping(ctx)
+	})
+
+	return err
+}
+
+func (c *recording) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	return record(c, "GetPrice", []interface{}{symbol}, func() (decimal.Decimal, error) {
+		return c.next.GetPrice(ctx, symbol)
+	})
+}
+
+func (c *recording) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	return record(c, "GetBookTicker", []interface{}{symbol}, func() (*models.BookTicker, error) {
+		return c.next.GetBookTicker(ctx, symbol)
+	})
+}
+
+func (c *recording) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	return record(c, "GetBookTickers", nil, func() ([]*models.BookTicker, error) {
+		return c.next.GetBookTickers(ctx)
+	})
+}
+
+func (c *recording) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	return record(c, "GetSymbols", []interface{}{symbols}, func() ([]*models.Symbol, error) {
+		return c.next.GetSymbols(ctx, symbols...)
+	})
+}
+
+func (c *recording) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	b, err := record(c, "GetBalance", []interface{}{coin}, func() ([2]decimal.Decimal, error) {
+		free, locked, err := c.next.GetBalance(ctx, coin)
+		return [2]decimal.Decimal{free, locked}, err
+	})
+
+	return b[0], b[1], err
+}
+
+func (c *recording) GetAssets(ctx context.Context) ([]models.Asset, error) {
+	return record(c, "GetAssets", nil, func() ([]models.Asset, error) {
+		return c.next.GetAssets(ctx)
+	})
+}
+
+func (c *recording) GetKlines(ctx context.Context, symbol, interval string) ([]*models.Kline, error) {
+	return record(c, "GetKlines", []interface{}{symbol, interval}, func() ([]*models.Kline, error) {
+		return c.next.GetKlines(ctx, symbol, interval)
+	})
+}
+
+func (c *recording) GetKlinesCloses(ctx context.Context, symbol, interval string) ([]float64, error) {
+	return record(c, "GetKlinesCloses", []interface{}{symbol, interval}, func() ([]float64, error) {
+		return c.next.GetKlinesCloses(ctx, symbol, interval)
+	})
+}
+
+func (c *recording) NewOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	tif models.TimeInForceType,
+	price, quantity decimal.Decimal,
+) (*models.Order, error) {
+	args := []interface{}{symbol, sideType, orderType, tif, price, quantity}
+
+	return record(c, "NewOrder", args, func() (*models.Order, error) {
+		return c.next.NewOrder(ctx, symbol, sideType, orderType, tif, price, quantity)
+	})
+}
+
+func (c *recording) NewLimitBuyOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return record(c, "NewLimitBuyOrder", []interface{}{symbol, price, quantity}, func() (*models.Order, error) {
+		return c.next.NewLimitBuyOrder(ctx, symbol, price, quantity)
+	})
+}
+
+func (c *recording) NewLimitSellOrder(ctx context.Context, symbol string, price, quantity decimal.Decimal) (*models.Order, error) {
+	return record(c, "NewLimitSellOrder", []interface{}{symbol, price, quantity}, func() (*models.Order, error) {
+		return c.next.NewLimitSellOrder(ctx, symbol, price, quantity)
+	})
+}
+
+func (c *recording) NewIcebergOrder(
+	ctx context.Context,
+	symbol string,
+	sideType models.SideType,
+	orderType models.OrderType,
+	price, quantity, icebergQuantity decimal.Decimal,
+) (*models.Order, error) {
+	args := []interface{}{symbol, sideType, orderType, price, quantity, icebergQuantity}
+
+	return record(c, "NewIcebergOrder", args, func() (*models.Order, error) {
+		return c.next.NewIcebergOrder(ctx, symbol, sideType, orderType, price, quantity, icebergQuantity)
+	})
+}
+
+func (c *recording) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return record(c, "NewMarketBuyOrder", []interface{}{symbol, quantity}, func() (*models.Order, error) {
+		return c.next.NewMarketBuyOrder(ctx, symbol, quantity)
+	})
+}
+
+func (c *recording) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	return record(c, "NewMarketSellOrder", []interface{}{symbol, quantity}, func() (*models.Order, error) {
+		return c.next.NewMarketSellOrder(ctx, symbol, quantity)
+	})
+}
+
+func (c *recording) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	return record(c, "GetOrder", []interface{}{symbol, orderId}, func() (*models.Order, error) {
+		return c.next.GetOrder(ctx, symbol, orderId)
+	})
+}
+
+func (c *recording) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
+	return record(c, "GetOpenOrders", []interface{}{symbol}, func() ([]*models.Order, error) {
+		return c.next.GetOpenOrders(ctx, symbol)
+	})
+}
+
+func (c *recording) CloseOrder(ctx context.Context, symbol string, orderId int64) error {
+	_, err := record(c, "CloseOrder", []interface{}{symbol, orderId}, func() (json.RawMessage, error) {
+		return nil, c.next.CloseOrder(ctx, symbol, orderId)
+	})
+
+	return err
+}
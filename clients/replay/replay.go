@@ -0,0 +1,103 @@
+// Package replay records the calls of a clients.HttpClient to a file and
+// serves them back, so a session can be run again without the exchange.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/caarlos0/env/v6"
+)
+
+type Config struct {
+	RecordFile string `env:"RECORD_FILE"` // appends the calls of the exchange clients to the file
+	ReplayFile string `env:"REPLAY_FILE"` // serves the calls from the recorded file instead of the exchanges
+}
+
+func NewReplayConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.RecordFile != "" && cfg.RecordFile == cfg.ReplayFile {
+		return nil, fmt.Errorf("can't record to the replayed file %s", cfg.RecordFile)
+	}
+
+	return cfg, nil
+}
+
+// Entry is a recorded call, the file has one JSON entry per line
+type Entry struct {
+	Time     time.Time       `json:"time"` // when the call started
+	Duration time.Duration   `json:"duration"`
+	Exchange string          `json:"exchange"`
+	Method   string          `json:"method"`
+	Args     json.RawMessage `json:"args,omitempty"`   // the arguments after the context
+	Result   json.RawMessage `json:"result,omitempty"` // the values returned before the error
+	Error    *Error          `json:"error,omitempty"`
+}
+
+// Error is a recorded error, replayed as a *clients.Error
+type Error struct {
+	Kind string `json:"kind,omitempty"` // message of one of the clients.Err* kinds
+	Code int64  `json:"code,omitempty"`
+	Msg  string `json:"msg"`
+}
+
+var kinds = []error{
+	clients.ErrInsufficientBalance,
+	clients.ErrFilterFailure,
+	clients.ErrUnknownOrder,
+	clients.ErrRateLimited,
+	clients.ErrTimestamp,
+	clients.ErrNetwork,
+}
+
+func newError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	e := &Error{Code: clients.Code(err), Msg: err.Error()}
+
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			e.Kind = kind.Error()
+			break
+		}
+	}
+
+	return e
+}
+
+// err returns the error keeping its kind and code, so errors.Is and
+// clients.Code work on it as on the recorded one
+func (e *Error) err() error {
+	if e == nil {
+		return nil
+	}
+
+	replayed := &clients.Error{Code: e.Code, Msg: e.Msg}
+
+	for _, kind := range kinds {
+		if kind.Error() == e.Kind {
+			replayed.Kind = kind
+		}
+	}
+
+	return replayed
+}
+
+// encodeArgs encodes the arguments of a call the same way when it's recorded
+// and replayed, so the calls are matched by them
+func encodeArgs(args []interface{}) (json.RawMessage, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(args)
+}
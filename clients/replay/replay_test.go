@@ -0,0 +1,158 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+const testSymbol = "BTCUSDT"
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// session makes the calls of a short trading session and returns what the
+// client answered
+func session(t *testing.T, c clients.HttpClient) []interface{} {
+	t.Helper()
+
+	ctx := context.Background()
+
+	price, priceErr := c.GetPrice(ctx, testSymbol)
+	free, locked, balanceErr := c.GetBalance(ctx, "USDT")
+	symbols, symbolsErr := c.GetSymbols(ctx, testSymbol)
+	order, orderErr := c.NewLimitBuyOrder(ctx, testSymbol, d("19000"), d("0.01"))
+	closeErr := c.CloseOrder(ctx, testSymbol, 42)
+	retried, retriedErr := c.GetPrice(ctx, testSymbol)
+
+	return []interface{}{
+		price.String(), priceErr,
+		free.String(), locked.String(), balanceErr,
+		symbols, symbolsErr,
+		order, orderErr,
+		closeErr,
+		retried.String(), retriedErr,
+	}
+}
+
+func recordSession(t *testing.T, path string) []interface{} {
+	t.Helper()
+
+	m := mocks.NewHttpClient()
+	m.SetPrice(testSymbol, "20000")
+	m.SetBalance("USDT", "1000", "5")
+	m.SetSymbol(&models.Symbol{Symbol: testSymbol, BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: d("0.01")})
+	m.Fail("CloseOrder", &clients.Error{Kind: clients.ErrUnknownOrder, Code: -2011, Msg: "Unknown order sent"}, 1)
+
+	r, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	results := session(t, r.Middleware("binance")(m))
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	return results
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+
+	recorded := recordSession(t, path)
+
+	p, err := NewPlayer(path)
+	if err != nil {
+		t.Fatalf("NewPlayer() error = %v", err)
+	}
+
+	if len(p.entries) != 6 {
+		t.Fatalf("recorded %d calls, want 6", len(p.entries))
+	}
+
+	for _, e := range p.entries {
+		if e.Time.IsZero() || e.Exchange != "binance" {
+			t.Errorf("entry = %+v, want a timestamp and the exchange", e)
+		}
+	}
+
+	c := p.Client("binance")
+	replayed := session(t, c)
+
+	for i := range recorded {
+		// the errors are replayed as *clients.Error with the same message
+		if err, ok := recorded[i].(error); ok && err != nil {
+			if replayedErr, _ := replayed[i].(error); replayedErr == nil || replayedErr.Error() != err.Error() {
+				t.Errorf("result %d = %v, want %v", i, replayed[i], recorded[i])
+			}
+
+			continue
+		}
+
+		// decimals equal in value may differ inside, compare them encoded
+		want, _ := json.Marshal(recorded[i])
+		got, _ := json.Marshal(replayed[i])
+
+		if string(got) != string(want) {
+			t.Errorf("result %d = %s, want %s", i, got, want)
+		}
+	}
+
+	if left := c.Left(); left != 0 {
+		t.Errorf("%d recorded calls weren't served", left)
+	}
+}
+
+func TestReplay_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	recordSession(t, path)
+
+	p, err := NewPlayer(path)
+	if err != nil {
+		t.Fatalf("NewPlayer() error = %v", err)
+	}
+
+	c := p.Client("binance")
+	ctx := context.Background()
+
+	err = c.CloseOrder(ctx, testSymbol, 42)
+	if !errors.Is(err, clients.ErrUnknownOrder) || clients.Code(err) != -2011 {
+		t.Errorf("CloseOrder() error = %v, want ErrUnknownOrder with code -2011", err)
+	}
+
+	// the arguments select the recorded call
+	if _, err := c.NewLimitBuyOrder(ctx, testSymbol, d("19500"), d("0.01")); err == nil {
+		t.Error("NewLimitBuyOrder() with other arguments returned no error")
+	}
+
+	if err := c.CloseOrder(ctx, testSymbol, 42); err == nil {
+		t.Error("CloseOrder() served twice")
+	}
+
+	if _, err := p.Client("bybit").GetPrice(ctx, testSymbol); err == nil {
+		t.Error("GetPrice() of another exchange returned no error")
+	}
+}
+
+func TestNewPlayer_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+
+	if err := os.WriteFile(path, []byte("{\"method\":\"Ping\"}\n\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewPlayer(path); err == nil {
+		t.Error("NewPlayer() of an invalid line returned no error")
+	}
+}
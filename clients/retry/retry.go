@@ -0,0 +1,196 @@
+// Package retry wraps a clients.HttpClient to send failed safe requests again.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type Config struct {
+	Attempts  int           `env:"RETRY_ATTEMPTS"   envDefault:"3"`     // tries of a request, 1 disables retries
+	BaseDelay time.Duration `env:"RETRY_BASE_DELAY" envDefault:"200ms"` // backoff before the second try, doubled after every try
+	MaxDelay  time.Duration `env:"RETRY_MAX_DELAY"  envDefault:"5s"`    // the backoff never grows above it
+}
+
+func NewRetryConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Attempts < 1 {
+		return fmt.Errorf("attempts must be at least 1, got %d", cfg.Attempts)
+	}
+
+	if cfg.BaseDelay <= 0 {
+		return fmt.Errorf("base delay must be positive, got %s", cfg.BaseDelay)
+	}
+
+	if cfg.MaxDelay < cfg.BaseDelay {
+		return fmt.Errorf("max delay %s is below the base delay %s", cfg.MaxDelay, cfg.BaseDelay)
+	}
+
+	return nil
+}
+
+// Client sends the requests that only read again if they fail with a network
+// or rate limit error, see clients.Retryable. Orders are placed and canceled
+// once: after a network error their state is unknown and a second try could
+// place the order twice.
+type Client struct {
+	clients.HttpClient
+
+	cfg *Config
+	z   *zap.SugaredLogger
+
+	// after waits between the tries, tests replace it
+	after func(d time.Duration) <-chan time.Time
+}
+
+func NewClient(c clients.HttpClient, cfg *Config) *Client {
+	return &Client{
+		HttpClient: c,
+		cfg:        cfg,
+		z:          zap.S().With("context", "retry.Client"),
+		after:      time.After,
+	}
+}
+
+// Middleware wraps clients with NewClient
+func Middleware(cfg *Config) clients.Middleware {
+	return func(next clients.HttpClient) clients.HttpClient {
+		return NewClient(next, cfg)
+	}
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := do(ctx, c, "Ping", func() (struct{}, error) {
+		return struct{}{}, c.HttpClient.Ping(ctx)
+	})
+
+	return err
+}
+
+func (c *Client) GetPrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	return do(ctx, c, "GetPrice", func() (decimal.Decimal, error) {
+		return c.HttpClient.GetPrice(ctx, symbol)
+	})
+}
+
+func (c *Client) GetBookTicker(ctx context.Context, symbol string) (*models.BookTicker, error) {
+	return do(ctx, c, "GetBookTicker", func() (*models.BookTicker, error) {
+		return c.HttpClient.GetBookTicker(ctx, symbol)
+	})
+}
+
+func (c *Client) GetBookTickers(ctx context.Context) ([]*models.BookTicker, error) {
+	return do(ctx, c, "GetBookTickers", func() ([]*models.BookTicker, error) {
+		return c.HttpClient.GetBookTickers(ctx)
+	})
+}
+
+func (c *Client) GetSymbols(ctx context.Context, symbols ...string) ([]*models.Symbol, error) {
+	return do(ctx, c, "GetSymbols", func() ([]*models.Symbol, error) {
+		return c.HttpClient.GetSymbols(ctx, symbols...)
+	})
+}
+
+func (c *Client) GetBalance(ctx context.Context, coin string) (decimal.Decimal, decimal.Decimal, error) {
+	b, err := do(ctx, c, "GetBalance", func() ([2]decimal.Decimal, error) {
+		free, locked, err := c.HttpClient.GetBalance(ctx, coin)
+		return [2]decimal.Decimal{free, locked}, err
+	})
+
+	return b[0], b[1], err
+}
+
+func (c *Client) GetAssets(ctx context.Context) ([]models.Asset, error) {
+	return do(ctx, c, "GetAssets", func() ([]models.Asset, error) {
+		return c.HttpClient.GetAssets(ctx)
+	})
+}
+
+func (c *Client) GetKlines(ctx context.Context, symbol, interval string) ([]*models.Kline, error) {
+	return do(ctx, c, "GetKlines", func() ([]*models.Kline, error) {
+		return c.HttpClient.GetKlines(ctx, symbol, interval)
+	})
+}
+
+func (c *Client) GetKlinesCloses(ctx context.Context, symbol, interval string) ([]float64, error) {
+	return do(ctx, c, "GetKlinesCloses", func() ([]float64, error) {
+		return c.HttpClient.GetKlinesCloses(ctx, symbol, interval)
+	})
+}
+
+func (c *Client) GetOrder(ctx context.Context, symbol string, orderId int64) (*models.Order, error) {
+	return do(ctx, c, "GetOrder", func() (*models.Order, error) {
+		return c.HttpClient.GetOrder(ctx, symbol, orderId)
+	})
+}
+
+func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*models.Order, error) {
+	return do(ctx, c, "GetOpenOrders", func() ([]*models.Order, error) {
+		return c.HttpClient.GetOpenOrders(ctx, symbol)
+	})
+}
+
+// do calls f until it succeeds, fails with an error that isn't retryable, the
+// attempts are over or the context is done, the last result is returned
+func do[T any](ctx context.Context, c *Client, method string, f func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := f()
+		if err == nil || attempt >= c.cfg.Attempts || !clients.Retryable(err) {
+			return res, err
+		}
+
+		delay := c.backoff(attempt)
+
+		c.z.Warnw(
+			"request failed, retrying",
+			"method", method,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err.Error(),
+		)
+
+		select {
+		case <-c.after(delay):
+		case <-ctx.Done():
+			return res, err
+		}
+	}
+}
+
+// backoff doubles the base delay after every attempt up to the max one and
+// takes a random duration from its upper half, so clients failing together
+// don't retry together
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.cfg.BaseDelay
+	for i := 1; i < attempt && delay < c.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > c.cfg.MaxDelay {
+		delay = c.cfg.MaxDelay
+	}
+
+	half := int64(delay / 2)
+
+	return time.Duration(half + rand.Int63n(half+1))
+}
@@ -0,0 +1,184 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	networkErr = &clients.Error{Kind: clients.ErrNetwork, Msg: "connection reset"}
+	balanceErr = &clients.Error{Kind: clients.ErrInsufficientBalance, Code: -2010, Msg: "insufficient balance"}
+)
+
+// newTestClient doesn't wait between the tries and records the delays
+func newTestClient(m *mocks.HttpClient) (*Client, *[]time.Duration) {
+	c := NewClient(m, &Config{Attempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})
+	delays := &[]time.Duration{}
+
+	c.after = func(d time.Duration) <-chan time.Time {
+		*delays = append(*delays, d)
+
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	}
+
+	return c, delays
+}
+
+func TestClient_RetriesSafeRequests(t *testing.T) {
+	m := mocks.NewHttpClient()
+	m.SetPrice("BTCUSDT", "20000")
+	m.Fail("GetPrice", networkErr, 2)
+
+	c, delays := newTestClient(m)
+
+	price, err := c.GetPrice(context.Background(), "BTCUSDT")
+	if err != nil || !price.Equal(decimal.NewFromInt(20000)) {
+		t.Fatalf("GetPrice() = %v, %v, want 20000 on the third try", price, err)
+	}
+
+	if calls := len(m.CallsTo("GetPrice")); calls != 3 {
+		t.Errorf("GetPrice calls = %d, want 3", calls)
+	}
+
+	if len(*delays) != 2 {
+		t.Fatalf("delays = %v, want 2", *delays)
+	}
+
+	if d := (*delays)[0]; d < 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("first delay = %s, want within [50ms, 100ms]", d)
+	}
+
+	if d := (*delays)[1]; d < 100*time.Millisecond || d > 200*time.Millisecond {
+		t.Errorf("second delay = %s, want within [100ms, 200ms]", d)
+	}
+}
+
+func TestClient_GivesUp(t *testing.T) {
+	m := mocks.NewHttpClient()
+	m.Fail("GetBalance", networkErr, 0)
+
+	c, _ := newTestClient(m)
+
+	if _, _, err := c.GetBalance(context.Background(), "USDT"); !errors.Is(err, clients.ErrNetwork) {
+		t.Errorf("GetBalance() error = %v, want the network one", err)
+	}
+
+	if calls := len(m.CallsTo("GetBalance")); calls != 3 {
+		t.Errorf("GetBalance calls = %d, want 3 attempts", calls)
+	}
+}
+
+func TestClient_DoesNotRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		call   func(c *Client) error
+	}{
+		{
+			name:   "error that is not retryable",
+			method: "GetOpenOrders",
+			err:    balanceErr,
+			call: func(c *Client) error {
+				_, err := c.GetOpenOrders(context.Background(), "BTCUSDT")
+				return err
+			},
+		},
+		{
+			name:   "order placement",
+			method: "NewMarketBuyOrder",
+			err:    networkErr,
+			call: func(c *Client) error {
+				_, err := c.NewMarketBuyOrder(context.Background(), "BTCUSDT", decimal.NewFromInt(1))
+				return err
+			},
+		},
+		{
+			name:   "order cancel",
+			method: "CloseOrder",
+			err:    networkErr,
+			call: func(c *Client) error {
+				return c.CloseOrder(context.Background(), "BTCUSDT", 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewHttpClient()
+			m.Fail(tt.method, tt.err, 0)
+
+			c, delays := newTestClient(m)
+
+			if err := tt.call(c); !errors.Is(err, tt.err) {
+				t.Errorf("%s() error = %v, want %v", tt.method, err, tt.err)
+			}
+
+			if calls := len(m.CallsTo(tt.method)); calls != 1 || len(*delays) != 0 {
+				t.Errorf("%s calls = %d, delays = %v, want a single try", tt.method, calls, *delays)
+			}
+		})
+	}
+}
+
+func TestClient_StopsOnContextDone(t *testing.T) {
+	m := mocks.NewHttpClient()
+	m.Fail("Ping", networkErr, 0)
+
+	c := NewClient(m, &Config{Attempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.Ping(ctx); !errors.Is(err, clients.ErrNetwork) {
+		t.Errorf("Ping() error = %v, want the network one", err)
+	}
+
+	if calls := len(m.CallsTo("Ping")); calls != 1 {
+		t.Errorf("Ping calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_Backoff(t *testing.T) {
+	c := NewClient(nil, &Config{Attempts: 10, BaseDelay: time.Second, MaxDelay: 5 * time.Second})
+
+	limits := map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 40: 5 * time.Second}
+
+	for attempt, limit := range limits {
+		for i := 0; i < 100; i++ {
+			if d := c.backoff(attempt); d < limit/2 || d > limit {
+				t.Fatalf("backoff(%d) = %s, want within [%s, %s]", attempt, d, limit/2, limit)
+			}
+		}
+	}
+}
+
+func TestConfig_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: Config{Attempts: 3, BaseDelay: time.Second, MaxDelay: time.Second}},
+		{name: "no attempts", cfg: Config{Attempts: 0, BaseDelay: time.Second, MaxDelay: time.Second}, wantErr: true},
+		{name: "no base delay", cfg: Config{Attempts: 3, MaxDelay: time.Second}, wantErr: true},
+		{name: "max below base", cfg: Config{Attempts: 3, BaseDelay: time.Second, MaxDelay: time.Millisecond}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -11,17 +11,23 @@ type Config struct {
 
 	BaseCoin string `env:"BASE_COIN" envDefault:"USDT"`
 
+	Metrics struct {
+		Enable bool   `env:"METRICS_ENABLE" envDefault:"false"`
+		Addr   string `env:"METRICS_ADDR"   envDefault:":9100"`
+	}
+
 	ExchangesEnables struct {
 		Bybit   bool `env:"EXCHANGE_BYBIT_ENABLE"   envDefault:"false"`
 		Binance bool `env:"EXCHANGE_BINANCE_ENABLE" envDefault:"true"`
 	}
 
-	StrategiesEnables struct {
-		Grid bool `env:"STRATEGIES_GRID_ENABLE" envDefault:"false"`
-		MACD bool `env:"STRATEGIES_MACD_ENABLE" envDefault:"false"`
-	}
+	// type names of the strategies to run, e.g. macd,rsi
+	Strategies []string `env:"STRATEGIES_ENABLED" envSeparator:","`
 
 	Test bool `env:"TEST" envDefault:"true"`
+
+	// logs the orders and simulates them instead of sending, market data stays live
+	DryRun bool `env:"DRY_RUN" envDefault:"false"`
 }
 
 func NewConfig() (*Config, error) {
@@ -3,16 +3,22 @@ package di
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Minish144/crypto-trading-bot/clients"
 	"github.com/Minish144/crypto-trading-bot/clients/binance"
 	"github.com/Minish144/crypto-trading-bot/clients/bybit"
+	"github.com/Minish144/crypto-trading-bot/clients/dryrun"
+	"github.com/Minish144/crypto-trading-bot/clients/middleware"
+	"github.com/Minish144/crypto-trading-bot/clients/replay"
+	"github.com/Minish144/crypto-trading-bot/clients/retry"
 	"github.com/Minish144/crypto-trading-bot/config"
 	"github.com/Minish144/crypto-trading-bot/helpers"
 	"github.com/Minish144/crypto-trading-bot/logger"
+	"github.com/Minish144/crypto-trading-bot/metrics"
 	"github.com/Minish144/crypto-trading-bot/strategies"
-	"github.com/Minish144/crypto-trading-bot/strategies/gridStrategy"
-	"github.com/Minish144/crypto-trading-bot/strategies/macdStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/all"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +33,7 @@ type DI struct {
 
 		Binance struct {
 			Config     *binance.Config
+			Client     *binance.BinanceClient
 			HttpClient clients.HttpClient
 		}
 	}
@@ -35,6 +42,11 @@ type DI struct {
 		BinanceHelper *helpers.Helper
 	}
 
+	Metrics *metrics.Server
+
+	// records the calls of the exchange clients, nil if they aren't recorded
+	Recorder *replay.Recorder
+
 	Strategies []strategies.Strategy
 }
 
@@ -52,6 +64,64 @@ func NewDI() (*DI, error) {
 		return nil, fmt.Errorf("logger.NewLogger: %w", err)
 	}
 
+	if cfg.Metrics.Enable {
+		dic.Metrics = metrics.NewServer(cfg.Metrics.Addr)
+	}
+
+	retryCfg, err := retry.NewRetryConfig()
+	if err != nil {
+		return nil, fmt.Errorf("retry.NewRetryConfig: %w", err)
+	}
+
+	// shared by every strategy using the exchange
+	exchangeMiddlewares := []clients.Middleware{retry.Middleware(retryCfg)}
+
+	if cfg.DryRun {
+		// outermost, so the reads it makes to simulate the orders are retried
+		exchangeMiddlewares = append([]clients.Middleware{dryrun.Middleware()}, exchangeMiddlewares...)
+
+		zap.S().With("context", "di.NewDI").Warn("dry run, orders are logged and never sent to the exchanges")
+	}
+
+	replayCfg, err := replay.NewReplayConfig()
+	if err != nil {
+		return nil, fmt.Errorf("replay.NewReplayConfig: %w", err)
+	}
+
+	var player *replay.Player
+
+	if replayCfg.ReplayFile != "" {
+		if player, err = replay.NewPlayer(replayCfg.ReplayFile); err != nil {
+			return nil, fmt.Errorf("replay.NewPlayer: %w", err)
+		}
+
+		zap.S().With("context", "di.NewDI").Warnw("replaying recorded calls, no request is sent to the exchanges", "file", replayCfg.ReplayFile)
+	}
+
+	if replayCfg.RecordFile != "" {
+		if dic.Recorder, err = replay.NewRecorder(replayCfg.RecordFile); err != nil {
+			return nil, fmt.Errorf("replay.NewRecorder: %w", err)
+		}
+	}
+
+	// exchangeClient builds the client of the exchange and wraps it, the
+	// recorder goes closest to the exchange to record every try of a request
+	exchangeClient := func(exchange string, newClient func() clients.HttpClient) clients.HttpClient {
+		var c clients.HttpClient
+		if player != nil {
+			c = player.Client(exchange)
+		} else {
+			c = newClient()
+		}
+
+		middlewares := exchangeMiddlewares[:len(exchangeMiddlewares):len(exchangeMiddlewares)]
+		if dic.Recorder != nil {
+			middlewares = append(middlewares, dic.Recorder.Middleware(exchange))
+		}
+
+		return clients.Chain(c, middlewares...)
+	}
+
 	if cfg.ExchangesEnables.Bybit {
 		bbCfg, err := bybit.NewBybitConfig()
 		if err != nil {
@@ -59,7 +129,9 @@ func NewDI() (*DI, error) {
 		}
 
 		dic.Exchanges.Bybit.Config = bbCfg
-		dic.Exchanges.Bybit.HttpClient = bybit.NewBybitClient(bbCfg, cfg.Test)
+		dic.Exchanges.Bybit.HttpClient = exchangeClient("bybit", func() clients.HttpClient {
+			return bybit.NewBybitClient(bbCfg, cfg.Test)
+		})
 	}
 
 	if cfg.ExchangesEnables.Binance {
@@ -69,44 +141,52 @@ func NewDI() (*DI, error) {
 		}
 
 		dic.Exchanges.Binance.Config = bCfg
-		dic.Exchanges.Binance.HttpClient = binance.NewBinanceClient(bCfg, cfg.Test)
+		dic.Exchanges.Binance.HttpClient = exchangeClient("binance", func() clients.HttpClient {
+			dic.Exchanges.Binance.Client = binance.NewBinanceClient(bCfg, cfg.Test)
+			return dic.Exchanges.Binance.Client
+		})
 	}
 
 	dic.Helpers.BinanceHelper = helpers.NewHelper(dic.Exchanges.Binance.HttpClient, dic.Config.BaseCoin)
 
-	if dic.Config.StrategiesEnables.Grid {
-		gridStrategyCfg, err := gridStrategy.NewConfigFromEnv()
-		if err != nil {
-			return nil, fmt.Errorf("gridStrategy.NewConfigFromEnv: %w", err)
-		}
-
-		dic.Strategies = append(
-			dic.Strategies,
-			strategies.NewGridStrategy(
-				dic.Exchanges.Binance.HttpClient,
-				gridStrategyCfg,
-			),
-		)
+	registry := all.NewRegistry()
+	deps := strategies.Dependencies{
+		Binance:       dic.Exchanges.Binance.HttpClient,
+		Bybit:         dic.Exchanges.Bybit.HttpClient,
+		BinanceHelper: dic.Helpers.BinanceHelper,
 	}
 
-	if dic.Config.StrategiesEnables.MACD {
-		macdStrategyCfg, err := macdStrategy.NewConfigFromEnv()
+	enabled := make(map[string]bool, len(cfg.Strategies))
+
+	for _, name := range cfg.Strategies {
+		name = strings.TrimSpace(name)
+		if enabled[name] {
+			return nil, fmt.Errorf("strategy %q is enabled twice", name)
+		}
+
+		strategy, err := registry.New(name, deps.With(strategyMiddlewares(name)))
 		if err != nil {
-			return nil, fmt.Errorf("macdStrategy.NewConfigFromEnv: %w", err)
+			return nil, fmt.Errorf("registry.New: %w", err)
 		}
 
-		dic.Strategies = append(
-			dic.Strategies,
-			strategies.NewMACDStrategy(
-				dic.Exchanges.Binance.HttpClient,
-				macdStrategyCfg,
-			),
-		)
+		enabled[name] = true
+		dic.Strategies = append(dic.Strategies, strategy)
 	}
 
 	return dic, nil
 }
 
+// strategyMiddlewares wrap the clients of a single strategy, so its calls are
+// logged and counted under its name
+func strategyMiddlewares(strategy string) func(exchange string) []clients.Middleware {
+	return func(exchange string) []clients.Middleware {
+		return []clients.Middleware{
+			middleware.Logging(zap.S().With("context", "client", "exchange", exchange, "strategy", strategy)),
+			middleware.Metrics(exchange, strategy),
+		}
+	}
+}
+
 func (dic *DI) Start(ctx context.Context) context.Context {
 	z := zap.S().With("context", "di.Start")
 
@@ -128,8 +208,32 @@ func (dic *DI) Start(ctx context.Context) context.Context {
 		}
 	}
 
+	// nil while replaying recorded calls
+	if dic.Exchanges.Binance.Client != nil {
+		// a drifting clock gets signed requests rejected, sync before the strategies start
+		if _, err := dic.Exchanges.Binance.Client.SyncTime(ctx); err != nil {
+			z.Warnw(
+				"failed to sync binance time",
+				"error", err.Error(),
+			)
+		}
+
+		go dic.Exchanges.Binance.Client.StartTimeSync(ctx)
+
+		if err := dic.Exchanges.Binance.Client.LoadRateLimits(ctx); err != nil {
+			z.Warnw(
+				"failed to load binance rate limits, the defaults are used",
+				"error", err.Error(),
+			)
+		}
+	}
+
 	// go dic.Helpers.BinanceHelper.StartLoggingHelpers(ctx)
 
+	if dic.Metrics != nil {
+		go dic.Metrics.Start()
+	}
+
 	for _, strategy := range dic.Strategies {
 		z.Infow("starting strategy", "name", strategy.Name())
 		go strategy.Start(ctx)
@@ -138,4 +242,37 @@ func (dic *DI) Start(ctx context.Context) context.Context {
 	return ctx
 }
 
-func (dic *DI) Stop() {}
+func (dic *DI) Stop() {
+	z := zap.S().With("context", "di.Stop")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, strategy := range dic.Strategies {
+		if err := strategy.Stop(ctx); err != nil {
+			z.Warnw(
+				"failed to stop strategy",
+				"name", strategy.Name(),
+				"error", err.Error(),
+			)
+		}
+	}
+
+	if dic.Metrics != nil {
+		if err := dic.Metrics.Stop(ctx); err != nil {
+			z.Warnw(
+				"failed to stop metrics server",
+				"error", err.Error(),
+			)
+		}
+	}
+
+	if dic.Recorder != nil {
+		if err := dic.Recorder.Close(); err != nil {
+			z.Warnw(
+				"failed to close recorded calls file",
+				"error", err.Error(),
+			)
+		}
+	}
+}
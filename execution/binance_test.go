@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients/binance"
+	"github.com/Minish144/crypto-trading-bot/clients/binance/binancetest"
+	"github.com/Minish144/crypto-trading-bot/models"
+)
+
+// post-only children are LIMIT_MAKER orders, which binance only acknowledges
+// unless asked for the result, the chase must keep track of them anyway
+func TestExecutor_ExecuteLimitPostOnlyBinance(t *testing.T) {
+	srv := binancetest.NewServer()
+	defer srv.Close()
+
+	srv.AddSymbol(testSymbol, "BTC", "USDT", "20000")
+	srv.SetBookTicker(testSymbol, "19990", "20010")
+	srv.SetBalance("USDT", "100000", "0")
+
+	c := binance.NewBinanceClient(&binance.Config{}, false)
+	c.BaseURL = srv.URL
+
+	// the book moves up once, then the child at the new best bid is filled
+	waits := 0
+	between := func() {
+		waits++
+
+		switch waits {
+		case 1:
+			srv.SetBookTicker(testSymbol, "19995", "20015")
+		case 2:
+			for _, order := range srv.Orders() {
+				if order.Status == models.OrderStatusTypeNew {
+					if err := srv.FillOrder(order.OrderID); err != nil {
+						panic(err)
+					}
+				}
+			}
+		}
+	}
+
+	report, err := newTestExecutor(c, between).Execute(
+		context.Background(),
+		Order{Symbol: testSymbol, Side: models.SideTypeBuy, Quantity: d("1"), QuantityPrecision: 3, PricePrecision: 2},
+		Params{
+			Algorithm: AlgorithmLimit,
+			Limit:     LimitParams{PostOnly: true, RepriceInterval: 10 * time.Second, MaxSlippage: d("0.001"), Timeout: time.Minute},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{"LIMIT_MAKER 19990 1 CANCELED", "LIMIT_MAKER 19995 1 FILLED"}
+
+	if got := limitSummary(report.Orders); !equal(got, want) {
+		t.Errorf("orders = %v, want %v", got, want)
+	}
+
+	if got := limitSummary(srv.Orders()); !equal(got, want) {
+		t.Errorf("orders on the exchange = %v, want %v", got, want)
+	}
+
+	if !report.Executed.Equal(d("1")) {
+		t.Errorf("executed = %v, want 1", report.Executed)
+	}
+}
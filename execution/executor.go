@@ -0,0 +1,338 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// cleanupTimeout bounds canceling the last child after the context is done
+const cleanupTimeout = 5 * time.Second
+
+// Order is the parent order to work
+type Order struct {
+	Symbol            string
+	Side              models.SideType
+	Quantity          decimal.Decimal
+	QuantityPrecision uint
+	PricePrecision    uint // rounds the slippage bound of the limit algorithm
+}
+
+// Report describes how a parent order was executed
+type Report struct {
+	Symbol       string
+	Side         models.SideType
+	Algorithm    Algorithm
+	Quantity     decimal.Decimal // requested
+	Executed     decimal.Decimal // filled by all the child orders
+	Cost         decimal.Decimal // quote coin spent or received
+	AveragePrice decimal.Decimal // zero if nothing was executed
+	ArrivalPrice decimal.Decimal // mid price when the execution started
+	Slippage     decimal.Decimal // share the average price is worse than the arrival one
+	Orders       []*models.Order // final states of the child orders
+	Started      time.Time
+	Finished     time.Time
+}
+
+// Complete reports whether the whole quantity was executed
+func (r *Report) Complete() bool {
+	return r.Executed.GreaterThanOrEqual(r.Quantity)
+}
+
+// Remaining is what is left to execute
+func (r *Report) Remaining() decimal.Decimal {
+	return decimal.Max(r.Quantity.Sub(r.Executed), decimal.Zero)
+}
+
+func (r *Report) add(order *models.Order) {
+	r.Orders = append(r.Orders, order)
+	r.Executed = r.Executed.Add(order.ExecutedQuantity)
+	r.Cost = r.Cost.Add(order.CummulativeQuoteQuantity)
+}
+
+func (r *Report) finish() {
+	r.Finished = time.Now()
+
+	if !r.Executed.IsPositive() {
+		return
+	}
+
+	r.AveragePrice = r.Cost.Div(r.Executed)
+
+	if r.ArrivalPrice.IsPositive() {
+		r.Slippage = r.AveragePrice.Sub(r.ArrivalPrice).Div(r.ArrivalPrice)
+		if r.Side == models.SideTypeSell {
+			r.Slippage = r.Slippage.Neg()
+		}
+	}
+}
+
+// Executor splits parent orders into child limit orders at the touch, a child
+// that didn't fill by the next slice is canceled and its rest is added to it
+type Executor struct {
+	client clients.HttpClient
+	z      *zap.SugaredLogger
+
+	// after waits between the slices, tests replace it
+	after func(d time.Duration) <-chan time.Time
+}
+
+func NewExecutor(c clients.HttpClient) *Executor {
+	return &Executor{
+		client: c,
+		z:      zap.S().With("context", "Executor"),
+		after:  time.After,
+	}
+}
+
+// Execute works the order until it is executed, the duration is over or the
+// context is done. It fails only if the execution couldn't start, failures of
+// single child orders are logged and their quantity moves to the next slice.
+func (e *Executor) Execute(ctx context.Context, order Order, p Params) (*Report, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("p.Validate: %w", err)
+	}
+
+	ticker, err := e.client.GetBookTicker(ctx, order.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("client.GetBookTicker: %w", err)
+	}
+
+	report := &Report{
+		Symbol:       order.Symbol,
+		Side:         order.Side,
+		Algorithm:    p.Algorithm,
+		Quantity:     utils.TruncatePrecision(order.Quantity, order.QuantityPrecision),
+		ArrivalPrice: ticker.BidPrice.Add(ticker.AskPrice).Div(decimal.NewFromInt(2)),
+		Started:      time.Now(),
+	}
+
+	switch p.Algorithm {
+	case AlgorithmMarket:
+		e.market(ctx, report, report.Quantity)
+	case AlgorithmLimit:
+		e.limit(ctx, order, p.Limit, report)
+	default:
+		e.slices(ctx, order, p, report)
+	}
+
+	report.finish()
+
+	e.z.Infow(
+		"execution finished",
+		"symbol", report.Symbol,
+		"side", report.Side,
+		"algorithm", report.Algorithm,
+		"quantity", report.Quantity,
+		"executed", report.Executed,
+		"average_price", report.AveragePrice,
+		"arrival_price", report.ArrivalPrice,
+		"slippage", report.Slippage,
+		"orders", len(report.Orders),
+		"duration", report.Finished.Sub(report.Started),
+	)
+
+	return report, nil
+}
+
+func (e *Executor) slices(ctx context.Context, order Order, p Params, report *Report) {
+	sliceDuration := p.Duration / time.Duration(p.Slices)
+
+	var klines []*models.Kline
+
+	if p.Algorithm == AlgorithmVWAP || p.MaxParticipation.IsPositive() {
+		var err error
+
+		klines, err = e.client.GetKlines(ctx, order.Symbol, p.KlinesInterval)
+		if err != nil {
+			e.z.Warnw("failed to get klines, slices are equal and uncapped", "symbol", order.Symbol, "error", err.Error())
+		}
+	}
+
+	weights := twapWeights(p.Slices)
+	if p.Algorithm == AlgorithmVWAP {
+		weights = vwapWeights(klines, report.Started, sliceDuration, p.Slices)
+	}
+
+	plan := targets(report.Quantity, weights, order.QuantityPrecision)
+
+	var sliceCap decimal.Decimal
+	if p.MaxParticipation.IsPositive() {
+		sliceCap = utils.TruncatePrecision(p.MaxParticipation.Mul(marketVolume(klines, sliceDuration)), order.QuantityPrecision)
+	}
+
+	var child *models.Order
+
+	for i, target := range plan {
+		if i > 0 && !e.wait(ctx, sliceDuration) {
+			break
+		}
+
+		if child = e.settle(ctx, report, child); child != nil {
+			// the state of the last child is unknown, another one could overfill
+			continue
+		}
+
+		quantity := target.Sub(report.Executed)
+		if sliceCap.IsPositive() {
+			quantity = decimal.Min(quantity, sliceCap)
+		}
+
+		if quantity.IsPositive() {
+			child = e.place(ctx, order, quantity)
+		}
+	}
+
+	if child != nil {
+		e.wait(ctx, sliceDuration)
+
+		if ctx.Err() != nil {
+			e.cleanup(report, child)
+			return
+		}
+
+		if e.settle(ctx, report, child) != nil {
+			return
+		}
+	}
+
+	if p.FinishWithMarket && ctx.Err() == nil && report.Remaining().IsPositive() {
+		e.market(ctx, report, report.Remaining())
+	}
+}
+
+// wait returns false if the context is done before the duration passes
+func (e *Executor) wait(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-e.after(d):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// place sends a child limit order at the touch: buys at the best ask and
+// sells at the best bid, what doesn't fill at once rests until the next slice
+func (e *Executor) place(ctx context.Context, order Order, quantity decimal.Decimal) *models.Order {
+	ticker, err := e.client.GetBookTicker(ctx, order.Symbol)
+	if err != nil {
+		e.z.Warnw("failed to get book ticker", "symbol", order.Symbol, "error", err.Error())
+		return nil
+	}
+
+	var child *models.Order
+
+	if order.Side == models.SideTypeBuy {
+		child, err = e.client.NewLimitBuyOrder(ctx, order.Symbol, ticker.AskPrice, quantity)
+	} else {
+		child, err = e.client.NewLimitSellOrder(ctx, order.Symbol, ticker.BidPrice, quantity)
+	}
+
+	if err != nil {
+		e.z.Warnw(
+			"failed to place order",
+			"symbol", order.Symbol,
+			"side", order.Side,
+			"type", "limit",
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return nil
+	}
+
+	return child
+}
+
+// settle cancels the child if it is still open and adds what it filled to the
+// report. It returns the child back if its state couldn't be read.
+func (e *Executor) settle(ctx context.Context, report *Report, child *models.Order) *models.Order {
+	if child == nil {
+		return nil
+	}
+
+	current, err := e.client.GetOrder(ctx, child.Symbol, child.OrderID)
+	if err == nil && isOpen(current) {
+		if err = e.client.CloseOrder(ctx, child.Symbol, child.OrderID); err == nil {
+			current, err = e.client.GetOrder(ctx, child.Symbol, child.OrderID)
+		}
+	}
+
+	if err != nil {
+		e.z.Warnw(
+			"failed to settle order",
+			"symbol", child.Symbol,
+			"order_id", child.OrderID,
+			"error", err.Error(),
+		)
+
+		return child
+	}
+
+	report.add(current)
+
+	return nil
+}
+
+// cleanup settles the last child after the context is done, it must not
+// outlive the execution
+func (e *Executor) cleanup(report *Report, child *models.Order) {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	e.settle(ctx, report, child)
+}
+
+func (e *Executor) market(ctx context.Context, report *Report, quantity decimal.Decimal) {
+	var (
+		order *models.Order
+		err   error
+	)
+
+	if report.Side == models.SideTypeBuy {
+		order, err = e.client.NewMarketBuyOrder(ctx, report.Symbol, quantity)
+	} else {
+		order, err = e.client.NewMarketSellOrder(ctx, report.Symbol, quantity)
+	}
+
+	if err != nil {
+		e.z.Warnw(
+			"failed to place order",
+			"symbol", report.Symbol,
+			"side", report.Side,
+			"type", "market",
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	// some responses don't report the fill yet, the state of the order does.
+	// If it can't be read only the reported fill counts.
+	if order.ExecutedQuantity.IsZero() {
+		current, err := e.client.GetOrder(ctx, order.Symbol, order.OrderID)
+		if err != nil {
+			e.z.Warnw(
+				"failed to get order",
+				"symbol", order.Symbol,
+				"order_id", order.OrderID,
+				"error", err.Error(),
+			)
+		} else {
+			order = current
+		}
+	}
+
+	report.add(order)
+}
+
+func isOpen(order *models.Order) bool {
+	return order.Status == models.OrderStatusTypeNew || order.Status == models.OrderStatusTypePartiallyFilled
+}
@@ -0,0 +1,246 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+const testSymbol = "BTCUSDT"
+
+func newTestClient() *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, "20000")
+	c.SetBookTicker(testSymbol, "19990", "20010")
+
+	return c
+}
+
+// newTestExecutor doesn't wait between the slices, between runs the market
+// does what the test wants to the open orders
+func newTestExecutor(c clients.HttpClient, between func()) *Executor {
+	e := NewExecutor(c)
+	e.after = func(time.Duration) <-chan time.Time {
+		between()
+
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+
+		return ch
+	}
+
+	return e
+}
+
+func fillOpen(c *mocks.HttpClient) func() {
+	return func() {
+		for _, order := range c.OpenOrders(testSymbol) {
+			if err := c.Fill(order.OrderID); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+func summary(orders []*models.Order) []string {
+	result := make([]string, len(orders))
+	for i, order := range orders {
+		result[i] = string(order.Type) + " " + order.OrigQuantity.String() + " " + string(order.Status)
+	}
+
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	twap := Params{Algorithm: AlgorithmTWAP, Duration: time.Hour, Slices: 4, KlinesInterval: "1h", FinishWithMarket: true}
+
+	tests := []struct {
+		name         string
+		params       Params
+		between      func(c *mocks.HttpClient) func()
+		want         []string
+		wantExecuted string
+	}{
+		{
+			name:         "market",
+			params:       Params{Algorithm: AlgorithmMarket},
+			want:         []string{"MARKET 1 FILLED"},
+			wantExecuted: "1",
+		},
+		{
+			name:         "twap slices fill",
+			params:       twap,
+			between:      fillOpen,
+			want:         []string{"LIMIT 0.25 FILLED", "LIMIT 0.25 FILLED", "LIMIT 0.25 FILLED", "LIMIT 0.25 FILLED"},
+			wantExecuted: "1",
+		},
+		{
+			name:    "unfilled slices are replaced and finished at market",
+			params:  twap,
+			between: func(c *mocks.HttpClient) func() { return func() {} },
+			want: []string{
+				"LIMIT 0.25 CANCELED", "LIMIT 0.5 CANCELED", "LIMIT 0.75 CANCELED", "LIMIT 1 CANCELED", "MARKET 1 FILLED",
+			},
+			wantExecuted: "1",
+		},
+		{
+			name: "participation cap",
+			params: Params{
+				Algorithm: AlgorithmTWAP, Duration: 4 * time.Hour, Slices: 4, KlinesInterval: "1h",
+				MaxParticipation: d("0.1"), FinishWithMarket: true,
+			},
+			between:      fillOpen,
+			want:         []string{"LIMIT 0.1 FILLED", "LIMIT 0.1 FILLED", "LIMIT 0.1 FILLED", "LIMIT 0.1 FILLED", "MARKET 0.6 FILLED"},
+			wantExecuted: "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient()
+			c.SetKlines(testSymbol, "1h", hourly(func(hour int) float64 { return 1 }))
+
+			between := func() {}
+			if tt.between != nil {
+				between = tt.between(c)
+			}
+
+			report, err := newTestExecutor(c, between).Execute(
+				context.Background(),
+				Order{Symbol: testSymbol, Side: models.SideTypeBuy, Quantity: d("1"), QuantityPrecision: 3},
+				tt.params,
+			)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got := summary(report.Orders); !equal(got, tt.want) {
+				t.Errorf("orders = %v, want %v", got, tt.want)
+			}
+
+			if !report.Executed.Equal(d(tt.wantExecuted)) || !report.Complete() {
+				t.Errorf("executed = %v, want %s", report.Executed, tt.wantExecuted)
+			}
+		})
+	}
+}
+
+func TestExecutor_ExecuteReport(t *testing.T) {
+	c := newTestClient()
+
+	report, err := newTestExecutor(c, fillOpen(c)).Execute(
+		context.Background(),
+		Order{Symbol: testSymbol, Side: models.SideTypeBuy, Quantity: d("1"), QuantityPrecision: 3},
+		Params{Algorithm: AlgorithmTWAP, Duration: time.Hour, Slices: 2},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// buys at the ask are 10 above the mid price
+	if !report.ArrivalPrice.Equal(d("20000")) || !report.AveragePrice.Equal(d("20010")) || !report.Slippage.Equal(d("0.0005")) {
+		t.Errorf("report = arrival %v, average %v, slippage %v, want 20000, 20010, 0.0005",
+			report.ArrivalPrice, report.AveragePrice, report.Slippage)
+	}
+}
+
+func TestExecutor_ExecuteCanceled(t *testing.T) {
+	c := newTestClient()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	e := NewExecutor(c)
+	e.after = func(time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+
+	report, err := e.Execute(
+		ctx,
+		Order{Symbol: testSymbol, Side: models.SideTypeSell, Quantity: d("1"), QuantityPrecision: 3},
+		Params{Algorithm: AlgorithmTWAP, Duration: time.Hour, Slices: 4, FinishWithMarket: true},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if got, want := summary(report.Orders), []string{"LIMIT 0.25 CANCELED"}; !equal(got, want) {
+		t.Errorf("orders = %v, want %v", got, want)
+	}
+
+	if report.Complete() || !report.Remaining().Equal(d("1")) {
+		t.Errorf("remaining = %v, want 1", report.Remaining())
+	}
+}
+
+// ackClient answers market orders like an ACK response without the fill
+type ackClient struct {
+	*mocks.HttpClient
+}
+
+func (c ackClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	order, err := c.HttpClient.NewMarketBuyOrder(ctx, symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status, order.ExecutedQuantity, order.CummulativeQuoteQuantity = models.OrderStatusTypeNew, decimal.Zero, decimal.Zero
+
+	return order, nil
+}
+
+func TestExecutor_ExecuteMarketAck(t *testing.T) {
+	tests := []struct {
+		name         string
+		getOrderErr  error
+		wantExecuted string
+	}{
+		{name: "fill read back", wantExecuted: "1"},
+		{name: "fill unknown", getOrderErr: errors.New("timeout"), wantExecuted: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient()
+			if tt.getOrderErr != nil {
+				c.Fail("GetOrder", tt.getOrderErr, 1)
+			}
+
+			report, err := NewExecutor(ackClient{c}).Execute(
+				context.Background(),
+				Order{Symbol: testSymbol, Side: models.SideTypeBuy, Quantity: d("1"), QuantityPrecision: 3},
+				Params{Algorithm: AlgorithmMarket},
+			)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if !report.Executed.Equal(d(tt.wantExecuted)) {
+				t.Errorf("executed = %v, want %s", report.Executed, tt.wantExecuted)
+			}
+
+			if calls := c.CallsTo("GetOrder"); len(calls) != 1 {
+				t.Errorf("GetOrder calls = %v, want the market order read back", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+// limit keeps a single child at the best price on its side of the book: buys
+// join the best bid and sells the best ask, never further than the max
+// slippage from the arrival price. A child is canceled and replaced only when
+// the best price moves away from it, so it keeps its place in the queue.
+// After the timeout what is left goes to the market if the params allow it.
+func (e *Executor) limit(ctx context.Context, order Order, p LimitParams, report *Report) {
+	bound := limitBound(order, p, report.ArrivalPrice)
+
+	var (
+		child   *models.Order
+		elapsed time.Duration
+	)
+
+	for {
+		child = e.chase(ctx, order, p, bound, report, child)
+
+		wait := p.RepriceInterval
+		if p.Timeout > 0 && p.Timeout-elapsed < wait {
+			wait = p.Timeout - elapsed
+		}
+
+		if !e.wait(ctx, wait) {
+			e.cleanup(report, child)
+			return
+		}
+
+		elapsed += wait
+
+		child = e.refresh(ctx, report, child)
+		if child == nil && !report.Remaining().IsPositive() {
+			return
+		}
+
+		if p.Timeout > 0 && elapsed >= p.Timeout {
+			break
+		}
+	}
+
+	if e.settle(ctx, report, child) != nil {
+		// the state of the child is unknown, a market order could overfill
+		return
+	}
+
+	if p.FallbackToMarket && report.Remaining().IsPositive() {
+		e.market(ctx, report, report.Remaining())
+	}
+}
+
+// chase moves the child to the best price within the bound. An open child at
+// another price is settled and the rest is placed again, a child whose state
+// couldn't be read is kept.
+func (e *Executor) chase(
+	ctx context.Context,
+	order Order,
+	p LimitParams,
+	bound decimal.Decimal,
+	report *Report,
+	child *models.Order,
+) *models.Order {
+	ticker, err := e.client.GetBookTicker(ctx, order.Symbol)
+	if err != nil {
+		e.z.Warnw("failed to get book ticker", "symbol", order.Symbol, "error", err.Error())
+		return child
+	}
+
+	price := decimal.Min(ticker.BidPrice, bound)
+	if order.Side == models.SideTypeSell {
+		price = decimal.Max(ticker.AskPrice, bound)
+	}
+
+	if child != nil && child.Price.Equal(price) {
+		return child
+	}
+
+	if child = e.settle(ctx, report, child); child != nil {
+		return child
+	}
+
+	if quantity := report.Remaining(); quantity.IsPositive() {
+		return e.placeLimit(ctx, order, p, price, quantity)
+	}
+
+	return nil
+}
+
+// placeLimit sends a GTC child, as LIMIT_MAKER if it must not take and as an
+// iceberg if it is larger than the shown quantity
+func (e *Executor) placeLimit(
+	ctx context.Context,
+	order Order,
+	p LimitParams,
+	price, quantity decimal.Decimal,
+) *models.Order {
+	orderType := models.OrderTypeLimit
+	if p.PostOnly {
+		orderType = models.OrderTypeLimitMaker
+	}
+
+	var (
+		child *models.Order
+		err   error
+	)
+
+	if p.IcebergQuantity.IsPositive() && p.IcebergQuantity.LessThan(quantity) {
+		child, err = e.client.NewIcebergOrder(ctx, order.Symbol, order.Side, orderType, price, quantity, p.IcebergQuantity)
+	} else {
+		child, err = e.client.NewOrder(ctx, order.Symbol, order.Side, orderType, models.TimeInForceTypeGTC, price, quantity)
+	}
+
+	if err != nil {
+		// a post-only child is rejected if the book moved through it, the next
+		// reprice places it again
+		e.z.Warnw(
+			"failed to place order",
+			"symbol", order.Symbol,
+			"side", order.Side,
+			"type", orderType,
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return nil
+	}
+
+	return child
+}
+
+// refresh adds the child to the report once it is no longer open and keeps
+// the open one
+func (e *Executor) refresh(ctx context.Context, report *Report, child *models.Order) *models.Order {
+	if child == nil {
+		return nil
+	}
+
+	current, err := e.client.GetOrder(ctx, child.Symbol, child.OrderID)
+	if err != nil {
+		e.z.Warnw(
+			"failed to get order",
+			"symbol", child.Symbol,
+			"order_id", child.OrderID,
+			"error", err.Error(),
+		)
+
+		return child
+	}
+
+	if isOpen(current) {
+		return current
+	}
+
+	report.add(current)
+
+	return nil
+}
+
+// limitBound is the worst price the order may be chased to, rounded towards
+// the arrival price
+func limitBound(order Order, p LimitParams, arrival decimal.Decimal) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+
+	if order.Side == models.SideTypeBuy {
+		return utils.TruncatePrecision(arrival.Mul(one.Add(p.MaxSlippage)), order.PricePrecision)
+	}
+
+	return arrival.Mul(one.Sub(p.MaxSlippage)).RoundCeil(int32(order.PricePrecision))
+}
@@ -0,0 +1,210 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+)
+
+func limitSummary(orders []*models.Order) []string {
+	result := make([]string, len(orders))
+	for i, order := range orders {
+		result[i] = string(order.Type) + " " + order.Price.String() + " " + order.OrigQuantity.String() + " " + string(order.Status)
+	}
+
+	return result
+}
+
+// moveBook sets the book tickers one per wait, the last one stays
+func moveBook(c *mocks.HttpClient, books ...[2]string) func() {
+	i := 0
+
+	return func() {
+		book := books[i]
+		if i < len(books)-1 {
+			i++
+		}
+
+		c.SetBookTicker(testSymbol, book[0], book[1])
+	}
+}
+
+func TestExecutor_ExecuteLimit(t *testing.T) {
+	limit := LimitParams{
+		PostOnly:         true,
+		RepriceInterval:  10 * time.Second,
+		MaxSlippage:      d("0.001"),
+		Timeout:          time.Minute,
+		FallbackToMarket: true,
+	}
+
+	tests := []struct {
+		name         string
+		side         models.SideType
+		params       func(p LimitParams) LimitParams
+		between      func(c *mocks.HttpClient) func()
+		want         []string
+		wantExecuted string
+	}{
+		{
+			name:         "buy joins the best bid",
+			side:         models.SideTypeBuy,
+			between:      fillOpen,
+			want:         []string{"LIMIT_MAKER 19990 1 FILLED"},
+			wantExecuted: "1",
+		},
+		{
+			name:         "sell joins the best ask",
+			side:         models.SideTypeSell,
+			between:      fillOpen,
+			want:         []string{"LIMIT_MAKER 20010 1 FILLED"},
+			wantExecuted: "1",
+		},
+		{
+			name: "chases up to the max slippage and falls back to market",
+			side: models.SideTypeBuy,
+			between: func(c *mocks.HttpClient) func() {
+				return moveBook(c, [2]string{"19995", "20015"}, [2]string{"20050", "20070"})
+			},
+			want: []string{
+				"LIMIT_MAKER 19990 1 CANCELED", "LIMIT_MAKER 19995 1 CANCELED", "LIMIT_MAKER 20020 1 CANCELED", "MARKET 20000 1 FILLED",
+			},
+			wantExecuted: "1",
+		},
+		{
+			name: "not chased without a slippage allowance",
+			side: models.SideTypeSell,
+			params: func(p LimitParams) LimitParams {
+				p.MaxSlippage = d("0")
+				return p
+			},
+			between: func(c *mocks.HttpClient) func() {
+				return moveBook(c, [2]string{"19950", "19970"})
+			},
+			want:         []string{"LIMIT_MAKER 20010 1 CANCELED", "LIMIT_MAKER 20000 1 CANCELED", "MARKET 20000 1 FILLED"},
+			wantExecuted: "1",
+		},
+		{
+			name: "plain limit without the market fallback",
+			side: models.SideTypeBuy,
+			params: func(p LimitParams) LimitParams {
+				p.PostOnly = false
+				p.FallbackToMarket = false
+
+				return p
+			},
+			want:         []string{"LIMIT 19990 1 CANCELED"},
+			wantExecuted: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient()
+
+			between := func() {}
+			if tt.between != nil {
+				between = tt.between(c)
+			}
+
+			p := limit
+			if tt.params != nil {
+				p = tt.params(p)
+			}
+
+			report, err := newTestExecutor(c, between).Execute(
+				context.Background(),
+				Order{Symbol: testSymbol, Side: tt.side, Quantity: d("1"), QuantityPrecision: 3, PricePrecision: 2},
+				Params{Algorithm: AlgorithmLimit, Limit: p},
+			)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got := limitSummary(report.Orders); !equal(got, tt.want) {
+				t.Errorf("orders = %v, want %v", got, tt.want)
+			}
+
+			if !report.Executed.Equal(d(tt.wantExecuted)) {
+				t.Errorf("executed = %v, want %s", report.Executed, tt.wantExecuted)
+			}
+		})
+	}
+}
+
+func TestExecutor_ExecuteLimitIceberg(t *testing.T) {
+	c := newTestClient()
+
+	report, err := newTestExecutor(c, fillOpen(c)).Execute(
+		context.Background(),
+		Order{Symbol: testSymbol, Side: models.SideTypeBuy, Quantity: d("1"), QuantityPrecision: 3, PricePrecision: 2},
+		Params{
+			Algorithm: AlgorithmLimit,
+			Limit:     LimitParams{IcebergQuantity: d("0.1"), RepriceInterval: time.Second, Timeout: time.Minute},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if calls := c.CallsTo("NewIcebergOrder"); len(calls) != 1 {
+		t.Fatalf("NewIcebergOrder calls = %d, want 1", len(calls))
+	}
+
+	if len(report.Orders) != 1 || !report.Orders[0].IcebergQuantity.Equal(d("0.1")) || !report.Complete() {
+		t.Errorf("orders = %v, want one filled iceberg showing 0.1", limitSummary(report.Orders))
+	}
+}
+
+func TestExecutor_ExecuteLimitCanceled(t *testing.T) {
+	c := newTestClient()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	report, err := newTestExecutor(c, cancel).Execute(
+		ctx,
+		Order{Symbol: testSymbol, Side: models.SideTypeBuy, Quantity: d("1"), QuantityPrecision: 3, PricePrecision: 2},
+		Params{Algorithm: AlgorithmLimit, Limit: LimitParams{PostOnly: true, RepriceInterval: time.Second, FallbackToMarket: true}},
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if open := c.OpenOrders(testSymbol); len(open) != 0 {
+		t.Errorf("open orders = %d, want the child canceled", len(open))
+	}
+
+	if got, want := limitSummary(report.Orders), []string{"LIMIT_MAKER 19990 1 CANCELED"}; !equal(got, want) {
+		t.Errorf("orders = %v, want %v", got, want)
+	}
+}
+
+func TestLimitParams_Validate(t *testing.T) {
+	valid := LimitParams{RepriceInterval: time.Second, MaxSlippage: d("0.01"), Timeout: time.Minute}
+
+	tests := []struct {
+		name    string
+		modify  func(p *LimitParams)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *LimitParams) {}},
+		{name: "no timeout", modify: func(p *LimitParams) { p.Timeout = 0 }},
+		{name: "negative iceberg", modify: func(p *LimitParams) { p.IcebergQuantity = d("-1") }, wantErr: true},
+		{name: "no reprice interval", modify: func(p *LimitParams) { p.RepriceInterval = 0 }, wantErr: true},
+		{name: "slippage of 1", modify: func(p *LimitParams) { p.MaxSlippage = d("1") }, wantErr: true},
+		{name: "negative timeout", modify: func(p *LimitParams) { p.Timeout = -time.Second }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+
+			if err := (Params{Algorithm: AlgorithmLimit, Limit: p}).Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package execution works large orders as child orders placed over time
+package execution
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Algorithm string
+
+const (
+	// AlgorithmMarket sends the whole order at once
+	AlgorithmMarket Algorithm = "market"
+	// AlgorithmTWAP splits the order into equal slices over the duration
+	AlgorithmTWAP Algorithm = "twap"
+	// AlgorithmVWAP sizes the slices after the volume traded at the same time of the day
+	AlgorithmVWAP Algorithm = "vwap"
+	// AlgorithmLimit works the order as a single limit order chasing the best price
+	AlgorithmLimit Algorithm = "limit"
+)
+
+// Params describe how a parent order is worked, strategies embed them with
+// their own env prefix
+type Params struct {
+	Algorithm        Algorithm       `env:"ALGORITHM"          envDefault:"market"` // market, twap, vwap or limit
+	Duration         time.Duration   `env:"DURATION"           envDefault:"1h"`     // how long a parent order is worked
+	Slices           int             `env:"SLICES"             envDefault:"12"`     // child orders a parent order is split into
+	KlinesInterval   string          `env:"KLINES_INTERVAL"    envDefault:"1h"`     // klines the volume profile and the market volume come from
+	MaxParticipation decimal.Decimal `env:"MAX_PARTICIPATION"  envDefault:"0"`      // share of the market volume a slice may take, 0 disables
+	FinishWithMarket bool            `env:"FINISH_WITH_MARKET" envDefault:"true"`   // send what is left at the end as a market order
+	Limit            LimitParams     `envPrefix:"LIMIT_"`                           // how the limit algorithm works the order
+}
+
+func (p Params) Validate() error {
+	switch p.Algorithm {
+	case AlgorithmMarket:
+		return nil
+	case AlgorithmLimit:
+		return p.Limit.Validate()
+	case AlgorithmTWAP, AlgorithmVWAP:
+	default:
+		return fmt.Errorf("unknown algorithm %q, available: market, twap, vwap, limit", p.Algorithm)
+	}
+
+	if p.Duration <= 0 {
+		return fmt.Errorf("duration must be positive, got %s", p.Duration)
+	}
+
+	if p.Slices < 1 {
+		return fmt.Errorf("slices must be at least 1, got %d", p.Slices)
+	}
+
+	if p.MaxParticipation.IsNegative() || p.MaxParticipation.GreaterThan(decimal.NewFromInt(1)) {
+		return fmt.Errorf("max participation must be between 0 and 1, got %s", p.MaxParticipation)
+	}
+
+	if (p.Algorithm == AlgorithmVWAP || p.MaxParticipation.IsPositive()) && p.KlinesInterval == "" {
+		return fmt.Errorf("klines interval is required for the volume profile")
+	}
+
+	return nil
+}
+
+// LimitParams describe how the limit algorithm works an order
+type LimitParams struct {
+	PostOnly         bool            `env:"POST_ONLY"          envDefault:"true"`  // place LIMIT_MAKER orders that never take
+	IcebergQuantity  decimal.Decimal `env:"ICEBERG_QUANTITY"   envDefault:"0"`     // quantity shown in the book, 0 shows all of it
+	RepriceInterval  time.Duration   `env:"REPRICE_INTERVAL"   envDefault:"10s"`   // how often the order is moved to the best price
+	MaxSlippage      decimal.Decimal `env:"MAX_SLIPPAGE"       envDefault:"0.002"` // share the price may be chased away from the arrival price
+	Timeout          time.Duration   `env:"TIMEOUT"            envDefault:"5m"`    // how long the order is chased, 0 means no limit
+	FallbackToMarket bool            `env:"FALLBACK_TO_MARKET" envDefault:"true"`  // send what is left after the timeout as a market order
+}
+
+func (p LimitParams) Validate() error {
+	if p.IcebergQuantity.IsNegative() {
+		return fmt.Errorf("iceberg quantity must not be negative, got %s", p.IcebergQuantity)
+	}
+
+	if p.RepriceInterval <= 0 {
+		return fmt.Errorf("reprice interval must be positive, got %s", p.RepriceInterval)
+	}
+
+	if p.MaxSlippage.IsNegative() || p.MaxSlippage.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("max slippage must be in [0, 1), got %s", p.MaxSlippage)
+	}
+
+	if p.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", p.Timeout)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package execution
+
+import (
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+const day = 24 * time.Hour
+
+// twapWeights gives every slice the same share
+func twapWeights(slices int) []decimal.Decimal {
+	weights := make([]decimal.Decimal, slices)
+	for i := range weights {
+		weights[i] = decimal.NewFromInt(1)
+	}
+
+	return weights
+}
+
+// vwapWeights gives every slice the average volume the klines had at the same
+// time of the day, slices without history fall back to equal shares
+func vwapWeights(klines []*models.Kline, start time.Time, sliceDuration time.Duration, slices int) []decimal.Decimal {
+	interval := klineDuration(klines)
+	if interval <= 0 || interval >= day {
+		return twapWeights(slices)
+	}
+
+	buckets := int(day / interval)
+	sums := make([]float64, buckets)
+	counts := make([]int, buckets)
+
+	for _, k := range klines {
+		b := bucket(time.UnixMilli(k.OpenTime), interval)
+		sums[b] += k.Volume
+		counts[b]++
+	}
+
+	weights := make([]decimal.Decimal, slices)
+	total := decimal.Zero
+
+	for i := range weights {
+		b := bucket(start.Add(time.Duration(i)*sliceDuration), interval)
+		if counts[b] > 0 {
+			weights[i] = decimal.NewFromFloat(sums[b] / float64(counts[b]))
+		}
+
+		total = total.Add(weights[i])
+	}
+
+	if !total.IsPositive() {
+		return twapWeights(slices)
+	}
+
+	return weights
+}
+
+// bucket is the kline of the day the time falls in
+func bucket(t time.Time, interval time.Duration) int {
+	sinceMidnight := time.Duration(t.UTC().UnixMilli()%day.Milliseconds()) * time.Millisecond
+	return int(sinceMidnight / interval)
+}
+
+// klineDuration is the interval of the klines, zero if there are none
+func klineDuration(klines []*models.Kline) time.Duration {
+	if len(klines) == 0 {
+		return 0
+	}
+
+	k := klines[len(klines)-1]
+
+	return time.Duration(k.CloseTime-k.OpenTime+1) * time.Millisecond
+}
+
+// marketVolume is the volume the market is expected to trade during a slice,
+// the average of the klines scaled to the slice duration
+func marketVolume(klines []*models.Kline, sliceDuration time.Duration) decimal.Decimal {
+	interval := klineDuration(klines)
+	if interval <= 0 {
+		return decimal.Zero
+	}
+
+	total := 0.0
+	for _, k := range klines {
+		total += k.Volume
+	}
+
+	average := decimal.NewFromFloat(total / float64(len(klines)))
+
+	return average.Mul(decimal.NewFromInt(int64(sliceDuration))).Div(decimal.NewFromInt(int64(interval)))
+}
+
+// targets turns the weights into the quantity that should be executed by the
+// end of every slice, the last target is the whole quantity
+func targets(quantity decimal.Decimal, weights []decimal.Decimal, precision uint) []decimal.Decimal {
+	total := decimal.Zero
+	for _, w := range weights {
+		total = total.Add(w)
+	}
+
+	result := make([]decimal.Decimal, len(weights))
+	cumulative := decimal.Zero
+
+	for i, w := range weights {
+		cumulative = cumulative.Add(w)
+		result[i] = utils.TruncatePrecision(quantity.Mul(cumulative).Div(total), precision)
+	}
+
+	result[len(result)-1] = utils.TruncatePrecision(quantity, precision)
+
+	return result
+}
@@ -0,0 +1,111 @@
+package execution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// hourly builds two days of hourly klines with the volume of the hour of the day
+func hourly(volume func(hour int) float64) []*models.Kline {
+	var klines []*models.Kline
+
+	for i := 0; i < 48; i++ {
+		open := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)
+		klines = append(klines, &models.Kline{
+			OpenTime:  open.UnixMilli(),
+			CloseTime: open.Add(time.Hour).UnixMilli() - 1,
+			Volume:    volume(i % 24),
+		})
+	}
+
+	return klines
+}
+
+func Test_targets(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []decimal.Decimal
+		want    []string
+	}{
+		{name: "equal", weights: twapWeights(3), want: []string{"0.333", "0.666", "1"}},
+		{name: "weighted", weights: []decimal.Decimal{d("1"), d("3")}, want: []string{"0.25", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := targets(d("1"), tt.weights, 3)
+			for i, want := range tt.want {
+				if !got[i].Equal(d(want)) {
+					t.Errorf("targets() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func Test_vwapWeights(t *testing.T) {
+	// the market trades 3 times more from 12:00
+	klines := hourly(func(hour int) float64 {
+		if hour >= 12 {
+			return 3
+		}
+
+		return 1
+	})
+
+	start := time.Date(2022, 1, 3, 11, 0, 0, 0, time.UTC)
+
+	got := vwapWeights(klines, start, time.Hour, 2)
+	if !got[0].Equal(d("1")) || !got[1].Equal(d("3")) {
+		t.Errorf("vwapWeights() = %v, want [1 3]", got)
+	}
+
+	if got := vwapWeights(nil, start, time.Hour, 2); !got[0].Equal(got[1]) {
+		t.Errorf("vwapWeights() without klines = %v, want equal weights", got)
+	}
+}
+
+func Test_marketVolume(t *testing.T) {
+	klines := hourly(func(hour int) float64 { return 6 })
+
+	if got := marketVolume(klines, 10*time.Minute); !got.Equal(d("1")) {
+		t.Errorf("marketVolume() = %v, want 1", got)
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	valid := Params{Algorithm: AlgorithmTWAP, Duration: time.Hour, Slices: 4, KlinesInterval: "1h"}
+
+	tests := []struct {
+		name    string
+		modify  func(p *Params)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *Params) {}},
+		{name: "market ignores the rest", modify: func(p *Params) { *p = Params{Algorithm: AlgorithmMarket} }},
+		{name: "unknown algorithm", modify: func(p *Params) { p.Algorithm = "iceberg" }, wantErr: true},
+		{name: "no duration", modify: func(p *Params) { p.Duration = 0 }, wantErr: true},
+		{name: "no slices", modify: func(p *Params) { p.Slices = 0 }, wantErr: true},
+		{name: "participation above 1", modify: func(p *Params) { p.MaxParticipation = d("1.5") }, wantErr: true},
+		{name: "vwap without klines", modify: func(p *Params) { p.Algorithm, p.KlinesInterval = AlgorithmVWAP, "" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid
+			tt.modify(&p)
+
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -18,21 +19,40 @@ func NewHelper(c clients.HttpClient, baseCoin string) *Helper {
 	return &Helper{c: c, baseCoin: baseCoin}
 }
 
-func (h *Helper) TotalHoldings(ctx context.Context) (float64, float64, error) {
+// BaseCoin is the coin holdings are valued in
+func (h *Helper) BaseCoin() string {
+	return h.baseCoin
+}
+
+// Price returns the price of the coin in the base coin
+func (h *Helper) Price(ctx context.Context, coin string) (decimal.Decimal, error) {
+	if coin == h.baseCoin {
+		return decimal.NewFromInt(1), nil
+	}
+
+	price, err := h.c.GetPrice(ctx, coin+h.baseCoin)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("c.GetPrice: %w", err)
+	}
+
+	return price, nil
+}
+
+func (h *Helper) TotalHoldings(ctx context.Context) (decimal.Decimal, decimal.Decimal, error) {
 	assets, err := h.c.GetAssets(ctx)
 	if err != nil {
-		return 0, 0, fmt.Errorf("c.GetAssets: %w", err)
+		return decimal.Zero, decimal.Zero, fmt.Errorf("c.GetAssets: %w", err)
 	}
 
 	balance, locked, err := h.c.GetBalance(ctx, h.baseCoin)
 	if err != nil {
-		return 0, 0, fmt.Errorf("c.GetBalance: %w", err)
+		return decimal.Zero, decimal.Zero, fmt.Errorf("c.GetBalance: %w", err)
 	}
 
 	for _, asset := range assets {
 		pair := asset.Coin + h.baseCoin
 
-		if asset.Free == 0 && asset.Locked == 0 {
+		if asset.Free.IsZero() && asset.Locked.IsZero() {
 			continue
 		}
 
@@ -41,8 +61,8 @@ func (h *Helper) TotalHoldings(ctx context.Context) (float64, float64, error) {
 			continue
 		}
 
-		balance += price * asset.Free
-		locked += price * asset.Locked
+		balance = balance.Add(price.Mul(asset.Free))
+		locked = locked.Add(price.Mul(asset.Locked))
 	}
 
 	return balance, locked, nil
@@ -66,7 +86,7 @@ func (h *Helper) StartLoggingHelpers(ctx context.Context) {
 					"base_coin", h.baseCoin,
 					"amount", balance,
 					"amount_locked", locked,
-					"total", balance+locked,
+					"total", balance.Add(locked),
 				)
 			}
 		case <-ctx.Done():
@@ -0,0 +1,121 @@
+// Package metrics keeps the Prometheus collectors of the bot and serves them over HTTP.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const namespace = "trading_bot"
+
+var (
+	// TimeframeTrend is the trend of a confirmation timeframe: 1 up, -1 down, 0 neutral
+	TimeframeTrend = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "strategy",
+		Name:      "timeframe_trend",
+		Help:      "Trend of a confirmation timeframe: 1 up, -1 down, 0 neutral.",
+	}, []string{"strategy", "symbol", "interval"})
+
+	// SignalDecisions counts the signals checked against the confirmation timeframes
+	SignalDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "strategy",
+		Name:      "signal_decisions_total",
+		Help:      "Signals checked against the confirmation timeframes by the decision.",
+	}, []string{"strategy", "symbol", "signal", "decision"})
+
+	// RateLimitUsed is the used part of an exchange rate limit in its current window
+	RateLimitUsed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "exchange",
+		Name:      "rate_limit_used",
+		Help:      "Used part of an exchange rate limit in its current window.",
+	}, []string{"exchange", "type", "interval"})
+
+	// RateLimitMax is the size of an exchange rate limit
+	RateLimitMax = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "exchange",
+		Name:      "rate_limit_max",
+		Help:      "Size of an exchange rate limit.",
+	}, []string{"exchange", "type", "interval"})
+
+	// RateLimitThrottled counts the requests delayed or rejected by a rate limit
+	RateLimitThrottled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "exchange",
+		Name:      "rate_limit_throttled_total",
+		Help:      "Requests delayed or rejected by a rate limit and the bans received.",
+	}, []string{"exchange", "type", "action"})
+
+	// ClockOffset is the offset of the local clock from the server time of an exchange
+	ClockOffset = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "exchange",
+		Name:      "clock_offset_seconds",
+		Help:      "Offset of the local clock from the server time of an exchange, positive if the local clock is ahead.",
+	}, []string{"exchange"})
+
+	// ClientCalls counts the calls of the exchange clients by their result
+	ClientCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "exchange",
+		Name:      "client_calls_total",
+		Help:      "Calls of the exchange clients by the strategy making them and their result.",
+	}, []string{"exchange", "strategy", "method", "result"})
+
+	// ClientCallDuration observes how long the calls of the exchange clients take
+	ClientCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "exchange",
+		Name:      "client_call_duration_seconds",
+		Help:      "Duration of the calls of the exchange clients, retries and rate limit waits included.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"exchange", "method"})
+)
+
+// Server serves the default Prometheus registry on /metrics
+type Server struct {
+	srv *http.Server
+	z   *zap.SugaredLogger
+}
+
+func NewServer(addr string) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &Server{
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		z: zap.S().With("context", "metrics.Server"),
+	}
+}
+
+// Start serves the metrics until Stop is called
+func (s *Server) Start() {
+	s.z.Infow("serving metrics", "addr", s.srv.Addr)
+
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.z.Errorw("failed to serve metrics", "error", err.Error())
+	}
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	if err := s.srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("srv.Shutdown: %w", err)
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type Asset struct {
 	Coin   string
-	Free   float64
-	Locked float64
+	Free   decimal.Decimal
+	Locked decimal.Decimal
 }
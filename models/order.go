@@ -1,20 +1,22 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type Order struct {
 	Symbol                   string
 	OrderID                  int64
 	OrderListId              int64
 	ClientOrderID            string
-	Price                    float64
-	OrigQuantity             float64
-	ExecutedQuantity         float64
-	CummulativeQuoteQuantity float64
+	Price                    decimal.Decimal
+	OrigQuantity             decimal.Decimal
+	ExecutedQuantity         decimal.Decimal
+	CummulativeQuoteQuantity decimal.Decimal
 	Status                   OrderStatusType
 	TimeInForce              TimeInForceType
 	Type                     OrderType
 	Side                     SideType
-	StopPrice                float64
-	IcebergQuantity          float64
+	StopPrice                decimal.Decimal
+	IcebergQuantity          decimal.Decimal
 	Time                     int64
 	UpdateTime               int64
 	IsWorking                bool
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// Symbol is a trading pair with its trading rules, zero steps and limits
+// mean the exchange doesn't restrict them
+type Symbol struct {
+	Symbol      string
+	Status      string
+	BaseAsset   string          // the traded coin, e.g. BTC of BTCUSDT
+	QuoteAsset  string          // the coin of the price, e.g. USDT of BTCUSDT
+	TickSize    decimal.Decimal // price step
+	StepSize    decimal.Decimal // quantity step
+	MinQuantity decimal.Decimal // minimal order quantity
+	MinNotional decimal.Decimal // minimal price * quantity of an order
+}
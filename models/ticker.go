@@ -0,0 +1,12 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// BookTicker is the best bid and ask of a symbol
+type BookTicker struct {
+	Symbol      string
+	BidPrice    decimal.Decimal
+	BidQuantity decimal.Decimal
+	AskPrice    decimal.Decimal
+	AskQuantity decimal.Decimal
+}
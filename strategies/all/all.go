@@ -0,0 +1,36 @@
+// Package all lists every strategy the bot is able to run
+package all
+
+import (
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/arbitrageStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/bollingerStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/dcaStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/gridStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/maCrossStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/macdStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/marketMakerStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/rebalanceStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/rsiStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/ruleStrategy"
+	"github.com/Minish144/crypto-trading-bot/strategies/triangularStrategy"
+)
+
+// NewRegistry returns a registry with all the strategies registered
+func NewRegistry() *strategies.Registry {
+	r := strategies.NewRegistry()
+
+	gridStrategy.Register(r)
+	macdStrategy.Register(r)
+	rsiStrategy.Register(r)
+	bollingerStrategy.Register(r)
+	dcaStrategy.Register(r)
+	maCrossStrategy.Register(r)
+	ruleStrategy.Register(r)
+	marketMakerStrategy.Register(r)
+	rebalanceStrategy.Register(r)
+	arbitrageStrategy.Register(r)
+	triangularStrategy.Register(r)
+
+	return r
+}
@@ -0,0 +1,241 @@
+package arbitrageStrategy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+func (s *ArbitrageStrategy) Start(ctx context.Context) error {
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// leg is one of the two orders of a trade
+type leg struct {
+	exchange exchange
+	side     models.SideType
+	price    decimal.Decimal
+	order    *models.Order
+	err      error
+}
+
+func (l *leg) executed() decimal.Decimal {
+	if l.err != nil || l.order == nil {
+		return decimal.Zero
+	}
+
+	return l.order.ExecutedQuantity
+}
+
+func (s *ArbitrageStrategy) logic(ctx context.Context) {
+	if !s.mu.TryLock() {
+		// the previous trade is still running, the books it saw are gone anyway
+		return
+	}
+	defer s.mu.Unlock()
+
+	tickers, err := s.bookTickers(ctx)
+	if err != nil {
+		s.z.Warnw("failed to get book tickers", "error", err.Error())
+		return
+	}
+
+	o := bestOpportunity(tickers, s.fees)
+	if o.spread.LessThan(s.cfg.MinSpread) {
+		return
+	}
+
+	buy, sell := s.exchanges[o.buy], s.exchanges[o.sell]
+
+	s.z.Infow(
+		"arbitrage opportunity",
+		"buy_exchange", buy.name,
+		"sell_exchange", sell.name,
+		"ask", o.ask,
+		"bid", o.bid,
+		"spread", o.spread,
+	)
+
+	quantity, err := s.quantity(ctx, o)
+	if err != nil {
+		s.z.Warnw("failed to get balances", "error", err.Error())
+		return
+	}
+
+	if !quantity.IsPositive() {
+		s.z.Warnw(
+			"not enough balance for arbitrage",
+			"buy_exchange", buy.name,
+			"sell_exchange", sell.name,
+		)
+
+		return
+	}
+
+	s.trade(ctx, o, quantity)
+}
+
+// bookTickers reads both books at the same time so they are as close as possible
+func (s *ArbitrageStrategy) bookTickers(ctx context.Context) ([2]*models.BookTicker, error) {
+	var (
+		tickers [2]*models.BookTicker
+		errs    [2]error
+		wg      sync.WaitGroup
+	)
+
+	for i, e := range s.exchanges {
+		wg.Add(1)
+
+		go func(i int, e exchange) {
+			defer wg.Done()
+			tickers[i], errs[i] = e.client.GetBookTicker(ctx, s.cfg.Symbol)
+		}(i, e)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return tickers, fmt.Errorf("%s client.GetBookTicker: %w", s.exchanges[i].name, err)
+		}
+	}
+
+	return tickers, nil
+}
+
+// quantity is the order amount cut to the top levels of the books, to the
+// base coin the buy exchange holds and to the quote coin the sell exchange holds
+func (s *ArbitrageStrategy) quantity(ctx context.Context, o opportunity) (decimal.Decimal, error) {
+	buy, sell := s.exchanges[o.buy], s.exchanges[o.sell]
+
+	baseFree, _, err := buy.client.GetBalance(ctx, s.cfg.Coins.Base)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s client.GetBalance: %w", buy.name, err)
+	}
+
+	quoteFree, _, err := sell.client.GetBalance(ctx, s.cfg.Coins.Quote)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s client.GetBalance: %w", sell.name, err)
+	}
+
+	amount := s.cfg.OrderAmount
+	if s.cfg.BaseCoinForAmount {
+		amount = utils.QuoteQtyFromBaseQty(o.ask, s.cfg.OrderAmount)
+	}
+
+	affordable := utils.QuoteQtyFromBaseQty(o.ask.Mul(decimal.NewFromInt(1).Add(s.fees[o.buy])), baseFree)
+
+	quantity := decimal.Min(amount, o.quantity, affordable, quoteFree)
+
+	return utils.TruncatePrecision(quantity, s.cfg.QuantityPrecision), nil
+}
+
+// trade places both legs at once as IOC limit orders at the prices the spread
+// was computed from, then evens out what one leg filled more than the other
+func (s *ArbitrageStrategy) trade(ctx context.Context, o opportunity, quantity decimal.Decimal) {
+	buy := &leg{exchange: s.exchanges[o.buy], side: models.SideTypeBuy, price: o.ask}
+	sell := &leg{exchange: s.exchanges[o.sell], side: models.SideTypeSell, price: o.bid}
+
+	var wg sync.WaitGroup
+
+	for _, l := range []*leg{buy, sell} {
+		wg.Add(1)
+
+		go func(l *leg) {
+			defer wg.Done()
+			l.order, l.err = l.exchange.client.NewOrder(ctx, s.cfg.Symbol, l.side, models.OrderTypeLimit, models.TimeInForceTypeIOC, l.price, quantity)
+		}(l)
+	}
+
+	wg.Wait()
+
+	for _, l := range []*leg{buy, sell} {
+		if l.err != nil {
+			s.z.Warnw(
+				"failed to place order",
+				"exchange", l.exchange.name,
+				"side", l.side,
+				"type", "limit",
+				"price", l.price,
+				"quantity", quantity,
+				"error", l.err.Error(),
+			)
+		}
+	}
+
+	bought, sold := buy.executed(), sell.executed()
+
+	s.z.Infow(
+		"arbitrage executed",
+		"buy_exchange", buy.exchange.name,
+		"sell_exchange", sell.exchange.name,
+		"ask", o.ask,
+		"bid", o.bid,
+		"quantity", quantity,
+		"bought", bought,
+		"sold", sold,
+	)
+
+	switch {
+	case bought.GreaterThan(sold):
+		// the coin bought without a sale is sold back where it was bought
+		s.unwind(ctx, buy.exchange, models.SideTypeSell, bought.Sub(sold))
+	case sold.GreaterThan(bought):
+		// the coin sold without a purchase is bought back where it was sold
+		s.unwind(ctx, sell.exchange, models.SideTypeBuy, sold.Sub(bought))
+	}
+}
+
+// unwind closes the exposure left by a leg that failed or filled less than the
+// other one with a market order, it returns the balances to where they were
+func (s *ArbitrageStrategy) unwind(ctx context.Context, e exchange, side models.SideType, quantity decimal.Decimal) {
+	var err error
+
+	if side == models.SideTypeBuy {
+		_, err = e.client.NewMarketBuyOrder(ctx, s.cfg.Symbol, quantity)
+	} else {
+		_, err = e.client.NewMarketSellOrder(ctx, s.cfg.Symbol, quantity)
+	}
+
+	if err != nil {
+		s.z.Errorw(
+			"failed to unwind leg",
+			"exchange", e.name,
+			"side", side,
+			"type", "market",
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	s.z.Warnw(
+		"leg unwound",
+		"exchange", e.name,
+		"side", side,
+		"type", "market",
+		"quantity", quantity,
+	)
+}
+
+func (s *ArbitrageStrategy) Stop(ctx context.Context) error {
+	return nil
+}
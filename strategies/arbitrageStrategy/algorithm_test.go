@@ -0,0 +1,238 @@
+package arbitrageStrategy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+	"golang.org/x/exp/slices"
+)
+
+const testSymbol = "BTCUSDT"
+
+func newTestConfig() *Config {
+	cfg := &Config{
+		Symbol:            testSymbol,
+		QuantityPrecision: 3,
+		Exchanges:         []string{"binance", "bybit"},
+		Fees:              []decimal.Decimal{d("0.001"), d("0.001")},
+		Interval:          1,
+		OrderAmount:       d("0.1"),
+		MinSpread:         d("0.001"),
+	}
+	cfg.Coins.Quote = "BTC"
+	cfg.Coins.Base = "USDT"
+
+	return cfg
+}
+
+// newTestClient quotes the symbol around the price and holds enough of both coins
+func newTestClient(price, bid, ask string) *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, price)
+	c.SetBookTicker(testSymbol, bid, ask)
+	c.SetBalance("BTC", "1", "0")
+	c.SetBalance("USDT", "100000", "0")
+
+	return c
+}
+
+func newTestStrategy(t *testing.T, binance, bybit *mocks.HttpClient) *ArbitrageStrategy {
+	t.Helper()
+
+	s, err := NewArbitrageStrategy(strategies.Dependencies{Binance: binance, Bybit: bybit}, newTestConfig())
+	if err != nil {
+		t.Fatalf("NewArbitrageStrategy() error = %v", err)
+	}
+
+	return s
+}
+
+func orders(c *mocks.HttpClient) []string {
+	var result []string
+	for _, order := range c.Orders() {
+		result = append(result, string(order.Side)+" "+string(order.Type)+" "+order.OrigQuantity.String())
+	}
+
+	return result
+}
+
+func TestNewConfigFromEnv(t *testing.T) {
+	os.Setenv("STRATEGIES_ARBITRAGE_SYMBOL", "BTC/USDT")
+	os.Setenv("STRATEGIES_ARBITRAGE_EXCHANGES", "Binance, bybit")
+	os.Setenv("STRATEGIES_ARBITRAGE_FEES", "0.001,0.00075")
+	defer os.Unsetenv("STRATEGIES_ARBITRAGE_SYMBOL")
+	defer os.Unsetenv("STRATEGIES_ARBITRAGE_EXCHANGES")
+	defer os.Unsetenv("STRATEGIES_ARBITRAGE_FEES")
+
+	cfg, err := NewConfigFromEnv()
+	if err != nil {
+		t.Fatalf("NewConfigFromEnv() error = %v", err)
+	}
+
+	if cfg.Exchanges[0] != "binance" || cfg.Exchanges[1] != "bybit" || !cfg.Fees[1].Equal(d("0.00075")) {
+		t.Errorf("NewConfigFromEnv() = %v %v, want binance/bybit with 0.001/0.00075 fees", cfg.Exchanges, cfg.Fees)
+	}
+}
+
+func TestConfig_validate(t *testing.T) {
+	strategytest.RunValidate(t, newTestConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "one exchange", Modify: func(cfg *Config) { cfg.Exchanges, cfg.Fees = cfg.Exchanges[:1], cfg.Fees[:1] }, WantErr: true},
+		{Name: "same exchange twice", Modify: func(cfg *Config) { cfg.Exchanges[1] = "binance" }, WantErr: true},
+		{Name: "missing fee", Modify: func(cfg *Config) { cfg.Fees = cfg.Fees[:1] }, WantErr: true},
+		{Name: "fee of 1", Modify: func(cfg *Config) { cfg.Fees[0] = d("1") }, WantErr: true},
+		{Name: "zero amount", Modify: func(cfg *Config) { cfg.OrderAmount = decimal.Zero }, WantErr: true},
+		{Name: "negative spread", Modify: func(cfg *Config) { cfg.MinSpread = d("-0.1") }, WantErr: true},
+	})
+}
+
+func TestNewArbitrageStrategy_disabledExchange(t *testing.T) {
+	if _, err := NewArbitrageStrategy(strategies.Dependencies{Binance: mocks.NewHttpClient()}, newTestConfig()); err == nil {
+		t.Error("NewArbitrageStrategy() without bybit succeeded")
+	}
+}
+
+func TestNewArbitrageStrategy_unsupportedExchange(t *testing.T) {
+	bybit := newTestClient("20000", "19999", "20001")
+	bybit.Fail("GetBookTicker", clients.ErrNotImplemented, 1)
+
+	deps := strategies.Dependencies{Binance: newTestClient("20000", "19999", "20001"), Bybit: bybit}
+
+	if _, err := NewArbitrageStrategy(deps, newTestConfig()); !errors.Is(err, clients.ErrNotImplemented) {
+		t.Errorf("NewArbitrageStrategy() error = %v, want ErrNotImplemented", err)
+	}
+}
+
+func TestNewConfigFromEnv_noExchanges(t *testing.T) {
+	if _, err := NewConfigFromEnv(); err == nil {
+		t.Error("NewConfigFromEnv() without exchanges succeeded")
+	}
+}
+
+func TestArbitrageStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name        string
+		binance     [3]string // price, bid and ask
+		bybit       [3]string
+		prepare     func(binance, bybit *mocks.HttpClient)
+		wantBinance []string
+		wantBybit   []string
+	}{
+		{
+			name:    "spread below fees",
+			binance: [3]string{"20000", "19999", "20001"},
+			bybit:   [3]string{"20030", "20029", "20031"},
+		},
+		{
+			name:        "buys on the cheap exchange and sells on the expensive one",
+			binance:     [3]string{"20000", "19999", "20001"},
+			bybit:       [3]string{"20100", "20099", "20101"},
+			wantBinance: []string{"BUY LIMIT 0.1"},
+			wantBybit:   []string{"SELL LIMIT 0.1"},
+		},
+		{
+			name:        "both directions",
+			binance:     [3]string{"20100", "20099", "20101"},
+			bybit:       [3]string{"20000", "19999", "20001"},
+			wantBinance: []string{"SELL LIMIT 0.1"},
+			wantBybit:   []string{"BUY LIMIT 0.1"},
+		},
+		{
+			name:    "cut to the balance",
+			binance: [3]string{"20000", "19999", "20001"},
+			bybit:   [3]string{"20100", "20099", "20101"},
+			prepare: func(binance, bybit *mocks.HttpClient) {
+				bybit.SetBalance("BTC", "0.0305", "0")
+			},
+			wantBinance: []string{"BUY LIMIT 0.03"},
+			wantBybit:   []string{"SELL LIMIT 0.03"},
+		},
+		{
+			name:    "no balance",
+			binance: [3]string{"20000", "19999", "20001"},
+			bybit:   [3]string{"20100", "20099", "20101"},
+			prepare: func(binance, bybit *mocks.HttpClient) {
+				binance.SetBalance("USDT", "0", "0")
+			},
+		},
+		{
+			name:    "failed sell is unwound",
+			binance: [3]string{"20000", "19999", "20001"},
+			bybit:   [3]string{"20100", "20099", "20101"},
+			prepare: func(binance, bybit *mocks.HttpClient) {
+				bybit.Fail("NewOrder", errors.New("rejected"), 1)
+			},
+			wantBinance: []string{"BUY LIMIT 0.1", "SELL MARKET 0.1"},
+		},
+		{
+			name:    "failed buy is unwound",
+			binance: [3]string{"20000", "19999", "20001"},
+			bybit:   [3]string{"20100", "20099", "20101"},
+			prepare: func(binance, bybit *mocks.HttpClient) {
+				binance.Fail("NewOrder", errors.New("rejected"), 1)
+			},
+			wantBybit: []string{"SELL LIMIT 0.1", "BUY MARKET 0.1"},
+		},
+		{
+			name:    "failed book ticker",
+			binance: [3]string{"20000", "19999", "20001"},
+			bybit:   [3]string{"20100", "20099", "20101"},
+			prepare: func(binance, bybit *mocks.HttpClient) {
+				bybit.Fail("GetBookTicker", errors.New("timeout"), 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binance := newTestClient(tt.binance[0], tt.binance[1], tt.binance[2])
+			bybit := newTestClient(tt.bybit[0], tt.bybit[1], tt.bybit[2])
+
+			s := newTestStrategy(t, binance, bybit)
+
+			if tt.prepare != nil {
+				tt.prepare(binance, bybit)
+			}
+
+			s.logic(context.Background())
+
+			if got := orders(binance); !slices.Equal(got, tt.wantBinance) {
+				t.Errorf("binance orders = %v, want %v", got, tt.wantBinance)
+			}
+
+			if got := orders(bybit); !slices.Equal(got, tt.wantBybit) {
+				t.Errorf("bybit orders = %v, want %v", got, tt.wantBybit)
+			}
+		})
+	}
+}
+
+func TestArbitrageStrategy_logicExpiredLeg(t *testing.T) {
+	binance := newTestClient("20000", "19999", "20001")
+	bybit := newTestClient("20100", "20099", "20101")
+
+	s := newTestStrategy(t, binance, bybit)
+
+	// the bid moved away before the sell arrived, the IOC order expires unfilled
+	o := bestOpportunity([2]*models.BookTicker{
+		{BidPrice: d("19999"), BidQuantity: d("1"), AskPrice: d("20001"), AskQuantity: d("1")},
+		{BidPrice: d("20200"), BidQuantity: d("1"), AskPrice: d("20201"), AskQuantity: d("1")},
+	}, s.fees)
+
+	s.trade(context.Background(), o, d("0.1"))
+
+	if got, want := orders(binance), []string{"BUY LIMIT 0.1", "SELL MARKET 0.1"}; !slices.Equal(got, want) {
+		t.Errorf("binance orders = %v, want %v", got, want)
+	}
+
+	if sell := bybit.Orders(); len(sell) != 1 || sell[0].Status != models.OrderStatusTypeExpired {
+		t.Errorf("bybit orders = %+v, want an expired sell", sell)
+	}
+}
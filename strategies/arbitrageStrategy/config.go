@@ -0,0 +1,88 @@
+package arbitrageStrategy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_ARBITRAGE_SYMBOL"        envDefault:"BTCUSDT"` // trading pair symbol, the same on both exchanges
+	QuantityPrecision uint   `env:"STRATEGIES_ARBITRAGE_QTY_PRECISION" envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Exchanges         []string          `env:"STRATEGIES_ARBITRAGE_EXCHANGES"            envSeparator:","`                          // the two exchanges to trade between, both have to quote and trade
+	Fees              []decimal.Decimal `env:"STRATEGIES_ARBITRAGE_FEES"                 envDefault:"0.001,0.001" envSeparator:","` // taker fee share of each exchange, in the same order
+	Interval          time.Duration     `env:"STRATEGIES_ARBITRAGE_INTERVAL"             envDefault:"2s"`                           // how often the books are compared
+	BaseCoinForAmount bool              `env:"STRATEGIES_ARBITRAGE_BASE_COIN_FOR_AMOUNT" envDefault:"false"`                        // whether to use base coin for ORDER_AMOUNT
+	OrderAmount       decimal.Decimal   `env:"STRATEGIES_ARBITRAGE_ORDER_AMOUNT"         envDefault:"0.00005"`                      // largest quote coin amount of a trade
+	MinSpread         decimal.Decimal   `env:"STRATEGIES_ARBITRAGE_MIN_SPREAD"           envDefault:"0.001"`                        // net spread share after fees a trade needs
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	for i := range cfg.Exchanges {
+		cfg.Exchanges[i] = strings.ToLower(strings.TrimSpace(cfg.Exchanges[i]))
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if len(cfg.Exchanges) != 2 || cfg.Exchanges[0] == cfg.Exchanges[1] {
+		return fmt.Errorf("two different exchanges are required, got %v", cfg.Exchanges)
+	}
+
+	if len(cfg.Fees) != len(cfg.Exchanges) {
+		return fmt.Errorf("a fee per exchange is required, got %d fees", len(cfg.Fees))
+	}
+
+	for i, fee := range cfg.Fees {
+		if fee.IsNegative() || fee.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			return fmt.Errorf("fee of %s must be between 0 and 1, got %s", cfg.Exchanges[i], fee)
+		}
+	}
+
+	if !cfg.OrderAmount.IsPositive() {
+		return fmt.Errorf("order amount must be positive, got %s", cfg.OrderAmount)
+	}
+
+	if cfg.MinSpread.IsNegative() {
+		return fmt.Errorf("min spread must not be negative, got %s", cfg.MinSpread)
+	}
+
+	return nil
+}
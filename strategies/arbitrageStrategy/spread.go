@@ -0,0 +1,51 @@
+package arbitrageStrategy
+
+import (
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+// opportunity is buying at the ask of one exchange and selling at the bid of
+// the other, exchanges are indexes into the configured ones
+type opportunity struct {
+	buy, sell int
+	ask, bid  decimal.Decimal
+	spread    decimal.Decimal // net share earned after the fees of both legs
+	quantity  decimal.Decimal // what both top levels of the books can take
+}
+
+// netSpread is the share earned by buying at ask and selling at bid after fees
+func netSpread(ask, bid, buyFee, sellFee decimal.Decimal) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+
+	cost := ask.Mul(one.Add(buyFee))
+	if !cost.IsPositive() {
+		return decimal.Zero
+	}
+
+	return bid.Mul(one.Sub(sellFee)).Sub(cost).Div(cost)
+}
+
+// bestOpportunity compares both directions between the two books
+func bestOpportunity(tickers [2]*models.BookTicker, fees [2]decimal.Decimal) opportunity {
+	var best opportunity
+
+	for buy := range tickers {
+		sell := 1 - buy
+
+		o := opportunity{
+			buy:      buy,
+			sell:     sell,
+			ask:      tickers[buy].AskPrice,
+			bid:      tickers[sell].BidPrice,
+			spread:   netSpread(tickers[buy].AskPrice, tickers[sell].BidPrice, fees[buy], fees[sell]),
+			quantity: decimal.Min(tickers[buy].AskQuantity, tickers[sell].BidQuantity),
+		}
+
+		if buy == 0 || o.spread.GreaterThan(best.spread) {
+			best = o
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,49 @@
+package arbitrageStrategy
+
+import (
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+var d = strategytest.D
+
+func Test_netSpread(t *testing.T) {
+	tests := []struct {
+		name     string
+		ask, bid string
+		fee      string
+		want     string
+	}{
+		{name: "no fees", ask: "100", bid: "101", fee: "0", want: "0.01"},
+		{name: "fees eat the spread", ask: "100", bid: "102", fee: "0.01", want: "-0.000198"},
+		{name: "negative", ask: "100", bid: "99", fee: "0", want: "-0.01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := netSpread(d(tt.ask), d(tt.bid), d(tt.fee), d(tt.fee)).Round(6)
+			if !got.Equal(d(tt.want)) {
+				t.Errorf("netSpread() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_bestOpportunity(t *testing.T) {
+	tickers := [2]*models.BookTicker{
+		{BidPrice: d("101"), BidQuantity: d("2"), AskPrice: d("101.1"), AskQuantity: d("3")},
+		{BidPrice: d("99.9"), BidQuantity: d("1"), AskPrice: d("100"), AskQuantity: d("0.5")},
+	}
+
+	o := bestOpportunity(tickers, [2]decimal.Decimal{decimal.Zero, decimal.Zero})
+	if o.buy != 1 || o.sell != 0 || !o.ask.Equal(d("100")) || !o.bid.Equal(d("101")) {
+		t.Errorf("bestOpportunity() = %+v, want buying at 100 on the second and selling at 101 on the first", o)
+	}
+
+	if !o.quantity.Equal(d("0.5")) {
+		t.Errorf("bestOpportunity() quantity = %v, want 0.5", o.quantity)
+	}
+}
@@ -0,0 +1,67 @@
+package arbitrageStrategy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// exchange is one side of the arbitrage
+type exchange struct {
+	name   string
+	client clients.HttpClient
+}
+
+type ArbitrageStrategy struct {
+	name      string
+	cfg       *Config
+	exchanges [2]exchange
+	fees      [2]decimal.Decimal
+	z         *zap.SugaredLogger
+
+	mu sync.Mutex
+}
+
+// NewArbitrageStrategy builds the strategy on the clients of the configured
+// exchanges, it fails if an exchange is disabled or its client can't quote the
+// symbol, e.g. the bybit one is a stub yet
+func NewArbitrageStrategy(deps strategies.Dependencies, cfg *Config) (*ArbitrageStrategy, error) {
+	z := zap.S().With("context", "ArbitrageStrategy", "symbol", cfg.Symbol)
+
+	s := &ArbitrageStrategy{
+		name: "arbitrage strategy",
+		cfg:  cfg,
+		z:    z,
+	}
+
+	for i, name := range cfg.Exchanges {
+		c, err := deps.Exchange(name)
+		if err != nil {
+			return nil, fmt.Errorf("deps.Exchange: %w", err)
+		}
+
+		if _, err := c.GetBookTicker(context.Background(), cfg.Symbol); err != nil {
+			return nil, fmt.Errorf("exchange %s can't quote %s: %w", name, cfg.Symbol, err)
+		}
+
+		s.exchanges[i] = exchange{name: name, client: c}
+		s.fees[i] = cfg.Fees[i]
+	}
+
+	return s, nil
+}
+
+// Register adds the strategy to the registry as "arbitrage"
+func Register(r *strategies.Registry) {
+	strategies.RegisterWith(r, "arbitrage", NewConfigFromEnv, NewArbitrageStrategy)
+}
+
+func (s *ArbitrageStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol + " " + strings.Join(s.cfg.Exchanges, "/")
+}
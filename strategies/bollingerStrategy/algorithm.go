@@ -0,0 +1,119 @@
+package bollingerStrategy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+func (s *BollingerStrategy) Start(ctx context.Context) error {
+	go s.position.StopLoss(ctx)
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	stopLossTicker := time.NewTicker(s.cfg.StopLossUpdatePeriod)
+	defer stopLossTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-stopLossTicker.C:
+			go s.position.StopLoss(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+type signal string
+
+const (
+	signalBuy       signal = "buy"
+	signalSell      signal = "sell"
+	signalDoNothing signal = "do-nothing"
+)
+
+func (s *BollingerStrategy) logic(ctx context.Context) {
+	klines, err := s.client.GetKlinesCloses(
+		ctx,
+		s.cfg.Symbol,
+		s.cfg.KlinesInterval,
+	)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get klines",
+			"interval", s.cfg.KlinesInterval,
+			"error", err.Error(),
+		)
+	}
+
+	if l := len(klines); l < s.cfg.Period {
+		s.z.Warnw(
+			"not enough klines received",
+			"count", l,
+		)
+
+		return
+	}
+
+	signal, b := s.getSignal(klines)
+	price := utils.RoundPrecision(decimal.NewFromFloat(klines[len(klines)-1]), s.cfg.PricePrecision)
+	amount := s.position.OrderQuantity(price)
+
+	s.z.Infow(
+		"new signal",
+		"signal", signal,
+		"price", price,
+		"mode", s.cfg.Mode,
+		"upper", b.upper,
+		"middle", b.middle,
+		"lower", b.lower,
+		"width", b.width(),
+	)
+
+	switch {
+	case signal == signalBuy && s.confirmer.Allows(ctx, timeframes.Up):
+		_ = s.position.Buy(ctx, price, amount)
+	case signal == signalSell && s.confirmer.Allows(ctx, timeframes.Down):
+		_ = s.position.Sell(ctx, price, amount)
+	}
+}
+
+// getSignal compares the last close with the bands: in breakout mode a close
+// above the upper band buys and below the lower one sells, in reversion mode
+// it is the other way around. Bands narrower or wider than the configured
+// width filters give no signal.
+func (s *BollingerStrategy) getSignal(klines []float64) (signal, bands) {
+	b := newBands(klines, s.cfg.Period, s.cfg.Deviation)
+	last := klines[len(klines)-1]
+
+	if width := b.width(); width < s.cfg.MinBandWidth || s.cfg.MaxBandWidth != 0 && width > s.cfg.MaxBandWidth {
+		return signalDoNothing, b
+	}
+
+	above, below := last >= b.upper, last <= b.lower
+
+	// the bands of a series without any change collapse into the price
+	if above && below {
+		return signalDoNothing, b
+	}
+
+	switch {
+	case s.cfg.Mode == bandsModeBreakout && above, s.cfg.Mode == bandsModeReversion && below:
+		return signalBuy, b
+	case s.cfg.Mode == bandsModeBreakout && below, s.cfg.Mode == bandsModeReversion && above:
+		return signalSell, b
+	default:
+		return signalDoNothing, b
+	}
+}
+
+func (s *BollingerStrategy) Stop(ctx context.Context) error {
+	return nil
+}
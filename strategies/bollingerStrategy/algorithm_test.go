@@ -0,0 +1,161 @@
+package bollingerStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+func newTestConfig(mode bandsMode) *Config {
+	cfg := strategytest.NewConfig[Config]()
+	cfg.Mode, cfg.Period, cfg.Deviation = mode, 20, 2
+
+	return cfg
+}
+
+// closesEndingAt is a series swinging by 1 around 100 with the given last close
+func closesEndingAt(last float64) []float64 {
+	closes := make([]float64, 30)
+	for i := range closes {
+		closes[i] = 99 + float64(i%2)*2
+	}
+
+	closes[len(closes)-1] = last
+
+	return closes
+}
+
+var (
+	spikeUp   = closesEndingAt(110)
+	spikeDown = closesEndingAt(90)
+	inside    = closesEndingAt(100.5)
+)
+
+func TestConfig_validate(t *testing.T) {
+	newConfig := func() *Config { return newTestConfig(bandsModeReversion) }
+
+	strategytest.RunValidate(t, newConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "unknown mode", Modify: func(cfg *Config) { cfg.Mode = "squeeze" }, WantErr: true},
+		{Name: "short period", Modify: func(cfg *Config) { cfg.Period = 1 }, WantErr: true},
+		{Name: "zero deviation", Modify: func(cfg *Config) { cfg.Deviation = 0 }, WantErr: true},
+		{Name: "width filters", Modify: func(cfg *Config) { cfg.MinBandWidth, cfg.MaxBandWidth = 0.01, 0.1 }},
+		{Name: "swapped width filters", Modify: func(cfg *Config) { cfg.MinBandWidth, cfg.MaxBandWidth = 0.1, 0.01 }, WantErr: true},
+	})
+}
+
+func TestBollingerStrategy_getSignal(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         bandsMode
+		closes       []float64
+		minBandWidth float64
+		maxBandWidth float64
+		want         signal
+	}{
+		{name: "breakout above", mode: bandsModeBreakout, closes: spikeUp, want: signalBuy},
+		{name: "breakout below", mode: bandsModeBreakout, closes: spikeDown, want: signalSell},
+		{name: "breakout inside", mode: bandsModeBreakout, closes: inside, want: signalDoNothing},
+		{name: "reversion above", mode: bandsModeReversion, closes: spikeUp, want: signalSell},
+		{name: "reversion below", mode: bandsModeReversion, closes: spikeDown, want: signalBuy},
+		{name: "reversion inside", mode: bandsModeReversion, closes: inside, want: signalDoNothing},
+		{name: "constant series", mode: bandsModeReversion, closes: make([]float64, 30), want: signalDoNothing},
+		{name: "bands too narrow", mode: bandsModeBreakout, closes: spikeUp, minBandWidth: 0.2, want: signalDoNothing},
+		{name: "bands too wide", mode: bandsModeReversion, closes: spikeDown, maxBandWidth: 0.05, want: signalDoNothing},
+		{name: "bands within filters", mode: bandsModeReversion, closes: spikeDown, minBandWidth: 0.05, maxBandWidth: 0.2, want: signalBuy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(tt.mode)
+			cfg.MinBandWidth, cfg.MaxBandWidth = tt.minBandWidth, tt.maxBandWidth
+
+			got, b := NewBollingerStrategy(mocks.NewHttpClient(), cfg).getSignal(tt.closes)
+			if got != tt.want {
+				t.Errorf("getSignal() = %v (bands %+v, width %v), want %v", got, b, b.width(), tt.want)
+			}
+		})
+	}
+}
+
+func TestBollingerStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name          string
+		closes        []float64
+		position      string
+		holdings      string
+		baseCoin      bool
+		wantBuy       string
+		wantSell      string
+		klinesFailure error
+	}{
+		{name: "buy below the lower band", closes: spikeDown, wantBuy: "0.1"},
+		{name: "sell above the upper band", closes: spikeUp, position: "1", wantSell: "0.1"},
+		{name: "sell no more than bought", closes: spikeUp, position: "0.045", holdings: "1", wantSell: "0.045"},
+		{name: "coins not bought aren't sold", closes: spikeUp, holdings: "1"},
+		{name: "nothing to sell", closes: spikeUp},
+		{name: "inside the bands", closes: inside, position: "1"},
+		{name: "amount in base coin", closes: spikeDown, baseCoin: true, wantBuy: "0.111"},
+		{name: "not enough klines", closes: spikeDown[20:]},
+		{name: "klines failure", closes: spikeDown, klinesFailure: errors.New("timeout")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(strategytest.Symbol, "15m", tt.closes)
+
+			if tt.klinesFailure != nil {
+				c.Fail("GetKlinesCloses", tt.klinesFailure, 1)
+			}
+
+			cfg := newTestConfig(bandsModeReversion)
+			cfg.BaseCoinForAmount = tt.baseCoin
+
+			if tt.baseCoin {
+				// 10 USDT worth of the coin at the last close of 90
+				cfg.OrderAmount = decimal.NewFromInt(10)
+			}
+
+			s := NewBollingerStrategy(c, cfg)
+
+			if tt.position != "" {
+				strategytest.Hold(t, s.position, c, tt.position)
+			}
+
+			if tt.holdings != "" {
+				c.SetBalance(strategytest.Coin, tt.holdings, "0")
+			}
+
+			s.logic(context.Background())
+
+			strategytest.AssertMarketOrder(t, c.CallsTo("NewMarketBuyOrder"), tt.wantBuy)
+			strategytest.AssertMarketOrder(t, c.CallsTo("NewMarketSellOrder"), tt.wantSell)
+		})
+	}
+}
+
+func TestBollingerStrategy_TrendConfirmation(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetCloses(strategytest.Symbol, "15m", spikeDown)
+	c.SetCloses(strategytest.Symbol, "1d", closesEndingAt(80))
+
+	cfg := newTestConfig(bandsModeReversion)
+	cfg.TrendIntervals, cfg.TrendPeriod = []string{"1d"}, 10
+
+	NewBollingerStrategy(c, cfg).logic(context.Background())
+
+	if calls := c.CallsTo("NewMarketBuyOrder"); len(calls) != 0 {
+		t.Errorf("bought %v against the daily trend", calls)
+	}
+
+	c.SetCloses(strategytest.Symbol, "1d", closesEndingAt(120))
+	NewBollingerStrategy(c, cfg).logic(context.Background())
+
+	if calls := c.CallsTo("NewMarketBuyOrder"); len(calls) != 1 {
+		t.Errorf("placed %d buys with the daily trend, want 1", len(calls))
+	}
+}
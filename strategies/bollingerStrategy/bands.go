@@ -0,0 +1,51 @@
+package bollingerStrategy
+
+import (
+	"math"
+
+	"github.com/cinar/indicator"
+)
+
+type bandsMode string
+
+const (
+	// bandsModeBreakout follows the price leaving the bands
+	bandsModeBreakout bandsMode = "breakout"
+	// bandsModeReversion expects the price to return inside the bands
+	bandsModeReversion bandsMode = "reversion"
+)
+
+type bands struct {
+	middle float64
+	upper  float64
+	lower  float64
+}
+
+// newBands calculates the bands of the last kline, closes must hold at least period values
+func newBands(closes []float64, period int, deviation float64) bands {
+	sma := indicator.Sma(period, closes)
+	std := indicator.StdFromSma(period, closes, sma)
+
+	last := len(closes) - 1
+	middle, dev := sma[last], std[last]
+
+	// rounding errors of a constant series turn the variance slightly negative
+	if math.IsNaN(dev) {
+		dev = 0
+	}
+
+	return bands{
+		middle: middle,
+		upper:  middle + deviation*dev,
+		lower:  middle - deviation*dev,
+	}
+}
+
+// width is the bands distance relative to the middle band
+func (b bands) width() float64 {
+	if b.middle == 0 {
+		return 0
+	}
+
+	return (b.upper - b.lower) / b.middle
+}
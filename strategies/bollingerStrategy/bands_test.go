@@ -0,0 +1,53 @@
+package bollingerStrategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewBands(t *testing.T) {
+	tests := []struct {
+		name      string
+		closes    []float64
+		period    int
+		deviation float64
+		want      bands
+	}{
+		{
+			name:      "last period only",
+			closes:    []float64{100, 1, 2, 3, 4, 5},
+			period:    5,
+			deviation: 2,
+			want:      bands{middle: 3, upper: 3 + 2*math.Sqrt2, lower: 3 - 2*math.Sqrt2},
+		},
+		{
+			name:      "constant series",
+			closes:    []float64{0.3, 0.3, 0.3, 0.3},
+			period:    3,
+			deviation: 2,
+			want:      bands{middle: 0.3, upper: 0.3, lower: 0.3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newBands(tt.closes, tt.period, tt.deviation)
+
+			for _, pair := range [][2]float64{{got.middle, tt.want.middle}, {got.upper, tt.want.upper}, {got.lower, tt.want.lower}} {
+				if math.Abs(pair[0]-pair[1]) > 1e-6 {
+					t.Fatalf("newBands() = %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBands_width(t *testing.T) {
+	if got := (bands{middle: 100, upper: 110, lower: 90}).width(); got != 0.2 {
+		t.Errorf("width() = %v, want 0.2", got)
+	}
+
+	if got := (bands{}).width(); got != 0 {
+		t.Errorf("width() of zero bands = %v, want 0", got)
+	}
+}
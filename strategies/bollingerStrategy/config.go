@@ -0,0 +1,96 @@
+package bollingerStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_BOLLINGER_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
+	PricePrecision    uint   `env:"STRATEGIES_BOLLINGER_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_BOLLINGER_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Interval             time.Duration   `env:"STRATEGIES_BOLLINGER_INTERVAL"                envDefault:"5m"`        // polling interval
+	StopLossUpdatePeriod time.Duration   `env:"STRATEGIES_BOLLINGER_STOP_LOSS_UPDATE_PERIOD" envDefault:"180m"`      // how often to update stop loss
+	StopLossShare        decimal.Decimal `env:"STRATEGIES_BOLLINGER_STOP_LOSS_SHARE"         envDefault:"0.85"`      // stop loss share of actual price
+	BaseCoinForAmount    bool            `env:"STRATEGIES_BOLLINGER_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`     // whether to use base coin for ORDER_AMOUNT
+	OrderAmount          decimal.Decimal `env:"STRATEGIES_BOLLINGER_ORDER_AMOUNT"            envDefault:"0.00005"`   // quote coin amount for placing order
+	MaxOrdersAmount      decimal.Decimal `env:"STRATEGIES_BOLLINGER_MAX_ORDERS_AMOUNT"       envDefault:"100"`       // amount available for trading
+	KlinesInterval       string          `env:"STRATEGIES_BOLLINGER_KLINES_INTERVAL"         envDefault:"15m"`       // klines interval
+	Mode                 bandsMode       `env:"STRATEGIES_BOLLINGER_MODE"                    envDefault:"reversion"` // breakout or reversion
+	Period               int             `env:"STRATEGIES_BOLLINGER_PERIOD"                  envDefault:"20"`        // number of klines the middle band is averaged over
+	Deviation            float64         `env:"STRATEGIES_BOLLINGER_DEVIATION"               envDefault:"2"`         // bands distance from the middle in standard deviations
+	MinBandWidth         float64         `env:"STRATEGIES_BOLLINGER_MIN_BAND_WIDTH"          envDefault:"0"`         // no signals while (upper - lower) / middle is below, 0 disables
+	MaxBandWidth         float64         `env:"STRATEGIES_BOLLINGER_MAX_BAND_WIDTH"          envDefault:"0"`         // no signals while (upper - lower) / middle is above, 0 disables
+	TrendIntervals       []string        `env:"STRATEGIES_BOLLINGER_TREND_INTERVALS"         envSeparator:","`       // higher timeframes whose trend must agree with signals, e.g. 4h,1d
+	TrendPeriod          int             `env:"STRATEGIES_BOLLINGER_TREND_PERIOD"            envDefault:"50"`        // EMA period of the higher timeframes trend
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Mode != bandsModeBreakout && cfg.Mode != bandsModeReversion {
+		return fmt.Errorf("unknown bands mode %q", cfg.Mode)
+	}
+
+	if cfg.Period < 2 {
+		return fmt.Errorf("period must be at least 2, got %d", cfg.Period)
+	}
+
+	if cfg.Deviation <= 0 {
+		return fmt.Errorf("deviation must be positive, got %v", cfg.Deviation)
+	}
+
+	if cfg.MinBandWidth < 0 || cfg.MaxBandWidth < 0 {
+		return fmt.Errorf("band width filters must not be negative")
+	}
+
+	if cfg.MaxBandWidth != 0 && cfg.MinBandWidth >= cfg.MaxBandWidth {
+		return fmt.Errorf("min band width %v must be below max band width %v", cfg.MinBandWidth, cfg.MaxBandWidth)
+	}
+
+	if len(cfg.TrendIntervals) > 0 && cfg.TrendPeriod < 1 {
+		return fmt.Errorf("trend period must be positive, got %d", cfg.TrendPeriod)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package bollingerStrategy
+
+import (
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/position"
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
+	"go.uber.org/zap"
+)
+
+type BollingerStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	test   bool
+	z      *zap.SugaredLogger
+
+	confirmer *timeframes.Confirmer
+	position  *position.Position
+}
+
+func NewBollingerStrategy(c clients.HttpClient, cfg *Config) *BollingerStrategy {
+	z := zap.S().With("context", "BollingerStrategy", "symbol", cfg.Symbol)
+
+	return &BollingerStrategy{
+		name:   "Bollinger Bands strategy",
+		cfg:    cfg,
+		client: c,
+		z:      z,
+
+		confirmer: timeframes.NewConfirmer("bollinger", c, cfg.Symbol, cfg.TrendIntervals, timeframes.EMATrend(cfg.TrendPeriod)),
+		position: position.New(c, position.Config{
+			Symbol:            cfg.Symbol,
+			Coin:              cfg.Coins.Quote,
+			PricePrecision:    cfg.PricePrecision,
+			QuantityPrecision: cfg.QuantityPrecision,
+			BaseCoinForAmount: cfg.BaseCoinForAmount,
+			OrderAmount:       cfg.OrderAmount,
+			MaxOrdersAmount:   cfg.MaxOrdersAmount,
+			StopLossShare:     cfg.StopLossShare,
+		}, z),
+	}
+}
+
+// Register adds the strategy to the registry as "bollinger"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "bollinger", NewConfigFromEnv, NewBollingerStrategy)
+}
+
+func (s *BollingerStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol
+}
@@ -0,0 +1,265 @@
+package dcaStrategy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/execution"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/cinar/indicator"
+	"github.com/shopspring/decimal"
+)
+
+func (s *DCAStrategy) Start(ctx context.Context) error {
+	buyTimer := time.NewTimer(time.Until(s.cfg.Schedule.Next(time.Now())))
+	defer buyTimer.Stop()
+
+	takeProfitTicker := time.NewTicker(s.cfg.Interval)
+	defer takeProfitTicker.Stop()
+
+	for {
+		select {
+		case <-buyTimer.C:
+			go s.buy(ctx)
+
+			buyTimer.Reset(time.Until(s.cfg.Schedule.Next(time.Now())))
+		case <-takeProfitTicker.C:
+			go s.takeProfit(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// buy executes a scheduled buy of the order amount increased by the dip
+// multipliers and cut to what is left of the budget. The lock is released
+// while the order executes, so a long TWAP doesn't hold back the take profit.
+func (s *DCAStrategy) buy(ctx context.Context) {
+	amount, price, multiplier, ok := s.nextBuy(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := s.executor.Execute(
+		ctx,
+		execution.Order{
+			Symbol:            s.cfg.Symbol,
+			Side:              models.SideTypeBuy,
+			Quantity:          amount,
+			QuantityPrecision: s.cfg.QuantityPrecision,
+			PricePrecision:    s.cfg.PricePrecision,
+		},
+		s.cfg.Execution,
+	)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buying = false
+
+	if err != nil {
+		s.z.Warnw(
+			"failed to execute order",
+			"side", "buy",
+			"algorithm", s.cfg.Execution.Algorithm,
+			"price", price,
+			"quantity", amount,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	// failed child orders are logged by the executor
+	if !report.Executed.IsPositive() {
+		return
+	}
+
+	quantity, cost := report.Executed, report.Cost
+	if cost.IsZero() {
+		cost = quantity.Mul(price)
+	}
+	s.quantity = s.quantity.Add(quantity)
+	s.cost = s.cost.Add(cost)
+
+	s.z.Infow(
+		"scheduled buy",
+		"price", price,
+		"quantity", quantity,
+		"multiplier", multiplier,
+		"position", s.quantity,
+		"cost", s.cost,
+	)
+}
+
+// nextBuy calculates the quantity of the scheduled buy and marks the buy as
+// executing, ok is false if there is nothing to buy. A buy scheduled while
+// the previous one still executes is skipped, the budget isn't known yet.
+func (s *DCAStrategy) nextBuy(ctx context.Context) (amount, price, multiplier decimal.Decimal, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buying {
+		s.z.Warnw("previous buy is still executing, the buy is skipped")
+		return amount, price, multiplier, false
+	}
+
+	price, err := s.client.GetPrice(ctx, s.cfg.Symbol)
+	if err != nil {
+		s.z.Warnw("failed to get price", "error", err.Error())
+		return amount, price, multiplier, false
+	}
+
+	amount = s.cfg.OrderAmount
+	if s.cfg.BaseCoinForAmount {
+		amount = utils.QuoteQtyFromBaseQty(price, s.cfg.OrderAmount)
+	}
+
+	multiplier = s.dipMultiplier(ctx, price)
+	amount = amount.Mul(multiplier)
+
+	if !s.cfg.Budget.IsZero() {
+		left := s.cfg.Budget.Sub(s.cost)
+		if !left.IsPositive() {
+			s.z.Infow("budget exhausted", "budget", s.cfg.Budget, "cost", s.cost)
+			return amount, price, multiplier, false
+		}
+
+		amount = decimal.Min(amount, utils.QuoteQtyFromBaseQty(price, left))
+	}
+
+	amount = utils.TruncatePrecision(amount, s.cfg.QuantityPrecision)
+	if !amount.IsPositive() {
+		s.z.Warnw("order amount is too small", "price", price, "multiplier", multiplier)
+		return amount, price, multiplier, false
+	}
+
+	s.buying = true
+
+	return amount, price, multiplier, true
+}
+
+// dipMultiplier increases the buy when the price is below the moving average
+// or down from the recent high, both multipliers apply at once. Klines
+// failures are logged and leave the buy at its regular size.
+func (s *DCAStrategy) dipMultiplier(ctx context.Context, price decimal.Decimal) decimal.Decimal {
+	multiplier := decimal.NewFromInt(1)
+
+	if s.cfg.DipMAPeriod == 0 && s.cfg.DipHighPeriod == 0 {
+		return multiplier
+	}
+
+	klines, err := s.client.GetKlines(ctx, s.cfg.Symbol, s.cfg.KlinesInterval)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get klines",
+			"interval", s.cfg.KlinesInterval,
+			"error", err.Error(),
+		)
+
+		return multiplier
+	}
+
+	if period := s.cfg.DipMAPeriod; period > 0 && len(klines) >= period {
+		closes := make([]float64, len(klines))
+		for i, k := range klines {
+			closes[i] = k.Close
+		}
+
+		sma := indicator.Sma(period, closes)
+		if ma := decimal.NewFromFloat(sma[len(sma)-1]); price.LessThan(ma) {
+			multiplier = multiplier.Mul(s.cfg.DipMAMultiplier)
+		}
+	}
+
+	if period := s.cfg.DipHighPeriod; period > 0 && len(klines) >= period {
+		high := 0.0
+		for _, k := range klines[len(klines)-period:] {
+			if k.High > high {
+				high = k.High
+			}
+		}
+
+		threshold := decimal.NewFromFloat(high).Mul(decimal.NewFromInt(1).Sub(s.cfg.DipHighShare))
+		if price.LessThanOrEqual(threshold) {
+			multiplier = multiplier.Mul(s.cfg.DipHighMultiplier)
+		}
+	}
+
+	return multiplier
+}
+
+// takeProfit sells the whole position once the price is the configured share
+// above its average cost and starts accumulating from scratch. Only what was
+// sold leaves the position when the balance is short of it.
+func (s *DCAStrategy) takeProfit(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.TakeProfitShare.IsZero() || !s.quantity.IsPositive() {
+		return
+	}
+
+	price, err := s.client.GetPrice(ctx, s.cfg.Symbol)
+	if err != nil {
+		s.z.Warnw("failed to get price for take profit", "error", err.Error())
+		return
+	}
+
+	average := s.cost.Div(s.quantity)
+	target := average.Mul(decimal.NewFromInt(1).Add(s.cfg.TakeProfitShare))
+
+	if price.LessThan(target) {
+		return
+	}
+
+	balance, _, err := s.client.GetBalance(ctx, s.cfg.Coins.Quote)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get balance for take profit",
+			"coin", s.cfg.Coins.Quote,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	quantity := utils.TruncatePrecision(decimal.Min(s.quantity, balance), s.cfg.QuantityPrecision)
+	if !quantity.IsPositive() {
+		return
+	}
+
+	order, err := s.client.NewMarketSellOrder(ctx, s.cfg.Symbol, quantity)
+	if err != nil {
+		s.z.Warnw(
+			"failed to place order",
+			"side", "sell",
+			"type", "market",
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	sold := decimal.Min(order.ExecutedQuantity, s.quantity)
+	left := s.quantity.Sub(sold)
+
+	s.z.Infow(
+		"take profit",
+		"price", price,
+		"average_cost", utils.RoundPrecision(average, s.cfg.PricePrecision),
+		"quantity", sold,
+		"left", left,
+	)
+
+	// the coins left keep their average cost
+	s.cost = s.cost.Mul(left).Div(s.quantity)
+	s.quantity = left
+}
+
+func (s *DCAStrategy) Stop(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,387 @@
+package dcaStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/execution"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/schedule"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+const testSymbol = "BTCUSDT"
+
+func newTestConfig() *Config {
+	sched, err := schedule.Parse("@daily")
+	if err != nil {
+		panic(err)
+	}
+
+	cfg := &Config{
+		Symbol:            testSymbol,
+		PricePrecision:    2,
+		QuantityPrecision: 4,
+		Schedule:          sched,
+		OrderAmount:       decimal.RequireFromString("0.01"),
+		KlinesInterval:    "1d",
+		DipMAMultiplier:   decimal.NewFromInt(2),
+		DipHighShare:      decimal.RequireFromString("0.1"),
+		DipHighMultiplier: decimal.NewFromInt(3),
+		Execution:         execution.Params{Algorithm: execution.AlgorithmMarket},
+	}
+	cfg.Coins.Quote = "BTC"
+	cfg.Coins.Base = "USDT"
+
+	return cfg
+}
+
+func newTestClient(price string) *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, price)
+	c.SetBalance("BTC", "10", "0")
+
+	return c
+}
+
+// highs builds klines with constant closes of 20000 and the given highs
+func highs(values ...float64) []*models.Kline {
+	klines := make([]*models.Kline, len(values))
+	for i, v := range values {
+		klines[i] = &models.Kline{Open: 20000, High: v, Low: 20000, Close: 20000}
+	}
+
+	return klines
+}
+
+func boughtQuantity(t *testing.T, c *mocks.HttpClient) decimal.Decimal {
+	t.Helper()
+
+	calls := c.CallsTo("NewMarketBuyOrder")
+	if len(calls) != 1 {
+		t.Fatalf("placed %d buys, want 1", len(calls))
+	}
+
+	return calls[0].Args[1].(decimal.Decimal)
+}
+
+func TestConfig_validate(t *testing.T) {
+	strategytest.RunValidate(t, newTestConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "no schedule", Modify: func(cfg *Config) { cfg.Schedule = schedule.Schedule{} }, WantErr: true},
+		{Name: "zero amount", Modify: func(cfg *Config) { cfg.OrderAmount = decimal.Zero }, WantErr: true},
+		{Name: "negative budget", Modify: func(cfg *Config) { cfg.Budget = decimal.NewFromInt(-1) }, WantErr: true},
+		{Name: "multiplier below 1", Modify: func(cfg *Config) { cfg.DipMAMultiplier = decimal.RequireFromString("0.5") }, WantErr: true},
+		{Name: "dip share of 1", Modify: func(cfg *Config) { cfg.DipHighShare = decimal.NewFromInt(1) }, WantErr: true},
+	})
+}
+
+func TestDCAStrategy_buy(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    string
+		modify   func(cfg *Config)
+		closes   []float64
+		klines   []*models.Kline
+		failure  error
+		want     string
+		wantCost string
+	}{
+		{
+			name:     "regular buy",
+			price:    "20000",
+			want:     "0.01",
+			wantCost: "200",
+		},
+		{
+			name:     "amount in base coin",
+			price:    "30000",
+			modify:   func(cfg *Config) { cfg.BaseCoinForAmount, cfg.OrderAmount = true, decimal.NewFromInt(100) },
+			want:     "0.0033",
+			wantCost: "99",
+		},
+		{
+			name:     "below the moving average",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipMAPeriod = 3 },
+			closes:   []float64{10000, 21000, 21000, 21000},
+			want:     "0.02",
+			wantCost: "400",
+		},
+		{
+			name:     "above the moving average",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipMAPeriod = 3 },
+			closes:   []float64{30000, 19000, 19000, 19000},
+			want:     "0.01",
+			wantCost: "200",
+		},
+		{
+			name:     "not enough klines for the moving average",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipMAPeriod = 5 },
+			closes:   []float64{21000, 21000, 21000},
+			want:     "0.01",
+			wantCost: "200",
+		},
+		{
+			name:     "down from the recent high",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipHighPeriod = 2 },
+			klines:   highs(30000, 22000, 22500),
+			want:     "0.03",
+			wantCost: "600",
+		},
+		{
+			name:     "old high is ignored",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipHighPeriod = 2 },
+			klines:   highs(30000, 21000, 21500),
+			want:     "0.01",
+			wantCost: "200",
+		},
+		{
+			name:     "both dips",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipMAPeriod, cfg.DipHighPeriod = 2, 2 },
+			klines:   []*models.Kline{{High: 25000, Close: 25000}, {High: 25000, Close: 24000}},
+			want:     "0.06",
+			wantCost: "1200",
+		},
+		{
+			name:     "klines failure keeps the regular size",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.DipMAPeriod = 3 },
+			failure:  errors.New("timeout"),
+			want:     "0.01",
+			wantCost: "200",
+		},
+		{
+			name:     "cut to the budget",
+			price:    "20000",
+			modify:   func(cfg *Config) { cfg.Budget = decimal.NewFromInt(150) },
+			want:     "0.0075",
+			wantCost: "150",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(tt.price)
+
+			if tt.closes != nil {
+				c.SetCloses(testSymbol, "1d", tt.closes)
+			}
+
+			if tt.klines != nil {
+				c.SetKlines(testSymbol, "1d", tt.klines)
+			}
+
+			if tt.failure != nil {
+				c.Fail("GetKlines", tt.failure, 1)
+			}
+
+			cfg := newTestConfig()
+			if tt.modify != nil {
+				tt.modify(cfg)
+			}
+
+			s := NewDCAStrategy(c, cfg)
+			s.buy(context.Background())
+
+			if got := boughtQuantity(t, c); !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("bought %v, want %v", got, tt.want)
+			}
+
+			if !s.quantity.Equal(decimal.RequireFromString(tt.want)) || !s.cost.Equal(decimal.RequireFromString(tt.wantCost)) {
+				t.Errorf("position = %v for %v, want %v for %v", s.quantity, s.cost, tt.want, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestDCAStrategy_buyBudgetExhausted(t *testing.T) {
+	c := newTestClient("20000")
+	cfg := newTestConfig()
+	cfg.Budget = decimal.NewFromInt(300)
+
+	s := NewDCAStrategy(c, cfg)
+
+	for i := 0; i < 3; i++ {
+		s.buy(context.Background())
+	}
+
+	calls := c.CallsTo("NewMarketBuyOrder")
+	if len(calls) != 2 {
+		t.Fatalf("placed %d buys, want 2", len(calls))
+	}
+
+	if got := calls[1].Args[1].(decimal.Decimal); !got.Equal(decimal.RequireFromString("0.005")) {
+		t.Errorf("last buy = %v, want 0.005", got)
+	}
+
+	if !s.cost.Equal(decimal.NewFromInt(300)) {
+		t.Errorf("cost = %v, want 300", s.cost)
+	}
+}
+
+func TestDCAStrategy_buyFailure(t *testing.T) {
+	c := newTestClient("20000")
+	c.Fail("NewMarketBuyOrder", errors.New("insufficient balance"), 1)
+
+	s := NewDCAStrategy(c, newTestConfig())
+	s.buy(context.Background())
+
+	if !s.quantity.IsZero() || !s.cost.IsZero() {
+		t.Errorf("position = %v for %v after a failed buy, want none", s.quantity, s.cost)
+	}
+}
+
+func TestDCAStrategy_takeProfit(t *testing.T) {
+	tests := []struct {
+		name     string
+		share    string
+		price    string
+		balance  string
+		wantSell string
+		wantLeft string // quantity left, the cost is 20000 each
+	}{
+		{name: "below the target", share: "0.2", price: "23900", balance: "10"},
+		{name: "target reached", share: "0.2", price: "24000", balance: "10", wantSell: "0.03", wantLeft: "0"},
+		{name: "no more than held", share: "0.2", price: "25000", balance: "0.02", wantSell: "0.02", wantLeft: "0.01"},
+		{name: "disabled", share: "0", price: "50000", balance: "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient("20000")
+			cfg := newTestConfig()
+			cfg.TakeProfitShare = decimal.RequireFromString(tt.share)
+
+			s := NewDCAStrategy(c, cfg)
+
+			// average cost of 20000 over three buys
+			for _, price := range []string{"19000", "20000", "21000"} {
+				c.SetPrice(testSymbol, price)
+				s.buy(context.Background())
+			}
+
+			c.SetPrice(testSymbol, tt.price)
+			c.SetBalance("BTC", tt.balance, "0")
+			s.takeProfit(context.Background())
+
+			calls := c.CallsTo("NewMarketSellOrder")
+
+			if tt.wantSell == "" {
+				if len(calls) != 0 {
+					t.Errorf("unexpected sells %v", calls)
+				}
+
+				if !s.quantity.Equal(decimal.RequireFromString("0.03")) {
+					t.Errorf("position = %v, want 0.03", s.quantity)
+				}
+
+				return
+			}
+
+			if len(calls) != 1 || !calls[0].Args[1].(decimal.Decimal).Equal(decimal.RequireFromString(tt.wantSell)) {
+				t.Fatalf("sells = %v, want %v", calls, tt.wantSell)
+			}
+
+			left := decimal.RequireFromString(tt.wantLeft)
+			if cost := left.Mul(decimal.NewFromInt(20000)); !s.quantity.Equal(left) || !s.cost.Equal(cost) {
+				t.Errorf("position = %v for %v after take profit, want %v for %v", s.quantity, s.cost, left, cost)
+			}
+		})
+	}
+}
+
+// partialClient executes half of the market sells
+type partialClient struct {
+	*mocks.HttpClient
+}
+
+func (c partialClient) NewMarketSellOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	order, err := c.HttpClient.NewMarketSellOrder(ctx, symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status, order.ExecutedQuantity = models.OrderStatusTypeExpired, quantity.Div(decimal.NewFromInt(2))
+
+	return order, nil
+}
+
+func TestDCAStrategy_takeProfitPartial(t *testing.T) {
+	c := newTestClient("24000")
+	cfg := newTestConfig()
+	cfg.TakeProfitShare = decimal.RequireFromString("0.2")
+
+	s := NewDCAStrategy(partialClient{c}, cfg)
+
+	// bought before at 20000
+	s.quantity, s.cost = decimal.RequireFromString("0.04"), decimal.NewFromInt(800)
+
+	s.takeProfit(context.Background())
+
+	if !s.quantity.Equal(decimal.RequireFromString("0.02")) || !s.cost.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("position = %v for %v, want the unsold 0.02 for 400", s.quantity, s.cost)
+	}
+}
+
+// blockingClient holds the market buys until released
+type blockingClient struct {
+	*mocks.HttpClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingClient) NewMarketBuyOrder(ctx context.Context, symbol string, quantity decimal.Decimal) (*models.Order, error) {
+	c.started <- struct{}{}
+	<-c.release
+
+	return c.HttpClient.NewMarketBuyOrder(ctx, symbol, quantity)
+}
+
+func TestDCAStrategy_buyExecutesUnlocked(t *testing.T) {
+	c := &blockingClient{HttpClient: newTestClient("20000"), started: make(chan struct{}), release: make(chan struct{})}
+	cfg := newTestConfig()
+	cfg.TakeProfitShare = decimal.RequireFromString("0.2")
+
+	s := NewDCAStrategy(c, cfg)
+
+	// bought before at 20000
+	s.quantity, s.cost = decimal.RequireFromString("0.01"), decimal.NewFromInt(200)
+
+	done := make(chan struct{})
+
+	go func() {
+		s.buy(context.Background())
+		close(done)
+	}()
+
+	<-c.started
+
+	// the take profit and the next scheduled buy don't wait for the execution
+	c.SetPrice(testSymbol, "24000")
+	s.takeProfit(context.Background())
+	s.buy(context.Background())
+
+	close(c.release)
+	<-done
+
+	if sells := c.CallsTo("NewMarketSellOrder"); len(sells) != 1 {
+		t.Errorf("sells = %v, want the take profit", sells)
+	}
+
+	if buys := c.CallsTo("NewMarketBuyOrder"); len(buys) != 1 {
+		t.Errorf("buys = %v, want the buy scheduled meanwhile skipped", buys)
+	}
+
+	// the execution is applied to the position left by the take profit
+	if !s.quantity.Equal(decimal.RequireFromString("0.01")) || !s.cost.Equal(decimal.NewFromInt(240)) || s.buying {
+		t.Errorf("position = %v for %v, buying %v, want 0.01 for 240", s.quantity, s.cost, s.buying)
+	}
+}
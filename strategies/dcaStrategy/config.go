@@ -0,0 +1,101 @@
+package dcaStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/execution"
+	"github.com/Minish144/crypto-trading-bot/strategies/schedule"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_DCA_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
+	PricePrecision    uint   `env:"STRATEGIES_DCA_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_DCA_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Schedule          schedule.Schedule `env:"STRATEGIES_DCA_SCHEDULE"             envDefault:"0 0 * * *"` // cron expression of the buys
+	Interval          time.Duration     `env:"STRATEGIES_DCA_INTERVAL"             envDefault:"5m"`        // how often to check take profit
+	BaseCoinForAmount bool              `env:"STRATEGIES_DCA_BASE_COIN_FOR_AMOUNT" envDefault:"false"`     // whether to use base coin for ORDER_AMOUNT
+	OrderAmount       decimal.Decimal   `env:"STRATEGIES_DCA_ORDER_AMOUNT"         envDefault:"0.00005"`   // quote coin amount of a single buy
+	Budget            decimal.Decimal   `env:"STRATEGIES_DCA_BUDGET"               envDefault:"0"`         // base coin amount a position may cost, 0 means no limit
+	TakeProfitShare   decimal.Decimal   `env:"STRATEGIES_DCA_TAKE_PROFIT_SHARE"    envDefault:"0"`         // sell the position when the price is this share above its average cost, 0 disables
+	KlinesInterval    string            `env:"STRATEGIES_DCA_KLINES_INTERVAL"      envDefault:"1d"`        // klines interval of the dip checks
+	DipMAPeriod       int               `env:"STRATEGIES_DCA_DIP_MA_PERIOD"        envDefault:"0"`         // klines of the moving average, 0 disables
+	DipMAMultiplier   decimal.Decimal   `env:"STRATEGIES_DCA_DIP_MA_MULTIPLIER"    envDefault:"1"`         // buy multiplier while the price is below the moving average
+	DipHighPeriod     int               `env:"STRATEGIES_DCA_DIP_HIGH_PERIOD"      envDefault:"0"`         // klines the recent high is taken from, 0 disables
+	DipHighShare      decimal.Decimal   `env:"STRATEGIES_DCA_DIP_HIGH_SHARE"       envDefault:"0.1"`       // share the price must be down from the recent high
+	DipHighMultiplier decimal.Decimal   `env:"STRATEGIES_DCA_DIP_HIGH_MULTIPLIER"  envDefault:"1"`         // buy multiplier while the price is down from the recent high
+	Execution         execution.Params  `envPrefix:"STRATEGIES_DCA_EXECUTION_"`                            // how the buys are executed
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Schedule.Schedule == nil {
+		return fmt.Errorf("schedule is required")
+	}
+
+	if !cfg.OrderAmount.IsPositive() {
+		return fmt.Errorf("order amount must be positive, got %s", cfg.OrderAmount)
+	}
+
+	if cfg.Budget.IsNegative() || cfg.TakeProfitShare.IsNegative() {
+		return fmt.Errorf("budget and take profit share must not be negative")
+	}
+
+	if cfg.DipMAPeriod < 0 || cfg.DipHighPeriod < 0 {
+		return fmt.Errorf("dip periods must not be negative")
+	}
+
+	if cfg.DipMAMultiplier.LessThan(decimal.NewFromInt(1)) || cfg.DipHighMultiplier.LessThan(decimal.NewFromInt(1)) {
+		return fmt.Errorf("dip multipliers must be at least 1")
+	}
+
+	if !cfg.DipHighShare.IsPositive() || cfg.DipHighShare.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("dip high share must be between 0 and 1, got %s", cfg.DipHighShare)
+	}
+
+	if err := cfg.Execution.Validate(); err != nil {
+		return fmt.Errorf("cfg.Execution.Validate: %w", err)
+	}
+
+	return nil
+}
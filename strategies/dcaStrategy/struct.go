@@ -0,0 +1,47 @@
+package dcaStrategy
+
+import (
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/execution"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type DCAStrategy struct {
+	name     string
+	cfg      *Config
+	client   clients.HttpClient
+	executor *execution.Executor
+	test     bool
+	z        *zap.SugaredLogger
+
+	// the position accumulated since the last take profit, kept in memory only
+	mu       sync.Mutex
+	quantity decimal.Decimal // coins bought
+	cost     decimal.Decimal // base coin spent on them
+	buying   bool            // a buy is executing without the lock
+}
+
+func NewDCAStrategy(c clients.HttpClient, cfg *Config) *DCAStrategy {
+	z := zap.S().With("context", "DCAStrategy", "symbol", cfg.Symbol)
+
+	return &DCAStrategy{
+		name:     "DCA strategy",
+		cfg:      cfg,
+		client:   c,
+		executor: execution.NewExecutor(c),
+		z:        z,
+	}
+}
+
+// Register adds the strategy to the registry as "dca"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "dca", NewConfigFromEnv, NewDCAStrategy)
+}
+
+func (s *DCAStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol
+}
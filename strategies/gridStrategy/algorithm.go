@@ -7,21 +7,27 @@ import (
 
 	"github.com/Minish144/crypto-trading-bot/models"
 	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
 	"go.uber.org/atomic"
 )
 
 func (s *GridStrategy) Start(ctx context.Context) error {
-	stopLoss := atomic.NewFloat64(0)
-	ordersChecksCounter := atomic.NewInt32(0)
+	stopLoss := atomic.NewPointer(&decimal.Zero)
 
 	go s.stopLoss(ctx, stopLoss)
-	go s.logic(ctx, stopLoss, ordersChecksCounter)
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	stopLossTicker := time.NewTicker(s.cfg.StopLossUpdatePeriod)
+	defer stopLossTicker.Stop()
 
 	for {
 		select {
-		case <-time.NewTicker(s.cfg.Interval).C:
-			go s.logic(ctx, stopLoss, ordersChecksCounter)
-		case <-time.NewTicker(s.cfg.StopLossUpdatePeriod).C:
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-stopLossTicker.C:
 			go s.stopLoss(ctx, stopLoss)
 		case <-ctx.Done():
 			return nil
@@ -29,7 +35,14 @@ func (s *GridStrategy) Start(ctx context.Context) error {
 	}
 }
 
-func (s *GridStrategy) logic(ctx context.Context, stopLoss *atomic.Float64, ordersChecksCounter *atomic.Int32) {
+func (s *GridStrategy) logic(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped() {
+		return
+	}
+
 	// get the current price of the symbol
 	price, err := s.client.GetPrice(ctx, s.cfg.Symbol)
 	if err != nil {
@@ -37,66 +50,291 @@ func (s *GridStrategy) logic(ctx context.Context, stopLoss *atomic.Float64, orde
 		return
 	}
 
-	amount := s.cfg.OrderAmount
-	if s.cfg.BaseCoinForAmount {
-		amount = utils.QuoteQtyFromBaseQty(price, s.cfg.OrderAmount)
+	if err := s.reconcile(ctx, price); err != nil {
+		s.z.Warnw("failed to reconcile grid", "error", err.Error())
 	}
+}
 
-	// increment checks counter
-	ordersChecksCounter.Inc()
+// stopped reports whether the grid stays flat after stop loss, it's rebuilt
+// once STOP_LOSS_COOLDOWN passes or, with no cooldown, after a restart
+func (s *GridStrategy) stopped() bool {
+	if s.stoppedAt.IsZero() {
+		return false
+	}
+
+	if s.cfg.StopLossCooldown == 0 || s.now().Before(s.stoppedAt.Add(s.cfg.StopLossCooldown)) {
+		return true
+	}
 
-	// close all orders if more checks were performed than expected
-	if ordersChecksCounter.Load() >= int32(s.cfg.OrdersCheckRetriesMax) {
-		orders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
+	s.z.Infow("stop loss cooldown passed", "stopped_at", s.stoppedAt)
+	s.stoppedAt = time.Time{}
+
+	return false
+}
+
+// reconcile brings open orders in line with the grid: fills move the empty
+// level, the grid is shifted if the price left it, then only missing levels
+// are placed and only grid orders that don't belong to any level are canceled
+func (s *GridStrategy) reconcile(ctx context.Context, price decimal.Decimal) error {
+	openOrders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
+	if err != nil {
+		return fmt.Errorf("client.GetOpenOrders: %w", err)
+	}
+
+	openOrders = s.own(openOrders)
+
+	var pending map[int]bool
+
+	switch {
+	case s.levels == nil:
+		// build the grid on the first run and after stop loss
+		lower, upper, err := s.bounds(price)
+		if err != nil {
+			return fmt.Errorf("s.bounds: %w", err)
+		}
+
+		s.levels = generateLevels(s.cfg.Mode, lower, upper, s.cfg.LevelsAmount, s.cfg.PricePrecision)
+		s.empty = nearestLevel(s.levels, price)
+
+		s.z.Infow(
+			"grid created",
+			"mode", s.cfg.Mode,
+			"lower", lower,
+			"upper", upper,
+			"levels", len(s.levels),
+		)
+	case !inRange(s.levels, price):
+		lower, upper := s.shiftedBounds(price)
+		s.levels = generateLevels(s.cfg.Mode, lower, upper, s.cfg.LevelsAmount, s.cfg.PricePrecision)
+		s.empty = nearestLevel(s.levels, price)
+
+		s.z.Infow(
+			"grid shifted",
+			"price", price,
+			"lower", lower,
+			"upper", upper,
+		)
+	default:
+		pending = s.processFilledOrders(ctx, openOrders)
+	}
+
+	s.syncOrders(ctx, openOrders, pending)
+
+	return nil
+}
+
+// own keeps the open orders placed by the grid and forgets the placed ones
+// that aren't open anymore, orders of other strategies and manual ones are
+// never matched to levels nor canceled
+func (s *GridStrategy) own(openOrders []*models.Order) []*models.Order {
+	var (
+		res  = make([]*models.Order, 0, len(s.orders))
+		open = make(map[int64]struct{}, len(s.orders))
+	)
+
+	for _, order := range openOrders {
+		if _, ok := s.orders[order.OrderID]; ok {
+			res = append(res, order)
+			open[order.OrderID] = struct{}{}
+		}
+	}
+
+	for id := range s.orders {
+		if _, ok := open[id]; !ok {
+			delete(s.orders, id)
+		}
+	}
+
+	return res
+}
+
+// processFilledOrders resolves tracked orders that are no longer open, the level
+// of the latest filled one becomes the empty level. It returns the levels
+// whose orders couldn't be resolved, they are left untouched until next tick
+func (s *GridStrategy) processFilledOrders(ctx context.Context, openOrders []*models.Order) map[int]bool {
+	open := make(map[int64]struct{}, len(openOrders))
+	for _, order := range openOrders {
+		open[order.OrderID] = struct{}{}
+	}
+
+	var (
+		pending    = make(map[int]bool)
+		lastFilled *models.Order
+	)
+
+	for i, l := range s.levels {
+		if l.order == nil {
+			continue
+		}
+
+		if _, ok := open[l.order.OrderID]; ok {
+			continue
+		}
+
+		order, err := s.client.GetOrder(ctx, s.cfg.Symbol, l.order.OrderID)
 		if err != nil {
 			s.z.Warnw(
-				"failed to get open orders for stop loss",
+				"failed to get order",
+				"level", i,
+				"order_id", l.order.OrderID,
 				"error", err.Error(),
 			)
 
-			return
+			pending[i] = true
+
+			continue
+		}
+
+		if order.Status != models.OrderStatusTypeFilled {
+			continue
+		}
+
+		s.z.Infow(
+			"order filled",
+			"level", i,
+			"side", order.Side,
+			"price", order.Price,
+			"quantity", order.ExecutedQuantity,
+		)
+
+		// levels are walked upwards, on equal time the price went down through
+		// buys and up through sells, so the lowest buy or the highest sell is the last
+		if lastFilled == nil || order.UpdateTime > lastFilled.UpdateTime ||
+			order.UpdateTime == lastFilled.UpdateTime && order.Side == models.SideTypeSell {
+			lastFilled = order
+			s.empty = i
 		}
+	}
 
-		s.closeOrders(ctx, orders)
+	return pending
+}
+
+// syncOrders matches open grid orders to the levels, cancels the ones that
+// match nothing and places orders on the levels that miss them
+func (s *GridStrategy) syncOrders(ctx context.Context, openOrders []*models.Order, pending map[int]bool) {
+	matched := make([]bool, len(s.levels))
+
+	for i, l := range s.levels {
+		if !pending[i] {
+			l.order = nil
+		}
 	}
 
-	// check whether all orders have been filled
-	filled, err := s.allOrdersFilled(ctx)
-	if err != nil {
-		s.z.Warnw("failed to check open orders", "error", err.Error())
-		return
-	} else if !filled {
-		return
+	var stale []*models.Order
+
+	for _, order := range openOrders {
+		i, ok := s.matchLevel(order, matched, pending)
+		if !ok {
+			stale = append(stale, order)
+			continue
+		}
+
+		matched[i] = true
+		s.levels[i].order = order
 	}
 
-	// place buy and sell orders
-	sellLevels, buyLevels := s.generateGrids(price)
-	s.placeSellOrders(ctx, sellLevels, price, amount)
-	s.placeBuyOrders(ctx, buyLevels, price, amount)
+	s.closeOrders(ctx, stale)
 
-	ordersChecksCounter.Store(0)
+	for i := range s.levels {
+		side, ok := desiredSide(i, s.empty)
+		if !ok || matched[i] || pending[i] {
+			continue
+		}
+
+		s.placeOrder(ctx, i, side, s.levelQuantity(i, side))
+	}
 }
 
-func (s *GridStrategy) generateGrids(price float64) ([]float64, []float64) {
-	// calculate the price levels for each grid
-	sellLevels := make([]float64, s.cfg.GridsAmount)
-	buyLevels := make([]float64, s.cfg.GridsAmount)
+// matchLevel finds a free level with the same price that wants an order of the same side
+func (s *GridStrategy) matchLevel(order *models.Order, matched []bool, pending map[int]bool) (int, bool) {
+	for i, l := range s.levels {
+		if matched[i] || pending[i] || !l.price.Equal(order.Price) {
+			continue
+		}
 
-	for i := uint(1); i <= s.cfg.GridsAmount; i++ {
-		sellLevels[i-1] = price * (1 + s.cfg.GridSize + (float64(i) * s.cfg.GridSize))
-		buyLevels[i-1] = price * (1 - (float64(i) * s.cfg.GridSize))
+		if side, ok := desiredSide(i, s.empty); ok && side == order.Side {
+			return i, true
+		}
 	}
 
-	return sellLevels, buyLevels
+	return 0, false
 }
 
-func (s *GridStrategy) allOrdersFilled(ctx context.Context) (bool, error) {
-	orders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
+// levelQuantity sells on level i what a buy on the level below has bought
+func (s *GridStrategy) levelQuantity(i int, side models.SideType) decimal.Decimal {
+	if side == models.SideTypeSell && i > 0 {
+		return s.buyQuantity(s.levels[i-1].price)
+	}
+
+	return s.buyQuantity(s.levels[i].price)
+}
+
+// buyQuantity converts ORDER_AMOUNT into the quantity for a level
+func (s *GridStrategy) buyQuantity(price decimal.Decimal) decimal.Decimal {
+	if s.cfg.BaseCoinForAmount {
+		return utils.TruncatePrecision(utils.QuoteQtyFromBaseQty(price, s.cfg.OrderAmount), s.cfg.QuantityPrecision)
+	}
+
+	return s.cfg.OrderAmount
+}
+
+func (s *GridStrategy) placeOrder(ctx context.Context, i int, side models.SideType, quantity decimal.Decimal) {
+	l := s.levels[i]
+
+	if l.order != nil {
+		return
+	}
+
+	if !quantity.IsPositive() {
+		s.z.Warnw(
+			"failed to place order",
+			"level", i,
+			"side", side,
+			"type", "limit",
+			"price", l.price,
+			"quantity", quantity,
+			"error", "quantity is zero",
+		)
+
+		return
+	}
+
+	var (
+		order *models.Order
+		err   error
+	)
+
+	if side == models.SideTypeBuy {
+		order, err = s.client.NewLimitBuyOrder(ctx, s.cfg.Symbol, l.price, quantity)
+	} else {
+		order, err = s.client.NewLimitSellOrder(ctx, s.cfg.Symbol, l.price, quantity)
+	}
+
 	if err != nil {
-		return false, fmt.Errorf("client.GetOpenOrders: %w", err)
+		s.z.Warnw(
+			"failed to place order",
+			"level", i,
+			"side", side,
+			"type", "limit",
+			"price", l.price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return
 	}
 
-	return len(orders) == 0, nil
+	l.order = order
+	s.orders[order.OrderID] = struct{}{}
+
+	s.z.Infow(
+		"new order",
+		"level", i,
+		"side", side,
+		"type", "limit",
+		"price", l.price,
+		"quantity", quantity,
+	)
 }
 
 func (s *GridStrategy) closeOrders(ctx context.Context, orders []*models.Order) {
@@ -104,18 +342,22 @@ func (s *GridStrategy) closeOrders(ctx context.Context, orders []*models.Order)
 		if err := s.client.CloseOrder(ctx, s.cfg.Symbol, order.OrderID); err != nil {
 			s.z.Warnw(
 				"failed to close order",
-				"side", "buy",
-				"type", "limit",
+				"side", order.Side,
+				"type", order.Type,
 				"price", order.Price,
 				"quantity", order.OrigQuantity,
 				"error", err.Error(),
 			)
+
+			continue
 		}
+
+		delete(s.orders, order.OrderID)
 	}
 }
 
 // @TODO implement using stop-loss binance orders instead of market sell order
-func (s *GridStrategy) stopLoss(ctx context.Context, stopLoss *atomic.Float64) {
+func (s *GridStrategy) stopLoss(ctx context.Context, stopLoss *atomic.Pointer[decimal.Decimal]) {
 	currentPrice, err := s.client.GetPrice(ctx, s.cfg.Symbol)
 	if err != nil {
 		s.z.Warnw(
@@ -125,113 +367,86 @@ func (s *GridStrategy) stopLoss(ctx context.Context, stopLoss *atomic.Float64) {
 		return
 	}
 
-	if stopLoss.Load() != 0 && stopLoss.Load() >= currentPrice {
+	previous := *stopLoss.Load()
+
+	if !previous.IsZero() && previous.GreaterThanOrEqual(currentPrice) {
 		s.z.Infow(
 			"stop loss triggered",
 			"current_price", currentPrice,
-			"stop_loss", stopLoss,
+			"stop_loss", previous,
 		)
 
-		orders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
-		if err != nil {
+		if err := s.closeGrid(ctx, currentPrice); err != nil {
 			s.z.Warnw(
-				"failed to get open orders for stop loss",
+				"failed to close grid for stop loss",
 				"error", err.Error(),
 			)
 
 			return
 		}
-
-		s.closeOrders(ctx, orders)
-
-		go func() {
-			balance, _, err := s.client.GetBalance(ctx, s.cfg.Coins.Quote)
-			if err != nil {
-				s.z.Warnw(
-					"failed to get balance for stop loss",
-					"coin", s.cfg.Coins.Quote,
-					"error", err.Error(),
-				)
-			}
-
-			s.client.NewLimitSellOrder(ctx, s.cfg.Symbol, currentPrice, balance)
-		}()
 	}
 
-	stopLossActual := currentPrice * s.cfg.StopLossShare
+	stopLossActual := utils.RoundPrecision(currentPrice.Mul(s.cfg.StopLossShare), s.cfg.PricePrecision)
 	s.z.Infow(
 		"stop loss updated",
-		"previous", stopLoss.Load(),
+		"previous", previous,
 		"current", stopLossActual,
 	)
 
-	stopLoss.Store(stopLossActual)
+	stopLoss.Store(&stopLossActual)
 }
 
-func (s *GridStrategy) placeSellOrders(ctx context.Context, levels []float64, price, quantity float64) {
-	// placing sell orders at each grid level
-	for i, gridLevel := range levels {
-		// calculate the size of the order
-		if err := s.client.NewLimitSellOrder(
-			ctx,
-			s.cfg.Symbol,
-			utils.RoundPrecision(gridLevel, s.cfg.PricePrecision),
-			utils.RoundPrecision(quantity*(1+float64(i)*s.cfg.GridStep), s.cfg.QuantityPrecision),
-		); err != nil {
-			s.z.Warnw(
-				"failed to place order",
-				"side", "sell",
-				"type", "limit",
-				"multiplier", (1 + s.cfg.GridSize + (float64(i) * s.cfg.GridSize)),
-				"price", utils.RoundPrecision(gridLevel, s.cfg.PricePrecision),
-				"quantity", utils.RoundPrecision(quantity*(1+float64(i)*s.cfg.GridStep), s.cfg.QuantityPrecision),
-				"error", err.Error(),
-			)
+// closeGrid cancels the grid orders and sells the balance, the grid stays flat
+// until the cooldown passes instead of being rebuilt on the next tick
+func (s *GridStrategy) closeGrid(ctx context.Context, price decimal.Decimal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-			continue
-		}
+	if !s.stoppedAt.IsZero() {
+		// closed already, nothing of the grid is left
+		return nil
+	}
 
-		s.z.Infow(
-			"new order",
-			"side", "sell",
-			"type", "limit",
-			"multiplier", (1 + s.cfg.GridSize + (float64(i) * s.cfg.GridSize)),
-			"price", utils.RoundPrecision(gridLevel, s.cfg.PricePrecision),
-			"quantity", utils.RoundPrecision(quantity*(1+float64(i)*s.cfg.GridStep), s.cfg.QuantityPrecision),
-		)
+	orders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
+	if err != nil {
+		return fmt.Errorf("client.GetOpenOrders: %w", err)
 	}
+
+	s.closeOrders(ctx, s.own(orders))
+
+	s.levels = nil
+	s.stoppedAt = s.now()
+
+	s.sellForStopLoss(ctx, price)
+
+	return nil
 }
 
-func (s *GridStrategy) placeBuyOrders(ctx context.Context, levels []float64, price, quantity float64) {
-	// placing buy orders at each grid level
-	for i, gridLevel := range levels {
-		// calculate the size of the order
-		if err := s.client.NewLimitBuyOrder(
-			ctx,
-			s.cfg.Symbol,
-			utils.RoundPrecision(gridLevel, s.cfg.PricePrecision),
-			utils.RoundPrecision(quantity*(1+float64(i)*s.cfg.GridStep), s.cfg.QuantityPrecision),
-		); err != nil {
-			s.z.Warnw(
-				"failed to place order",
-				"side", "buy",
-				"type", "limit",
-				"multiplier", (1 - (float64(i) * s.cfg.GridSize)),
-				"price", utils.RoundPrecision(gridLevel, s.cfg.PricePrecision),
-				"quantity", utils.RoundPrecision(quantity*(1+float64(i)*s.cfg.GridStep), s.cfg.QuantityPrecision),
-				"error", err.Error(),
-			)
+func (s *GridStrategy) sellForStopLoss(ctx context.Context, price decimal.Decimal) {
+	balance, _, err := s.client.GetBalance(ctx, s.cfg.Coins.Quote)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get balance for stop loss",
+			"coin", s.cfg.Coins.Quote,
+			"error", err.Error(),
+		)
 
-			continue
-		}
+		return
+	}
 
-		s.z.Infow(
-			"new order",
-			"side", "buy",
+	quantity := utils.TruncatePrecision(balance, s.cfg.QuantityPrecision)
+	if !quantity.IsPositive() {
+		return
+	}
+
+	if _, err := s.client.NewLimitSellOrder(ctx, s.cfg.Symbol, price, quantity); err != nil {
+		s.z.Warnw(
+			"failed to place order",
+			"side", "sell",
 			"type", "limit",
-			"multiplier", (1 - (float64(i) * s.cfg.GridSize)),
-			"price", utils.RoundPrecision(gridLevel, s.cfg.PricePrecision),
-			"quantity", utils.RoundPrecision(quantity*(1+float64(i)*s.cfg.GridStep), s.cfg.QuantityPrecision),
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
 		)
 	}
 }
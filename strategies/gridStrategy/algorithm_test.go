@@ -0,0 +1,498 @@
+package gridStrategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+	"go.uber.org/atomic"
+)
+
+const testSymbol = "MINAUSDT"
+
+func newTestStrategy(c clients.HttpClient) *GridStrategy {
+	cfg := &Config{
+		Symbol:            testSymbol,
+		PricePrecision:    2,
+		QuantityPrecision: 2,
+		Mode:              gridModeArithmetic,
+		LowerPrice:        decimal.NewFromInt(100),
+		UpperPrice:        decimal.NewFromInt(200),
+		LevelsAmount:      5,
+		OrderAmount:       decimal.NewFromInt(1),
+		StopLossShare:     decimal.RequireFromString("0.9"),
+	}
+	cfg.Coins.Quote = "MINA"
+	cfg.Coins.Base = "USDT"
+
+	return NewGridStrategy(c, cfg)
+}
+
+func newTestClient(price string) *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, price)
+
+	return c
+}
+
+// open returns "SIDE@price" of every open order sorted by price
+func open(c *mocks.HttpClient) []string {
+	orders := c.OpenOrders(testSymbol)
+
+	res := make([]string, len(orders))
+	for i, order := range orders {
+		res[i] = fmt.Sprintf("%s@%s", order.Side, order.Price)
+	}
+
+	return res
+}
+
+func placed(c *mocks.HttpClient) int {
+	return len(c.CallsTo("NewLimitBuyOrder")) + len(c.CallsTo("NewLimitSellOrder"))
+}
+
+func canceled(c *mocks.HttpClient) int {
+	return len(c.CallsTo("CloseOrder"))
+}
+
+func TestGridStrategy_InitialOrders(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  gridMode
+		upper int64
+		price string
+		want  []string
+	}{
+		{
+			name:  "arithmetic",
+			mode:  gridModeArithmetic,
+			upper: 200,
+			price: "150",
+			want:  []string{"BUY@100", "BUY@125", "SELL@175", "SELL@200"},
+		},
+		{
+			name:  "geometric",
+			mode:  gridModeGeometric,
+			upper: 1600,
+			price: "390",
+			want:  []string{"BUY@100", "BUY@200", "SELL@800", "SELL@1600"},
+		},
+		{
+			name:  "price below the range keeps the lowest level empty",
+			mode:  gridModeArithmetic,
+			upper: 200,
+			price: "100",
+			want:  []string{"SELL@125", "SELL@150", "SELL@175", "SELL@200"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(tt.price)
+			s := newTestStrategy(c)
+			s.cfg.Mode = tt.mode
+			s.cfg.UpperPrice = decimal.NewFromInt(tt.upper)
+
+			s.logic(context.Background())
+
+			if got := open(c); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("open orders = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGridStrategy_DerivedBounds(t *testing.T) {
+	c := newTestClient("100")
+	s := newTestStrategy(c)
+	s.cfg.LowerPrice = decimal.Zero
+	s.cfg.UpperPrice = decimal.Zero
+	s.cfg.GridSize = decimal.RequireFromString("0.05")
+	s.cfg.LevelsAmount = 4
+
+	s.logic(context.Background())
+
+	// 4 levels of 5% around 100 give a range of 90..110
+	want := []string{"BUY@90", "SELL@103.33", "SELL@110"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+}
+
+func TestGridStrategy_QuantityFromBaseCoin(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+	s.cfg.BaseCoinForAmount = true
+	s.cfg.OrderAmount = decimal.NewFromInt(10)
+
+	s.logic(context.Background())
+
+	// 10 USDT per level, the sell at 175 sells what the buy at 150 would buy
+	want := map[string]string{"100": "0.1", "125": "0.08", "175": "0.06", "200": "0.05"}
+
+	for _, order := range c.OpenOrders(testSymbol) {
+		if !order.OrigQuantity.Equal(decimal.RequireFromString(want[order.Price.String()])) {
+			t.Errorf("quantity at %v = %v, want %v", order.Price, order.OrigQuantity, want[order.Price.String()])
+		}
+	}
+}
+
+func TestGridStrategy_NothingChanged(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+	c.ResetCalls()
+
+	s.logic(context.Background())
+
+	if placed(c) != 0 || canceled(c) != 0 {
+		t.Errorf("placed %d and canceled %d orders, want none", placed(c), canceled(c))
+	}
+}
+
+func TestGridStrategy_BuyFilled(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+	c.ResetCalls()
+
+	c.SetPrice(testSymbol, "124")
+	s.logic(context.Background())
+
+	want := []string{"BUY@100", "SELL@150", "SELL@175", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+
+	if placed(c) != 1 || canceled(c) != 0 {
+		t.Errorf("placed %d and canceled %d orders, want 1 and 0", placed(c), canceled(c))
+	}
+}
+
+func TestGridStrategy_SellFilled(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	c.SetPrice(testSymbol, "190")
+	s.logic(context.Background())
+
+	want := []string{"BUY@100", "BUY@125", "BUY@150", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+}
+
+func TestGridStrategy_SeveralFills(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	// price went through two levels between ticks
+	c.SetPrice(testSymbol, "100")
+	s.logic(context.Background())
+
+	want := []string{"SELL@125", "SELL@150", "SELL@175", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+}
+
+func TestGridStrategy_RoundTrip(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	for _, price := range []string{"124", "150", "124", "150"} {
+		c.SetPrice(testSymbol, price)
+		s.logic(context.Background())
+	}
+
+	want := []string{"BUY@100", "BUY@125", "SELL@175", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+
+	var filled int
+
+	for _, order := range c.Orders() {
+		if order.Status == models.OrderStatusTypeFilled {
+			filled++
+		}
+	}
+
+	if filled != 4 {
+		t.Errorf("filled %d orders, want 2 buys and 2 sells", filled)
+	}
+}
+
+func TestGridStrategy_MissingLevelReplaced(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	// order canceled outside of the bot
+	if err := c.CloseOrder(context.Background(), testSymbol, s.levels[1].order.OrderID); err != nil {
+		t.Fatal(err)
+	}
+
+	c.ResetCalls()
+	s.logic(context.Background())
+
+	calls := c.CallsTo("NewLimitBuyOrder")
+	if len(calls) != 1 || !calls[0].Args[1].(decimal.Decimal).Equal(decimal.NewFromInt(125)) {
+		t.Errorf("placed %v, want a single buy at 125", calls)
+	}
+
+	if placed(c) != 1 || canceled(c) != 0 {
+		t.Errorf("placed %d and canceled %d orders, want 1 and 0", placed(c), canceled(c))
+	}
+}
+
+func TestGridStrategy_ForeignOrdersLeftAlone(t *testing.T) {
+	c := newTestClient("150")
+	ctx := context.Background()
+
+	// a manual order off the grid and another one right on a level
+	_, _ = c.NewLimitBuyOrder(ctx, testSymbol, decimal.NewFromInt(110), decimal.NewFromInt(1))
+	_, _ = c.NewLimitBuyOrder(ctx, testSymbol, decimal.NewFromInt(125), decimal.NewFromInt(1))
+
+	s := newTestStrategy(c)
+	s.logic(ctx)
+
+	want := []string{"BUY@100", "BUY@110", "BUY@125", "BUY@125", "SELL@175", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+
+	c.SetPrice(testSymbol, "300")
+	s.logic(ctx)
+
+	if canceled(c) != 2 {
+		t.Errorf("canceled %d orders, want the 2 grid buys only", canceled(c))
+	}
+
+	want = []string{"BUY@110", "BUY@125", "BUY@250", "BUY@275", "SELL@325", "SELL@350"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders after shift = %v, want %v", got, want)
+	}
+}
+
+func TestGridStrategy_ShiftedWhenPriceLeavesRange(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	c.SetPrice(testSymbol, "300")
+	s.logic(context.Background())
+
+	want := []string{"BUY@250", "BUY@275", "SELL@325", "SELL@350"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+
+	if canceled(c) != 2 {
+		t.Errorf("canceled %d orders, want the 2 old buys", canceled(c))
+	}
+}
+
+func TestGridStrategy_FailedPlacementRetried(t *testing.T) {
+	c := newTestClient("150")
+	c.Fail("NewLimitSellOrder", errors.New("insufficient balance"), 2)
+
+	s := newTestStrategy(c)
+	s.logic(context.Background())
+
+	want := []string{"BUY@100", "BUY@125"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Fatalf("open orders = %v, want %v", got, want)
+	}
+
+	s.logic(context.Background())
+
+	want = []string{"BUY@100", "BUY@125", "SELL@175", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders after retry = %v, want %v", got, want)
+	}
+}
+
+func TestGridStrategy_UnknownOrderStateLeftAlone(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	c.SetPrice(testSymbol, "124")
+	c.Fail("GetOrder", errors.New("timeout"), 1)
+	c.ResetCalls()
+
+	s.logic(context.Background())
+
+	// the fill isn't confirmed yet, so neither the level nor its counter order are touched
+	if placed(c) != 0 || canceled(c) != 0 {
+		t.Errorf("placed %d and canceled %d orders, want none", placed(c), canceled(c))
+	}
+
+	s.logic(context.Background())
+
+	want := []string{"BUY@100", "SELL@150", "SELL@175", "SELL@200"}
+	if got := open(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("open orders = %v, want %v", got, want)
+	}
+}
+
+func TestGridStrategy_GetOpenOrdersFailure(t *testing.T) {
+	c := newTestClient("150")
+	c.Fail("GetOpenOrders", errors.New("timeout"), 1)
+
+	s := newTestStrategy(c)
+	s.logic(context.Background())
+
+	if placed(c) != 0 {
+		t.Errorf("placed %d orders without knowing open ones", placed(c))
+	}
+}
+
+func TestGridStrategy_StopLoss(t *testing.T) {
+	c := newTestClient("150")
+	c.SetBalance("MINA", "3.456", "0")
+
+	s := newTestStrategy(c)
+	stopLoss := atomic.NewPointer(&decimal.Zero)
+
+	s.logic(context.Background())
+	s.stopLoss(context.Background(), stopLoss)
+
+	if got := *stopLoss.Load(); !got.Equal(decimal.NewFromInt(135)) {
+		t.Fatalf("stop loss = %v, want 135", got)
+	}
+
+	c.ResetCalls()
+	c.SetPrice(testSymbol, "130")
+	s.stopLoss(context.Background(), stopLoss)
+
+	if canceled(c) != 4 {
+		t.Errorf("canceled %d orders, want 4", canceled(c))
+	}
+
+	calls := c.CallsTo("NewLimitSellOrder")
+	if len(calls) != 1 || !calls[0].Args[2].(decimal.Decimal).Equal(decimal.RequireFromString("3.45")) {
+		t.Errorf("stop loss sells = %v, want 3.45 MINA", calls)
+	}
+
+	if s.levels != nil {
+		t.Error("grid wasn't reset after stop loss")
+	}
+
+	if got := *stopLoss.Load(); !got.Equal(decimal.NewFromInt(117)) {
+		t.Errorf("stop loss = %v, want 117", got)
+	}
+}
+
+func TestGridStrategy_StopLossOwnOrders(t *testing.T) {
+	c := newTestClient("150")
+	c.SetBalance("MINA", "1", "0")
+
+	manual, _ := c.NewLimitSellOrder(context.Background(), testSymbol, decimal.NewFromInt(300), decimal.NewFromInt(1))
+
+	s := newTestStrategy(c)
+	stopLoss := atomic.NewPointer(&decimal.Zero)
+
+	s.logic(context.Background())
+	s.stopLoss(context.Background(), stopLoss)
+
+	c.ResetCalls()
+	c.SetPrice(testSymbol, "130")
+	s.stopLoss(context.Background(), stopLoss)
+
+	if canceled(c) != 4 {
+		t.Errorf("canceled %d orders, want the 4 grid ones", canceled(c))
+	}
+
+	for _, call := range c.CallsTo("CloseOrder") {
+		if call.Args[1] == manual.OrderID {
+			t.Errorf("canceled the manual order %d", manual.OrderID)
+		}
+	}
+}
+
+func TestGridStrategy_StopLossCooldown(t *testing.T) {
+	c := newTestClient("150")
+	c.SetBalance("MINA", "1", "0")
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	s := newTestStrategy(c)
+	s.cfg.StopLossCooldown = time.Hour
+	s.now = func() time.Time { return now }
+
+	stopLoss := atomic.NewPointer(&decimal.Zero)
+
+	s.logic(context.Background())
+	s.stopLoss(context.Background(), stopLoss)
+
+	c.SetPrice(testSymbol, "130")
+	s.stopLoss(context.Background(), stopLoss)
+
+	// the stop loss sell is left open, the grid stays flat
+	c.ResetCalls()
+	now = now.Add(59 * time.Minute)
+	s.logic(context.Background())
+
+	if placed(c) != 0 || canceled(c) != 0 {
+		t.Errorf("placed %d and canceled %d orders during cooldown, want none", placed(c), canceled(c))
+	}
+
+	// a further fall doesn't sell again while flat
+	c.SetPrice(testSymbol, "110")
+	s.stopLoss(context.Background(), stopLoss)
+
+	if calls := c.CallsTo("NewLimitSellOrder"); len(calls) != 0 {
+		t.Errorf("stop loss sells during cooldown = %v, want none", calls)
+	}
+
+	now = now.Add(time.Minute)
+	s.logic(context.Background())
+
+	if s.levels == nil || placed(c) == 0 {
+		t.Error("grid wasn't rebuilt after cooldown")
+	}
+
+	if canceled(c) != 0 {
+		t.Errorf("canceled %d orders after cooldown, want the stop loss sell left open", canceled(c))
+	}
+}
+
+func TestGridStrategy_StoppedUntilRestart(t *testing.T) {
+	c := newTestClient("150")
+	s := newTestStrategy(c)
+	stopLoss := atomic.NewPointer(&decimal.Zero)
+
+	s.logic(context.Background())
+	s.stopLoss(context.Background(), stopLoss)
+
+	c.SetPrice(testSymbol, "130")
+	s.stopLoss(context.Background(), stopLoss)
+
+	c.ResetCalls()
+	s.now = func() time.Time { return time.Now().Add(24 * 365 * time.Hour) }
+	s.logic(context.Background())
+
+	if placed(c) != 0 {
+		t.Errorf("placed %d orders without a cooldown, want none until restart", placed(c))
+	}
+}
@@ -0,0 +1,65 @@
+package gridStrategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/binance"
+	"github.com/Minish144/crypto-trading-bot/clients/binance/binancetest"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+func TestGridStrategy_Binance(t *testing.T) {
+	srv := binancetest.NewServer()
+	defer srv.Close()
+
+	srv.AddSymbol("MINAUSDT", "MINA", "USDT", "150")
+	srv.SetBalance("USDT", "1000", "0")
+	srv.SetBalance("MINA", "10", "0")
+
+	c := binance.NewBinanceClient(&binance.Config{}, false)
+	c.BaseURL = srv.URL
+
+	s := newTestStrategy(c)
+
+	ctx := context.Background()
+
+	s.logic(ctx)
+
+	if got := len(openOrders(srv)); got != 4 {
+		t.Fatalf("placed %d orders, want 4", got)
+	}
+
+	// the buy at 125 fills and a sell must appear at 150
+	srv.SetPrice("MINAUSDT", "124")
+	s.logic(ctx)
+
+	var sellAt150 bool
+
+	for _, order := range openOrders(srv) {
+		if order.Side == models.SideTypeSell && order.Price.Equal(decimal.NewFromInt(150)) {
+			sellAt150 = true
+		}
+	}
+
+	if !sellAt150 {
+		t.Errorf("no sell order at 150 after the buy at 125 filled, open orders: %+v", openOrders(srv))
+	}
+
+	if free, _ := srv.Balance("MINA"); !free.Equal(decimal.NewFromInt(8)) {
+		t.Errorf("free MINA = %v, want 8", free)
+	}
+}
+
+func openOrders(srv *binancetest.Server) []*models.Order {
+	var res []*models.Order
+
+	for _, order := range srv.Orders() {
+		if order.Status == models.OrderStatusTypeNew {
+			res = append(res, order)
+		}
+	}
+
+	return res
+}
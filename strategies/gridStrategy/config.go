@@ -6,25 +6,28 @@ import (
 
 	"github.com/Minish144/crypto-trading-bot/utils"
 	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
 	Symbol            string `env:"STRATEGIES_GRID_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
-	PricePrecision    uint   `env:"STRATEGIES_GRID_PRICE_PRECISION" envDefault:"3"`       // price float64 precision
-	QuantityPrecision uint   `env:"STRATEGIES_GRID_QTY_PRECISION"   envDefault:"3"`       // quantity float64 precision
+	PricePrecision    uint   `env:"STRATEGIES_GRID_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_GRID_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
 	Coins             struct {
 		Quote string
 		Base  string
 	}
-	Interval              time.Duration `env:"STRATEGIES_GRID_INTERVAL"                 envDefault:"5m"`      // polling interval
-	GridSize              float64       `env:"STRATEGIES_GRID_SIZE"                     envDefault:"0.01"`    // share of total funds to use for each grid level
-	GridStep              float64       `env:"STRATEGIES_GRID_STEP"                     envDefault:"0.02"`    // share increase/decrease of the price for each subsequent grid level
-	GridsAmount           uint          `env:"STRATEGIES_GRIDS_AMOUNT"                  envDefault:"3"`       // number of grids to create
-	BaseCoinForAmount     bool          `env:"STRATEGIES_GRIDS_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
-	OrderAmount           float64       `env:"STRATEGIES_GRIDS_ORDER_AMOUNT"            envDefault:"0.00005"` // quote coin amount for placing order
-	StopLossShare         float64       `env:"STRATEGIES_GRIDS_STOP_LOSS_SHARE"         envDefault:"0.9"`     // stop loss share of actual price
-	StopLossUpdatePeriod  time.Duration `env:"STRATEGIES_GRID_STOP_LOSS_UPDATE_PERIOD"  envDefault:"120m"`    // how often to update stop loss
-	OrdersCheckRetriesMax uint          `env:"STRATEGIES_GRID_ORDERS_CHECK_RETRIES_MAX" envDefault:"3"`       // how many times try to make orders unit replacing
+	Interval             time.Duration   `env:"STRATEGIES_GRID_INTERVAL"                envDefault:"5m"`         // polling interval
+	Mode                 gridMode        `env:"STRATEGIES_GRID_MODE"                    envDefault:"arithmetic"` // levels spacing: arithmetic or geometric
+	LowerPrice           decimal.Decimal `env:"STRATEGIES_GRID_LOWER_PRICE"             envDefault:"0"`          // lowest grid level, derived from the price and GRID_SIZE if 0
+	UpperPrice           decimal.Decimal `env:"STRATEGIES_GRID_UPPER_PRICE"             envDefault:"0"`          // highest grid level, derived from the price and GRID_SIZE if 0
+	LevelsAmount         uint            `env:"STRATEGIES_GRID_LEVELS"                  envDefault:"10"`         // number of price levels between the bounds
+	GridSize             decimal.Decimal `env:"STRATEGIES_GRID_SIZE"                    envDefault:"0.01"`       // share of the price between two levels, used to derive missing bounds
+	BaseCoinForAmount    bool            `env:"STRATEGIES_GRIDS_BASE_COIN_FOR_AMOUNT"   envDefault:"false"`      // whether to use base coin for ORDER_AMOUNT
+	OrderAmount          decimal.Decimal `env:"STRATEGIES_GRIDS_ORDER_AMOUNT"           envDefault:"0.00005"`    // quote coin amount for placing order
+	StopLossShare        decimal.Decimal `env:"STRATEGIES_GRIDS_STOP_LOSS_SHARE"        envDefault:"0.9"`        // stop loss share of actual price
+	StopLossUpdatePeriod time.Duration   `env:"STRATEGIES_GRID_STOP_LOSS_UPDATE_PERIOD" envDefault:"120m"`       // how often to update stop loss
+	StopLossCooldown     time.Duration   `env:"STRATEGIES_GRID_STOP_LOSS_COOLDOWN"      envDefault:"24h"`        // how long to stay flat after stop loss, 0 until restart
 }
 
 func NewConfigFromEnv() (*Config, error) {
@@ -52,7 +55,31 @@ func NewConfigFromEnv() (*Config, error) {
 	cfg.Coins.Quote = quote
 	cfg.Coins.Base = base
 
-	cfg.OrderAmount = utils.RoundPrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
 
 	return cfg, nil
 }
+
+func (cfg *Config) validate() error {
+	if cfg.Mode != gridModeArithmetic && cfg.Mode != gridModeGeometric {
+		return fmt.Errorf("unknown grid mode %q", cfg.Mode)
+	}
+
+	if cfg.LevelsAmount < 2 {
+		return fmt.Errorf("at least 2 levels are required, got %d", cfg.LevelsAmount)
+	}
+
+	if cfg.LowerPrice.IsNegative() || cfg.UpperPrice.IsNegative() {
+		return fmt.Errorf("grid bounds must not be negative")
+	}
+
+	if !cfg.LowerPrice.IsZero() && !cfg.UpperPrice.IsZero() && cfg.LowerPrice.GreaterThanOrEqual(cfg.UpperPrice) {
+		return fmt.Errorf("lower price %s must be below upper price %s", cfg.LowerPrice, cfg.UpperPrice)
+	}
+
+	return nil
+}
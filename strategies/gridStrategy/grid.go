@@ -0,0 +1,132 @@
+package gridStrategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+type gridMode string
+
+const (
+	gridModeArithmetic gridMode = "arithmetic" // same price distance between levels
+	gridModeGeometric  gridMode = "geometric"  // same price ratio between levels
+)
+
+// level is a single price of the grid holding at most one order
+type level struct {
+	price decimal.Decimal
+	order *models.Order
+}
+
+// bounds returns the configured grid range, filling the missing bounds
+// with LevelsAmount/2 grid sizes away from the price. A single configured
+// bound far enough on the wrong side of the price leaves no range.
+func (s *GridStrategy) bounds(price decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	half := s.cfg.GridSize.Mul(decimal.NewFromInt(int64(s.cfg.LevelsAmount))).Div(decimal.NewFromInt(2))
+
+	lower := s.cfg.LowerPrice
+	if lower.IsZero() {
+		lower = price.Mul(decimal.NewFromInt(1).Sub(half))
+	}
+
+	upper := s.cfg.UpperPrice
+	if upper.IsZero() {
+		upper = price.Mul(decimal.NewFromInt(1).Add(half))
+	}
+
+	lower = s.minPrice(lower)
+
+	if lower.GreaterThanOrEqual(upper) {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("no grid range at price %s: lower %s is not below upper %s", price, lower, upper)
+	}
+
+	return lower, upper, nil
+}
+
+// shiftedBounds moves the current range so that price ends up in its middle,
+// keeping the width for arithmetic grids and the ratio for geometric ones
+func (s *GridStrategy) shiftedBounds(price decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	lower, upper := s.levels[0].price, s.levels[len(s.levels)-1].price
+
+	if s.cfg.Mode == gridModeGeometric {
+		ratio := decimal.NewFromFloat(math.Sqrt(upper.Div(lower).InexactFloat64()))
+		return s.minPrice(price.Div(ratio)), price.Mul(ratio)
+	}
+
+	half := upper.Sub(lower).Div(decimal.NewFromInt(2))
+
+	return s.minPrice(price.Sub(half)), price.Add(half)
+}
+
+// minPrice keeps a bound above zero
+func (s *GridStrategy) minPrice(price decimal.Decimal) decimal.Decimal {
+	return decimal.Max(price, decimal.New(1, -int32(s.cfg.PricePrecision)))
+}
+
+// generateLevels splits [lower, upper] into levelsAmount prices rounded to precision
+func generateLevels(
+	mode gridMode,
+	lower, upper decimal.Decimal,
+	levelsAmount uint,
+	precision uint,
+) []*level {
+	levels := make([]*level, levelsAmount)
+	last := int64(levelsAmount) - 1
+
+	var (
+		step  = upper.Sub(lower).Div(decimal.NewFromInt(last))
+		ratio = decimal.NewFromFloat(math.Pow(upper.Div(lower).InexactFloat64(), 1/float64(last)))
+	)
+
+	for i := int64(0); i <= last; i++ {
+		var price decimal.Decimal
+
+		switch {
+		case i == last:
+			price = upper
+		case mode == gridModeGeometric:
+			price = lower.Mul(ratio.Pow(decimal.NewFromInt(i)))
+		default:
+			price = lower.Add(step.Mul(decimal.NewFromInt(i)))
+		}
+
+		levels[i] = &level{price: utils.RoundPrecision(price, precision)}
+	}
+
+	return levels
+}
+
+// nearestLevel returns the index of the level closest to price
+func nearestLevel(levels []*level, price decimal.Decimal) int {
+	nearest := 0
+
+	for i, l := range levels {
+		if l.price.Sub(price).Abs().LessThan(levels[nearest].price.Sub(price).Abs()) {
+			nearest = i
+		}
+	}
+
+	return nearest
+}
+
+// inRange reports whether price is between the lowest and the highest level
+func inRange(levels []*level, price decimal.Decimal) bool {
+	return price.GreaterThanOrEqual(levels[0].price) && price.LessThanOrEqual(levels[len(levels)-1].price)
+}
+
+// desiredSide returns the side of the order that level i should hold,
+// buys below the empty level and sells above it
+func desiredSide(i, empty int) (models.SideType, bool) {
+	switch {
+	case i < empty:
+		return models.SideTypeBuy, true
+	case i > empty:
+		return models.SideTypeSell, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,113 @@
+package gridStrategy
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGenerateLevels(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  gridMode
+		lower string
+		upper string
+		count uint
+		want  []string
+	}{
+		{
+			name:  "arithmetic",
+			mode:  gridModeArithmetic,
+			lower: "100",
+			upper: "200",
+			count: 5,
+			want:  []string{"100", "125", "150", "175", "200"},
+		},
+		{
+			name:  "geometric",
+			mode:  gridModeGeometric,
+			lower: "100",
+			upper: "400",
+			count: 3,
+			want:  []string{"100", "200", "400"},
+		},
+		{
+			name:  "rounded to precision",
+			mode:  gridModeArithmetic,
+			lower: "1",
+			upper: "2",
+			count: 4,
+			want:  []string{"1", "1.33", "1.67", "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := generateLevels(
+				tt.mode,
+				decimal.RequireFromString(tt.lower),
+				decimal.RequireFromString(tt.upper),
+				tt.count,
+				2,
+			)
+
+			if len(levels) != len(tt.want) {
+				t.Fatalf("generateLevels() returned %d levels, want %d", len(levels), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				if !levels[i].price.Equal(decimal.RequireFromString(want)) {
+					t.Errorf("level %d = %v, want %v", i, levels[i].price, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNearestLevel(t *testing.T) {
+	levels := generateLevels(gridModeArithmetic, decimal.NewFromInt(10), decimal.NewFromInt(50), 5, 0)
+
+	if got := nearestLevel(levels, decimal.RequireFromString("31.5")); got != 2 {
+		t.Errorf("nearestLevel() = %d, want 2", got)
+	}
+}
+
+func TestGridStrategy_bounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		lower     string
+		upper     string
+		price     string
+		wantLower string
+		wantUpper string
+		wantErr   bool
+	}{
+		{name: "both derived", lower: "0", upper: "0", price: "100", wantLower: "97.5", wantUpper: "102.5"},
+		{name: "only upper", lower: "0", upper: "110", price: "100", wantLower: "97.5", wantUpper: "110"},
+		{name: "only lower", lower: "90", upper: "0", price: "100", wantLower: "90", wantUpper: "102.5"},
+		{name: "upper below the derived lower", lower: "0", upper: "90", price: "100", wantErr: true},
+		{name: "lower above the derived upper", lower: "110", upper: "0", price: "100", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStrategy(newTestClient(tt.price))
+			s.cfg.LowerPrice = decimal.RequireFromString(tt.lower)
+			s.cfg.UpperPrice = decimal.RequireFromString(tt.upper)
+			s.cfg.GridSize = decimal.RequireFromString("0.01")
+
+			lower, upper, err := s.bounds(decimal.RequireFromString(tt.price))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bounds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if !lower.Equal(decimal.RequireFromString(tt.wantLower)) || !upper.Equal(decimal.RequireFromString(tt.wantUpper)) {
+				t.Errorf("bounds() = %s, %s, want %s, %s", lower, upper, tt.wantLower, tt.wantUpper)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package gridStrategy
 
 import (
+	"sync"
+	"time"
+
 	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
 	"go.uber.org/zap"
 )
 
@@ -11,6 +15,14 @@ type GridStrategy struct {
 	client clients.HttpClient
 	test   bool
 	z      *zap.SugaredLogger
+
+	mu     sync.Mutex
+	levels []*level
+	empty  int // index of the level without an order
+
+	orders    map[int64]struct{} // ids of the open orders placed by the grid, the only ones it cancels
+	stoppedAt time.Time          // when the stop loss closed the grid, zero while trading
+	now       func() time.Time
 }
 
 func NewGridStrategy(c clients.HttpClient, cfg *Config) *GridStrategy {
@@ -21,9 +33,16 @@ func NewGridStrategy(c clients.HttpClient, cfg *Config) *GridStrategy {
 		cfg:    cfg,
 		client: c,
 		z:      z,
+		orders: make(map[int64]struct{}),
+		now:    time.Now,
 	}
 }
 
+// Register adds the strategy to the registry as "grid"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "grid", NewConfigFromEnv, NewGridStrategy)
+}
+
 func (s *GridStrategy) Name() string {
 	return s.name + ": " + s.cfg.Symbol
 }
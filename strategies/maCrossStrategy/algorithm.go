@@ -0,0 +1,122 @@
+package maCrossStrategy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+func (s *MACrossStrategy) Start(ctx context.Context) error {
+	go s.position.StopLoss(ctx)
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	stopLossTicker := time.NewTicker(s.cfg.StopLossUpdatePeriod)
+	defer stopLossTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-stopLossTicker.C:
+			go s.position.StopLoss(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+type signal string
+
+const (
+	signalBuy       signal = "buy"
+	signalSell      signal = "sell"
+	signalDoNothing signal = "do-nothing"
+)
+
+func (s *MACrossStrategy) logic(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	klines, err := s.client.GetKlinesCloses(
+		ctx,
+		s.cfg.Symbol,
+		s.cfg.KlinesInterval,
+	)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get klines",
+			"interval", s.cfg.KlinesInterval,
+			"error", err.Error(),
+		)
+	}
+
+	if l := len(klines); l < s.cfg.SlowPeriod {
+		s.z.Warnw(
+			"not enough klines received",
+			"count", l,
+		)
+
+		return
+	}
+
+	signal, trend, fast, slow := s.getSignal(klines)
+	price := utils.RoundPrecision(decimal.NewFromFloat(klines[len(klines)-1]), s.cfg.PricePrecision)
+
+	s.z.Infow(
+		"new signal",
+		"signal", signal,
+		"price", price,
+		"trend", trend,
+		"fast", fast,
+		"slow", slow,
+	)
+
+	// a rejected or failed crossover is given again on the next tick while
+	// the trend holds
+	if s.act(ctx, signal, price) {
+		s.trend = trend
+	}
+}
+
+// getSignal buys when the fast average crosses above the slow one and sells
+// when it crosses below. Only a change of the trend is a signal, the first
+// trend seen after the start is remembered without trading.
+func (s *MACrossStrategy) getSignal(klines []float64) (signal, trend, float64, float64) {
+	fast := s.cfg.FastType.last(s.cfg.FastPeriod, klines)
+	slow := s.cfg.SlowType.last(s.cfg.SlowPeriod, klines)
+
+	current := trendOf(fast, slow, s.cfg.MinSeparation, s.trend)
+
+	switch {
+	case s.trend == trendUnknown || s.trend == current:
+		return signalDoNothing, current, fast, slow
+	case current == trendUp:
+		return signalBuy, current, fast, slow
+	default:
+		return signalSell, current, fast, slow
+	}
+}
+
+// act trades on the signal and tells whether the signal is done with
+func (s *MACrossStrategy) act(ctx context.Context, sig signal, price decimal.Decimal) bool {
+	amount := s.position.OrderQuantity(price)
+
+	switch sig {
+	case signalBuy:
+		return s.confirmer.Allows(ctx, timeframes.Up) && s.position.Buy(ctx, price, amount) == nil
+	case signalSell:
+		return s.confirmer.Allows(ctx, timeframes.Down) && s.position.Sell(ctx, price, amount) == nil
+	default:
+		return true
+	}
+}
+
+func (s *MACrossStrategy) Stop(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,177 @@
+package maCrossStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+)
+
+func newTestConfig() *Config {
+	cfg := strategytest.NewConfig[Config]()
+	cfg.FastPeriod, cfg.FastType = 3, maTypeEMA
+	cfg.SlowPeriod, cfg.SlowType = 8, maTypeSMA
+	cfg.MinSeparation = 0.001
+
+	return cfg
+}
+
+var (
+	rising  = strategytest.Rising(20)
+	falling = strategytest.Falling(20)
+	flat    = strategytest.Flat(20)
+)
+
+func TestConfig_validate(t *testing.T) {
+	strategytest.RunValidate(t, newTestConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "unknown type", Modify: func(cfg *Config) { cfg.SlowType = "wma" }, WantErr: true},
+		{Name: "fast not faster", Modify: func(cfg *Config) { cfg.FastPeriod = 8 }, WantErr: true},
+		{Name: "zero fast period", Modify: func(cfg *Config) { cfg.FastPeriod = 0 }, WantErr: true},
+		{Name: "negative separation", Modify: func(cfg *Config) { cfg.MinSeparation = -1 }, WantErr: true},
+	})
+}
+
+func TestMACrossStrategy_getSignal(t *testing.T) {
+	s := NewMACrossStrategy(mocks.NewHttpClient(), newTestConfig())
+
+	ticks := []struct {
+		name   string
+		closes []float64
+		want   signal
+	}{
+		{name: "first trend is only remembered", closes: rising, want: signalDoNothing},
+		{name: "trend continues", closes: rising, want: signalDoNothing},
+		{name: "cross below", closes: falling, want: signalSell},
+		{name: "still below", closes: falling, want: signalDoNothing},
+		{name: "averages meet", closes: flat, want: signalDoNothing},
+		{name: "cross above", closes: rising, want: signalBuy},
+	}
+
+	for _, tick := range ticks {
+		got, trend, fast, slow := s.getSignal(tick.closes)
+		if got != tick.want {
+			t.Fatalf("%s: getSignal() = %v (fast %v, slow %v), want %v", tick.name, got, fast, slow, tick.want)
+		}
+
+		s.trend = trend
+	}
+}
+
+func TestMACrossStrategy_getSignalMinSeparation(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.MinSeparation = 0.05
+
+	s := NewMACrossStrategy(mocks.NewHttpClient(), cfg)
+	s.trend = trendDown
+
+	// the fast average is about 2.2% above the slow one
+	if got, trend, _, _ := s.getSignal(rising); got != signalDoNothing || trend != trendDown {
+		t.Errorf("getSignal() = %v with trend %v, want no signal and the trend kept", got, trend)
+	}
+
+	cfg.MinSeparation = 0.01
+
+	if got, _, _, _ := s.getSignal(rising); got != signalBuy {
+		t.Errorf("getSignal() = %v, want buy", got)
+	}
+}
+
+func TestMACrossStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name          string
+		ticks         [][]float64
+		position      string
+		holdings      string
+		wantBuy       int
+		wantSell      int
+		buyFailures   int
+		klinesFailure error
+	}{
+		{name: "no crossover", ticks: [][]float64{rising, rising, rising}},
+		{name: "buy once on the crossover", ticks: [][]float64{falling, rising, rising}, wantBuy: 1},
+		{name: "sell once on the crossover", ticks: [][]float64{rising, falling, falling}, position: "1", wantSell: 1},
+		{name: "coins not bought aren't sold", ticks: [][]float64{rising, falling}, holdings: "1"},
+		{name: "nothing to sell", ticks: [][]float64{rising, falling}},
+		{name: "failed buy is retried", ticks: [][]float64{falling, rising, rising}, buyFailures: 1, wantBuy: 2},
+		{name: "whipsaw is ignored", ticks: [][]float64{rising, flat, rising}},
+		{name: "not enough klines", ticks: [][]float64{falling[:5], rising[:5]}},
+		{name: "klines failure", ticks: [][]float64{falling, rising}, klinesFailure: errors.New("timeout")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			s := NewMACrossStrategy(c, newTestConfig())
+
+			if tt.buyFailures != 0 {
+				c.Fail("NewMarketBuyOrder", errors.New("timeout"), tt.buyFailures)
+			}
+
+			if tt.position != "" {
+				strategytest.Hold(t, s.position, c, tt.position)
+			}
+
+			if tt.holdings != "" {
+				c.SetBalance(strategytest.Coin, tt.holdings, "0")
+			}
+
+			for i, closes := range tt.ticks {
+				c.SetCloses(strategytest.Symbol, "15m", closes)
+
+				if i == len(tt.ticks)-1 && tt.klinesFailure != nil {
+					c.Fail("GetKlinesCloses", tt.klinesFailure, 1)
+				}
+
+				s.logic(context.Background())
+			}
+
+			if got := len(c.CallsTo("NewMarketBuyOrder")); got != tt.wantBuy {
+				t.Errorf("placed %d buys, want %d", got, tt.wantBuy)
+			}
+
+			if got := len(c.CallsTo("NewMarketSellOrder")); got != tt.wantSell {
+				t.Errorf("placed %d sells, want %d", got, tt.wantSell)
+			}
+		})
+	}
+}
+
+func TestMACrossStrategy_TrendConfirmation(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		trend   []float64
+		turned  []float64 // higher timeframe at the third tick
+		wantBuy int
+	}{
+		{name: "higher timeframe agrees", trend: rising, wantBuy: 1},
+		{name: "higher timeframe disagrees", trend: falling},
+		{name: "higher timeframe turns", trend: falling, turned: rising, wantBuy: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(strategytest.Symbol, "4h", tt.trend)
+
+			cfg := newTestConfig()
+			cfg.TrendIntervals, cfg.TrendPeriod = []string{"4h"}, 10
+
+			s := NewMACrossStrategy(c, cfg)
+
+			for _, closes := range [][]float64{falling, rising} {
+				c.SetCloses(strategytest.Symbol, "15m", closes)
+				s.logic(context.Background())
+			}
+
+			// a crossover the higher timeframe rejected waits for it to agree
+			if tt.turned != nil {
+				c.SetCloses(strategytest.Symbol, "4h", tt.turned)
+				s.logic(context.Background())
+			}
+
+			if got := len(c.CallsTo("NewMarketBuyOrder")); got != tt.wantBuy {
+				t.Errorf("placed %d buys, want %d", got, tt.wantBuy)
+			}
+		})
+	}
+}
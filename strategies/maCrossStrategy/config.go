@@ -0,0 +1,90 @@
+package maCrossStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_MA_CROSS_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
+	PricePrecision    uint   `env:"STRATEGIES_MA_CROSS_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_MA_CROSS_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Interval             time.Duration   `env:"STRATEGIES_MA_CROSS_INTERVAL"                envDefault:"5m"`      // polling interval
+	StopLossUpdatePeriod time.Duration   `env:"STRATEGIES_MA_CROSS_STOP_LOSS_UPDATE_PERIOD" envDefault:"180m"`    // how often to update stop loss
+	StopLossShare        decimal.Decimal `env:"STRATEGIES_MA_CROSS_STOP_LOSS_SHARE"         envDefault:"0.85"`    // stop loss share of actual price
+	BaseCoinForAmount    bool            `env:"STRATEGIES_MA_CROSS_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
+	OrderAmount          decimal.Decimal `env:"STRATEGIES_MA_CROSS_ORDER_AMOUNT"            envDefault:"0.00005"` // quote coin amount for placing order
+	MaxOrdersAmount      decimal.Decimal `env:"STRATEGIES_MA_CROSS_MAX_ORDERS_AMOUNT"       envDefault:"100"`     // amount available for trading
+	KlinesInterval       string          `env:"STRATEGIES_MA_CROSS_KLINES_INTERVAL"         envDefault:"15m"`     // klines interval
+	FastPeriod           int             `env:"STRATEGIES_MA_CROSS_FAST_PERIOD"             envDefault:"9"`       // klines of the fast moving average
+	FastType             maType          `env:"STRATEGIES_MA_CROSS_FAST_TYPE"               envDefault:"ema"`     // fast moving average type: ema or sma
+	SlowPeriod           int             `env:"STRATEGIES_MA_CROSS_SLOW_PERIOD"             envDefault:"21"`      // klines of the slow moving average
+	SlowType             maType          `env:"STRATEGIES_MA_CROSS_SLOW_TYPE"               envDefault:"ema"`     // slow moving average type: ema or sma
+	MinSeparation        float64         `env:"STRATEGIES_MA_CROSS_MIN_SEPARATION"          envDefault:"0.001"`   // share of the slow average the fast one must cross it by
+	TrendIntervals       []string        `env:"STRATEGIES_MA_CROSS_TREND_INTERVALS"         envSeparator:","`     // higher timeframes whose trend must agree with signals, e.g. 4h,1d
+	TrendPeriod          int             `env:"STRATEGIES_MA_CROSS_TREND_PERIOD"            envDefault:"50"`      // EMA period of the higher timeframes trend
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	for _, t := range []maType{cfg.FastType, cfg.SlowType} {
+		if t != maTypeEMA && t != maTypeSMA {
+			return fmt.Errorf("unknown moving average type %q", t)
+		}
+	}
+
+	if cfg.FastPeriod < 1 || cfg.FastPeriod >= cfg.SlowPeriod {
+		return fmt.Errorf("periods must satisfy 0 < fast < slow, got %d and %d", cfg.FastPeriod, cfg.SlowPeriod)
+	}
+
+	if cfg.MinSeparation < 0 {
+		return fmt.Errorf("min separation must not be negative, got %v", cfg.MinSeparation)
+	}
+
+	if len(cfg.TrendIntervals) > 0 && cfg.TrendPeriod < 1 {
+		return fmt.Errorf("trend period must be positive, got %d", cfg.TrendPeriod)
+	}
+
+	return nil
+}
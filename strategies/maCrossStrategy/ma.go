@@ -0,0 +1,49 @@
+package maCrossStrategy
+
+import "github.com/cinar/indicator"
+
+type maType string
+
+const (
+	maTypeEMA maType = "ema"
+	maTypeSMA maType = "sma"
+)
+
+// last returns the moving average of the last kline
+func (t maType) last(period int, closes []float64) float64 {
+	var values []float64
+
+	if t == maTypeSMA {
+		values = indicator.Sma(period, closes)
+	} else {
+		values = indicator.Ema(period, closes)
+	}
+
+	return values[len(values)-1]
+}
+
+type trend string
+
+const (
+	trendUnknown trend = "unknown"
+	trendUp      trend = "up"
+	trendDown    trend = "down"
+)
+
+// trendOf tells whether the fast average is above or below the slow one by
+// more than minSeparation of the slow average, otherwise the averages are
+// too close to call and the previous trend holds
+func trendOf(fast, slow, minSeparation float64, previous trend) trend {
+	if slow == 0 {
+		return previous
+	}
+
+	switch separation := (fast - slow) / slow; {
+	case separation > minSeparation:
+		return trendUp
+	case separation < -minSeparation:
+		return trendDown
+	default:
+		return previous
+	}
+}
@@ -0,0 +1,45 @@
+package maCrossStrategy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaType_last(t *testing.T) {
+	closes := []float64{1, 2, 3, 4, 5, 6}
+
+	if got := maTypeSMA.last(3, closes); got != 5 {
+		t.Errorf("SMA = %v, want 5", got)
+	}
+
+	// the EMA weights the recent closes more than the SMA of the same period
+	if got := maTypeEMA.last(3, closes); got <= 5 || got >= 6 {
+		t.Errorf("EMA = %v, want between 5 and 6", got)
+	}
+}
+
+func TestTrendOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		fast     float64
+		slow     float64
+		previous trend
+		want     trend
+	}{
+		{name: "above", fast: 101, slow: 100, previous: trendDown, want: trendUp},
+		{name: "below", fast: 99, slow: 100, previous: trendUp, want: trendDown},
+		{name: "too close above", fast: 100.05, slow: 100, previous: trendDown, want: trendDown},
+		{name: "too close below", fast: 99.95, slow: 100, previous: trendUp, want: trendUp},
+		{name: "too close at the start", fast: 100, slow: 100, previous: trendUnknown, want: trendUnknown},
+		{name: "undefined slow average", fast: 1, slow: math.NaN(), previous: trendUp, want: trendUp},
+		{name: "zero slow average", fast: 1, slow: 0, previous: trendDown, want: trendDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trendOf(tt.fast, tt.slow, 0.001, tt.previous); got != tt.want {
+				t.Errorf("trendOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package maCrossStrategy
+
+import (
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/position"
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
+	"go.uber.org/zap"
+)
+
+type MACrossStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	test   bool
+	z      *zap.SugaredLogger
+
+	confirmer *timeframes.Confirmer
+	position  *position.Position
+
+	mu    sync.Mutex
+	trend trend // relation of the averages at the last tick a signal was done with
+}
+
+func NewMACrossStrategy(c clients.HttpClient, cfg *Config) *MACrossStrategy {
+	z := zap.S().With("context", "MACrossStrategy", "symbol", cfg.Symbol)
+
+	return &MACrossStrategy{
+		name:   "MA crossover strategy",
+		cfg:    cfg,
+		client: c,
+		z:      z,
+
+		confirmer: timeframes.NewConfirmer("ma_cross", c, cfg.Symbol, cfg.TrendIntervals, timeframes.EMATrend(cfg.TrendPeriod)),
+		position: position.New(c, position.Config{
+			Symbol:            cfg.Symbol,
+			Coin:              cfg.Coins.Quote,
+			PricePrecision:    cfg.PricePrecision,
+			QuantityPrecision: cfg.QuantityPrecision,
+			BaseCoinForAmount: cfg.BaseCoinForAmount,
+			OrderAmount:       cfg.OrderAmount,
+			MaxOrdersAmount:   cfg.MaxOrdersAmount,
+			StopLossShare:     cfg.StopLossShare,
+		}, z),
+		trend: trendUnknown,
+	}
+}
+
+// Register adds the strategy to the registry as "ma_cross"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "ma_cross", NewConfigFromEnv, NewMACrossStrategy)
+}
+
+func (s *MACrossStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol
+}
@@ -2,27 +2,30 @@ package macdStrategy
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
 	"github.com/Minish144/crypto-trading-bot/utils"
 	"github.com/cinar/indicator"
-	"go.uber.org/atomic"
+	"github.com/shopspring/decimal"
 )
 
 func (s *MACDStrategy) Start(ctx context.Context) error {
-	stopLoss := atomic.NewFloat64(0)
-
-	go s.stopLoss(ctx, stopLoss)
+	go s.position.StopLoss(ctx)
 	go s.logic(ctx)
 
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	stopLossTicker := time.NewTicker(s.cfg.StopLossUpdatePeriod)
+	defer stopLossTicker.Stop()
+
 	for {
 		select {
-		case <-time.NewTicker(s.cfg.Interval).C:
+		case <-logicTicker.C:
 			go s.logic(ctx)
-		case <-time.NewTicker(s.cfg.StopLossUpdatePeriod).C:
-			go s.stopLoss(ctx, stopLoss)
+		case <-stopLossTicker.C:
+			go s.position.StopLoss(ctx)
 		case <-ctx.Done():
 			return nil
 		}
@@ -38,6 +41,9 @@ const (
 )
 
 func (s *MACDStrategy) logic(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	klines, err := s.client.GetKlinesCloses(
 		ctx,
 		s.cfg.Symbol,
@@ -60,187 +66,67 @@ func (s *MACDStrategy) logic(ctx context.Context) {
 		return
 	}
 
-	signal, macd := s.getSignal(klines)
-	price := utils.RoundPrecision(klines[len(klines)-1], s.cfg.PricePrecision)
-
-	amount := utils.RoundPrecision(s.cfg.OrderAmount, s.cfg.QuantityPrecision)
-	if s.cfg.BaseCoinForAmount {
-		amount = utils.RoundPrecision(utils.QuoteQtyFromBaseQty(price, s.cfg.OrderAmount), s.cfg.QuantityPrecision)
-	}
+	signal, trend, macd, histogram := s.getSignal(klines)
+	price := utils.RoundPrecision(decimal.NewFromFloat(klines[len(klines)-1]), s.cfg.PricePrecision)
 
 	s.z.Infow(
 		"new signal",
 		"signal", signal,
 		"price", price,
 		"MACD", macd,
+		"histogram", histogram,
+		"trend", trend,
+		"in_position", s.position.InPosition(),
 	)
 
-	if signal == signalBuy {
-		allowed, err := s.isBuyAllowedByLimit(ctx, price, amount)
-		if err != nil {
-			s.z.Warnw(
-				"failed to check allowance",
-				"side", "buy",
-				"type", "market",
-				"price", price,
-				"quantity", amount,
-				"error", err.Error(),
-			)
-
-			return
-		}
-
-		if !allowed {
-			s.z.Warnw(
-				"failed to place order",
-				"side", "buy",
-				"type", "market",
-				"price", price,
-				"quantity", amount,
-				"error", "buy limit had been reached",
-			)
-
-			return
-		}
-
-		if err := s.client.NewMarketBuyOrder(ctx, s.cfg.Symbol, amount); err != nil {
-			s.z.Warnw(
-				"failed to place order",
-				"side", "buy",
-				"type", "market",
-				"price", price,
-				"quantity", amount,
-				"error", err.Error(),
-			)
-
-			return
-		}
-	} else if signal == signalSell {
-		if err := s.client.NewMarketSellOrder(ctx, s.cfg.Symbol, amount); err != nil {
-			s.z.Warnw(
-				"failed to place order",
-				"side", "sell",
-				"type", "market",
-				"price", price,
-				"quantity", amount,
-				"error", err.Error(),
-			)
-
-			return
-		}
+	// a rejected or failed crossover is given again on the next tick while
+	// the trend holds
+	if s.act(ctx, signal, price) {
+		s.trend = trend
 	}
 }
 
-func (s *MACDStrategy) getSignal(klines []float64) (signal, float64) {
+// getSignal buys when the MACD line crosses above the signal line and sells
+// when it crosses below. A crossover counts once the histogram keeps its new
+// sign for ConfirmationBars klines, the first trend seen after the start is
+// only remembered. With ZeroLineFilter buys also need MACD above zero.
+func (s *MACDStrategy) getSignal(klines []float64) (signal, trend, float64, float64) {
 	macdValues, signalValues := indicator.Macd(klines)
 
-	latestIndex := len(macdValues) - 1
-
-	macd := macdValues[latestIndex]
-	signalLatest := signalValues[latestIndex]
-
-	if macd > signalLatest {
-		return signalBuy, macd
-	} else if macd < signalLatest {
-		return signalSell, macd
-	} else {
-		return signalDoNothing, macd
-	}
-}
-
-func (s *MACDStrategy) isBuyAllowedByLimit(ctx context.Context, price, qty float64) (bool, error) {
-	available := s.cfg.MaxOrdersAmount
-
-	orders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
-	if err != nil {
-		return false, fmt.Errorf("client.GetOpenOrders: %w", err)
-	}
-
-	for _, order := range orders {
-		if order.Side == models.SideTypeBuy {
-			available -= order.Price * order.OrigQuantity
-		}
+	histogram := make([]float64, len(macdValues))
+	for i := range macdValues {
+		histogram[i] = macdValues[i] - signalValues[i]
 	}
 
-	available -= price * qty
-
-	balance, locked, err := s.client.GetBalance(ctx, s.cfg.Coins.Quote)
-	if err != nil {
-		return false, fmt.Errorf("client.GetBalance: %w", err)
-	}
-
-	total := price * (balance + locked)
-
-	return available >= 0 && total <= s.cfg.MaxOrdersAmount, nil
-}
-
-// @TODO implement using stop-loss binance orders instead of market sell order
-func (s *MACDStrategy) stopLoss(ctx context.Context, stopLoss *atomic.Float64) {
-	currentPrice, err := s.client.GetPrice(ctx, s.cfg.Symbol)
-	if err != nil {
-		s.z.Warnw(
-			"failed to get price for stop loss",
-			"error", err.Error(),
-		)
-		return
-	}
-
-	if stopLoss.Load() != 0 && stopLoss.Load() >= currentPrice {
-		s.z.Infow(
-			"stop loss triggered",
-			"current_price", currentPrice,
-			"stop_loss", stopLoss,
-		)
-
-		orders, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
-		if err != nil {
-			s.z.Warnw(
-				"failed to get open orders for stop loss",
-				"error", err.Error(),
-			)
-
-			return
-		}
+	latestIndex := len(macdValues) - 1
+	macd := macdValues[latestIndex]
 
-		s.closeOrders(ctx, orders)
+	current := histogramTrend(histogram, s.cfg.ConfirmationBars, s.trend)
 
-		go func() {
-			balance, _, err := s.client.GetBalance(ctx, s.cfg.Coins.Quote)
-			if err != nil {
-				s.z.Warnw(
-					"failed to get balance for stop loss",
-					"coin", s.cfg.Coins.Quote,
-					"error", err.Error(),
-				)
-			}
+	switch {
+	case s.trend == trendUnknown || s.trend == current:
+		return signalDoNothing, current, macd, histogram[latestIndex]
+	case current == trendUp && s.cfg.ZeroLineFilter && macd <= 0:
+		s.z.Infow("buy crossover below zero line skipped", "MACD", macd)
 
-			s.client.NewLimitSellOrder(ctx, s.cfg.Symbol, currentPrice, balance)
-		}()
+		return signalDoNothing, current, macd, histogram[latestIndex]
+	case current == trendUp:
+		return signalBuy, current, macd, histogram[latestIndex]
+	default:
+		return signalSell, current, macd, histogram[latestIndex]
 	}
-
-	stopLossActual := currentPrice * s.cfg.StopLossShare
-
-	s.z.Infow(
-		"stop loss updated",
-		"previous", stopLoss.Load(),
-		"current", stopLossActual,
-	)
-
-	stopLoss.Store(stopLossActual)
 }
 
-func (s *MACDStrategy) closeOrders(ctx context.Context, orders []*models.Order) {
-	for _, order := range orders {
-		if err := s.client.CloseOrder(ctx, s.cfg.Symbol, order.OrderID); err != nil {
-			s.z.Warnw(
-				"failed to close order",
-				"side", "buy",
-				"type", "limit",
-				"price", order.Price,
-				"quantity", order.OrigQuantity,
-				"error", err.Error(),
-			)
-		}
+// act trades on the signal and tells whether the signal is done with, a buy
+// in position and a sell without one have nothing to do
+func (s *MACDStrategy) act(ctx context.Context, sig signal, price decimal.Decimal) bool {
+	switch {
+	case sig == signalBuy && !s.position.InPosition():
+		return s.confirmer.Allows(ctx, timeframes.Up) && s.position.Buy(ctx, price, s.position.OrderQuantity(price)) == nil
+	case sig == signalSell && s.position.InPosition():
+		return s.confirmer.Allows(ctx, timeframes.Down) && s.position.Close(ctx, price) == nil
+	default:
+		return true
 	}
 }
 
@@ -0,0 +1,258 @@
+package macdStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+func newTestConfig() *Config {
+	cfg := strategytest.NewConfig[Config]()
+	cfg.ConfirmationBars = 1
+
+	return cfg
+}
+
+var (
+	rising  = strategytest.Series(50, func(i int) float64 { return 100 + float64(i*i)/10 })
+	falling = strategytest.Series(50, func(i int) float64 { return 400 - float64(i*i)/10 })
+	flat    = strategytest.Series(50, func(i int) float64 { return 250 })
+	// a long fall followed by 5 klines of growth, the histogram is positive
+	// for the last 4 of them while MACD is still below zero
+	rebound = strategytest.Series(50, func(i int) float64 {
+		if i < 45 {
+			return 300 - float64(i)*2
+		}
+
+		return 212 + float64(i-44)*3
+	})
+)
+
+func TestMACDStrategy_getSignal(t *testing.T) {
+	s := NewMACDStrategy(mocks.NewHttpClient(), newTestConfig())
+
+	ticks := []struct {
+		name   string
+		closes []float64
+		want   signal
+	}{
+		{name: "first trend is only remembered", closes: rising, want: signalDoNothing},
+		{name: "trend continues", closes: rising, want: signalDoNothing},
+		{name: "cross below", closes: falling, want: signalSell},
+		{name: "still below", closes: falling, want: signalDoNothing},
+		{name: "no histogram", closes: flat, want: signalDoNothing},
+		{name: "cross above", closes: rising, want: signalBuy},
+	}
+
+	for _, tick := range ticks {
+		got, trend, macd, histogram := s.getSignal(tick.closes)
+		if got != tick.want {
+			t.Fatalf("%s: getSignal() = %v (MACD %v, histogram %v), want %v", tick.name, got, macd, histogram, tick.want)
+		}
+
+		// every signal is acted on
+		s.trend = trend
+	}
+}
+
+func TestMACDStrategy_getSignalFilters(t *testing.T) {
+	tests := []struct {
+		name             string
+		confirmationBars int
+		zeroLineFilter   bool
+		want             signal
+	}{
+		{name: "no filters", confirmationBars: 1, want: signalBuy},
+		{name: "confirmed", confirmationBars: 4, want: signalBuy},
+		{name: "not confirmed", confirmationBars: 5, want: signalDoNothing},
+		{name: "below zero line", confirmationBars: 1, zeroLineFilter: true, want: signalDoNothing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMACDStrategy(mocks.NewHttpClient(), newTestConfig())
+			s.cfg.ConfirmationBars = tt.confirmationBars
+			s.cfg.ZeroLineFilter = tt.zeroLineFilter
+			s.trend = trendDown
+
+			if got, _, macd, histogram := s.getSignal(rebound); got != tt.want {
+				t.Errorf("getSignal() = %v (MACD %v, histogram %v), want %v", got, macd, histogram, tt.want)
+			}
+		})
+	}
+}
+
+func TestMACDStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name          string
+		ticks         [][]float64
+		position      string
+		holdings      string
+		baseCoin      bool
+		wantBuys      []string
+		wantSells     []string
+		buyFailures   int
+		sellFailures  int
+		klinesFailure error
+	}{
+		{name: "no crossover", ticks: [][]float64{rising, rising, rising}},
+		{name: "buy once on the crossover", ticks: [][]float64{falling, rising, rising}, wantBuys: []string{"0.1"}},
+		{name: "no buy while in position", ticks: [][]float64{falling, rising}, position: "0.5"},
+		{name: "sell the position", ticks: [][]float64{rising, falling, falling}, position: "1.234", wantSells: []string{"1.234"}},
+		{name: "failed buy is retried", ticks: [][]float64{falling, rising, rising}, buyFailures: 1, wantBuys: []string{"0.1", "0.1"}},
+		{
+			name:         "failed sell is retried",
+			ticks:        [][]float64{rising, falling, falling},
+			position:     "1.234",
+			sellFailures: 1,
+			wantSells:    []string{"1.234", "1.234"},
+		},
+		{name: "sell only the position", ticks: [][]float64{rising, falling}, position: "0.1", holdings: "2", wantSells: []string{"0.1"}},
+		{name: "no sell without position", ticks: [][]float64{rising, falling}},
+		{name: "coins not bought aren't sold", ticks: [][]float64{rising, falling}, holdings: "1.2345"},
+		{name: "amount in base coin", ticks: [][]float64{falling, rising}, baseCoin: true, wantBuys: []string{"0.029"}},
+		{name: "not enough klines", ticks: [][]float64{falling[:20], rising[:20]}},
+		{name: "klines failure", ticks: [][]float64{falling, rising}, klinesFailure: errors.New("timeout")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+
+			cfg := newTestConfig()
+			cfg.BaseCoinForAmount = tt.baseCoin
+
+			if tt.baseCoin {
+				// 10 USDT worth of the coin at the last close of 340.1
+				cfg.OrderAmount = decimal.NewFromInt(10)
+			}
+
+			s := NewMACDStrategy(c, cfg)
+
+			if tt.position != "" {
+				strategytest.Hold(t, s.position, c, tt.position)
+			}
+
+			if tt.holdings != "" {
+				c.SetBalance(strategytest.Coin, tt.holdings, "0")
+			}
+
+			if tt.buyFailures != 0 {
+				c.Fail("NewMarketBuyOrder", errors.New("timeout"), tt.buyFailures)
+			}
+
+			if tt.sellFailures != 0 {
+				c.Fail("NewMarketSellOrder", errors.New("timeout"), tt.sellFailures)
+			}
+
+			for i, closes := range tt.ticks {
+				c.SetCloses(strategytest.Symbol, "15m", closes)
+
+				if i == len(tt.ticks)-1 && tt.klinesFailure != nil {
+					c.Fail("GetKlinesCloses", tt.klinesFailure, 1)
+				}
+
+				s.logic(context.Background())
+			}
+
+			assertMarketOrders(t, c.CallsTo("NewMarketBuyOrder"), tt.wantBuys)
+			assertMarketOrders(t, c.CallsTo("NewMarketSellOrder"), tt.wantSells)
+		})
+	}
+}
+
+func assertMarketOrders(t *testing.T, calls []mocks.Call, want []string) {
+	t.Helper()
+
+	if len(calls) != len(want) {
+		t.Fatalf("placed %d orders, want %d", len(calls), len(want))
+	}
+
+	for i, call := range calls {
+		if got := call.Args[1].(decimal.Decimal); !got.Equal(strategytest.D(want[i])) {
+			t.Errorf("order #%d quantity = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestMACDStrategy_RoundTrip(t *testing.T) {
+	c := mocks.NewHttpClient()
+	s := NewMACDStrategy(c, newTestConfig())
+
+	// the mock doesn't move balances, the bought coins are there from the start
+	c.SetBalance(strategytest.Coin, "0.1", "0")
+
+	for _, closes := range [][]float64{falling, rising, rising, falling, rising} {
+		c.SetCloses(strategytest.Symbol, "15m", closes)
+		s.logic(context.Background())
+	}
+
+	if got := len(c.CallsTo("NewMarketBuyOrder")); got != 2 {
+		t.Errorf("placed %d buys, want 2", got)
+	}
+
+	if got := len(c.CallsTo("NewMarketSellOrder")); got != 1 {
+		t.Errorf("placed %d sells, want 1", got)
+	}
+
+	if !s.position.InPosition() {
+		t.Error("not in position after the last buy")
+	}
+}
+
+func TestMACDStrategy_TrendConfirmation(t *testing.T) {
+	tests := []struct {
+		name      string
+		trend     []float64
+		turned    []float64 // higher timeframe after the ticks
+		position  string
+		ticks     [][]float64
+		wantBuys  int
+		wantSells int
+	}{
+		{name: "buy confirmed", trend: rising, ticks: [][]float64{falling, rising}, wantBuys: 1},
+		{name: "buy rejected", trend: falling, ticks: [][]float64{falling, rising}},
+		{name: "higher timeframe turns", trend: falling, turned: rising, ticks: [][]float64{falling, rising}, wantBuys: 1},
+		{name: "sell confirmed", trend: falling, position: "1", ticks: [][]float64{rising, falling}, wantSells: 1},
+		{name: "sell rejected", trend: rising, position: "1", ticks: [][]float64{rising, falling}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(strategytest.Symbol, "4h", tt.trend)
+
+			cfg := newTestConfig()
+			cfg.TrendIntervals, cfg.TrendPeriod = []string{"4h"}, 20
+
+			s := NewMACDStrategy(c, cfg)
+
+			if tt.position != "" {
+				strategytest.Hold(t, s.position, c, tt.position)
+			}
+
+			for _, closes := range tt.ticks {
+				c.SetCloses(strategytest.Symbol, "15m", closes)
+				s.logic(context.Background())
+			}
+
+			// a crossover the higher timeframe rejected waits for it to agree
+			if tt.turned != nil {
+				c.SetCloses(strategytest.Symbol, "4h", tt.turned)
+				s.logic(context.Background())
+			}
+
+			if got := len(c.CallsTo("NewMarketBuyOrder")); got != tt.wantBuys {
+				t.Errorf("placed %d buys, want %d", got, tt.wantBuys)
+			}
+
+			if got := len(c.CallsTo("NewMarketSellOrder")); got != tt.wantSells {
+				t.Errorf("placed %d sells, want %d", got, tt.wantSells)
+			}
+		})
+	}
+}
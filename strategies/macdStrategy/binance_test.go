@@ -0,0 +1,80 @@
+package macdStrategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/binance"
+	"github.com/Minish144/crypto-trading-bot/clients/binance/binancetest"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+func TestMACDStrategy_Binance(t *testing.T) {
+	growth := func(i int) float64 { return 100 + float64(i*i)/10 }
+	fall := func(i int) float64 { return 300 - float64(i*i)/10 }
+
+	tests := []struct {
+		name   string
+		before func(i int) float64
+		trend  func(i int) float64
+		want   models.SideType
+	}{
+		{name: "crossover to growth buys", before: fall, trend: growth, want: models.SideTypeBuy},
+		{name: "crossover to fall sells", before: growth, trend: fall, want: models.SideTypeSell},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := binancetest.NewServer()
+			defer srv.Close()
+
+			before, closes := strategytest.Series(50, tt.before), strategytest.Series(50, tt.trend)
+
+			srv.AddSymbol(strategytest.Symbol, strategytest.Coin, strategytest.BaseCoin, decimal.NewFromFloat(closes[len(closes)-1]).String())
+			srv.SetBalance(strategytest.BaseCoin, "1000", "0")
+
+			c := binance.NewBinanceClient(&binance.Config{}, false)
+			c.BaseURL = srv.URL
+
+			cfg := newTestConfig()
+			cfg.MaxOrdersAmount = decimal.NewFromInt(1000)
+
+			s := NewMACDStrategy(c, cfg)
+
+			if tt.want == models.SideTypeSell {
+				// the strategy sells only what it bought
+				if err := s.position.Buy(context.Background(), decimal.NewFromInt(1), decimal.NewFromInt(1)); err != nil {
+					t.Fatalf("Buy() error = %v", err)
+				}
+			}
+
+			srv.SetCloses(strategytest.Symbol, "15m", before)
+			s.logic(context.Background())
+
+			srv.SetCloses(strategytest.Symbol, "15m", closes)
+			s.logic(context.Background())
+
+			// the sell follows the buy of the position
+			wantOrders := 1
+			if tt.want == models.SideTypeSell {
+				wantOrders = 2
+			}
+
+			orders := srv.Orders()
+			if len(orders) != wantOrders {
+				t.Fatalf("placed %d orders, want %d", len(orders), wantOrders)
+			}
+
+			last := orders[len(orders)-1]
+			if last.Side != tt.want || last.Type != models.OrderTypeMarket || last.Status != models.OrderStatusTypeFilled {
+				t.Errorf("placed %+v, want a filled market %s", last, tt.want)
+			}
+
+			if tt.want == models.SideTypeSell && !last.ExecutedQuantity.Equal(decimal.NewFromInt(1)) {
+				t.Errorf("sold %v, want the bought 1", last.ExecutedQuantity)
+			}
+		})
+	}
+}
@@ -6,23 +6,28 @@ import (
 
 	"github.com/Minish144/crypto-trading-bot/utils"
 	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
 	Symbol            string `env:"STRATEGIES_MACD_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
-	PricePrecision    uint   `env:"STRATEGIES_MACD_PRICE_PRECISION" envDefault:"3"`       // price float64 precision
-	QuantityPrecision uint   `env:"STRATEGIES_MACD_QTY_PRECISION"   envDefault:"3"`       // quantity float64 precision
+	PricePrecision    uint   `env:"STRATEGIES_MACD_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_MACD_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
 	Coins             struct {
 		Quote string
 		Base  string
 	}
-	Interval             time.Duration `env:"STRATEGIES_MACD_INTERVAL"                envDefault:"5m"`      // polling interval
-	StopLossUpdatePeriod time.Duration `env:"STRATEGIES_MACD_STOP_LOSS_UPDATE_PERIOD" envDefault:"180m"`    // how often to update stop loss
-	StopLossShare        float64       `env:"STRATEGIES_MACD_STOP_LOSS_SHARE"         envDefault:"0.85"`    // stop loss share of actual price
-	BaseCoinForAmount    bool          `env:"STRATEGIES_MACD_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
-	OrderAmount          float64       `env:"STRATEGIES_MACD_ORDER_AMOUNT"            envDefault:"0.00005"` // quote coin amount for placing order
-	MaxOrdersAmount      float64       `env:"STRATEGIES_MACD_MAX_ORDERS_AMOUNT"       envDefault:"100"`     // amount available for trading
-	KlinesInterval       string        `env:"STRATEGIES_MACD_KLINES_INTERVAL"         envDefault:"15m"`     // klines interval
+	Interval             time.Duration   `env:"STRATEGIES_MACD_INTERVAL"                envDefault:"5m"`      // polling interval
+	StopLossUpdatePeriod time.Duration   `env:"STRATEGIES_MACD_STOP_LOSS_UPDATE_PERIOD" envDefault:"180m"`    // how often to update stop loss
+	StopLossShare        decimal.Decimal `env:"STRATEGIES_MACD_STOP_LOSS_SHARE"         envDefault:"0.85"`    // stop loss share of actual price
+	BaseCoinForAmount    bool            `env:"STRATEGIES_MACD_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
+	OrderAmount          decimal.Decimal `env:"STRATEGIES_MACD_ORDER_AMOUNT"            envDefault:"0.00005"` // quote coin amount for placing order
+	MaxOrdersAmount      decimal.Decimal `env:"STRATEGIES_MACD_MAX_ORDERS_AMOUNT"       envDefault:"100"`     // amount available for trading
+	KlinesInterval       string          `env:"STRATEGIES_MACD_KLINES_INTERVAL"         envDefault:"15m"`     // klines interval
+	ConfirmationBars     int             `env:"STRATEGIES_MACD_CONFIRMATION_BARS"       envDefault:"1"`       // klines the histogram must keep its new sign for a crossover to count
+	ZeroLineFilter       bool            `env:"STRATEGIES_MACD_ZERO_LINE_FILTER"        envDefault:"false"`   // whether to buy only while MACD is above zero
+	TrendIntervals       []string        `env:"STRATEGIES_MACD_TREND_INTERVALS"         envSeparator:","`     // higher timeframes whose trend must agree with signals, e.g. 4h,1d
+	TrendPeriod          int             `env:"STRATEGIES_MACD_TREND_PERIOD"            envDefault:"50"`      // EMA period of the higher timeframes trend
 }
 
 func NewConfigFromEnv() (*Config, error) {
@@ -50,7 +55,15 @@ func NewConfigFromEnv() (*Config, error) {
 	cfg.Coins.Quote = quote
 	cfg.Coins.Base = base
 
-	cfg.OrderAmount = utils.RoundPrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if cfg.ConfirmationBars < 1 {
+		return nil, fmt.Errorf("confirmation bars must be at least 1, got %d", cfg.ConfirmationBars)
+	}
+
+	if len(cfg.TrendIntervals) > 0 && cfg.TrendPeriod < 1 {
+		return nil, fmt.Errorf("trend period must be positive, got %d", cfg.TrendPeriod)
+	}
 
 	return cfg, nil
 }
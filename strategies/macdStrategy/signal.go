@@ -0,0 +1,34 @@
+package macdStrategy
+
+type trend string
+
+const (
+	trendUnknown trend = "unknown"
+	trendUp      trend = "up"
+	trendDown    trend = "down"
+)
+
+// histogramTrend is up when the last bars histogram values are all positive
+// and down when they are all negative, otherwise the crossover isn't
+// confirmed yet and the previous trend holds
+func histogramTrend(histogram []float64, bars int, previous trend) trend {
+	if bars < 1 || len(histogram) < bars {
+		return previous
+	}
+
+	up, down := true, true
+
+	for _, h := range histogram[len(histogram)-bars:] {
+		up = up && h > 0
+		down = down && h < 0
+	}
+
+	switch {
+	case up:
+		return trendUp
+	case down:
+		return trendDown
+	default:
+		return previous
+	}
+}
@@ -0,0 +1,28 @@
+package macdStrategy
+
+import "testing"
+
+func TestHistogramTrend(t *testing.T) {
+	tests := []struct {
+		name      string
+		histogram []float64
+		bars      int
+		previous  trend
+		want      trend
+	}{
+		{name: "positive", histogram: []float64{-1, 0.5}, bars: 1, previous: trendDown, want: trendUp},
+		{name: "negative", histogram: []float64{1, -0.5}, bars: 1, previous: trendUp, want: trendDown},
+		{name: "zero", histogram: []float64{1, 0}, bars: 1, previous: trendDown, want: trendDown},
+		{name: "confirmed", histogram: []float64{-1, 0.5, 0.7}, bars: 2, previous: trendDown, want: trendUp},
+		{name: "not confirmed yet", histogram: []float64{-1, -0.5, 0.7}, bars: 2, previous: trendDown, want: trendDown},
+		{name: "not enough values", histogram: []float64{0.5}, bars: 2, previous: trendUnknown, want: trendUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := histogramTrend(tt.histogram, tt.bars, tt.previous); got != tt.want {
+				t.Errorf("histogramTrend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
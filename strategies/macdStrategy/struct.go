@@ -1,7 +1,12 @@
 package macdStrategy
 
 import (
+	"sync"
+
 	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/position"
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
 	"go.uber.org/zap"
 )
 
@@ -11,6 +16,12 @@ type MACDStrategy struct {
 	client clients.HttpClient
 	test   bool
 	z      *zap.SugaredLogger
+
+	confirmer *timeframes.Confirmer
+	position  *position.Position
+
+	mu    sync.Mutex
+	trend trend // histogram sign at the last tick a signal was done with
 }
 
 func NewMACDStrategy(c clients.HttpClient, cfg *Config) *MACDStrategy {
@@ -21,9 +32,27 @@ func NewMACDStrategy(c clients.HttpClient, cfg *Config) *MACDStrategy {
 		cfg:    cfg,
 		client: c,
 		z:      z,
+
+		confirmer: timeframes.NewConfirmer("macd", c, cfg.Symbol, cfg.TrendIntervals, timeframes.EMATrend(cfg.TrendPeriod)),
+		position: position.New(c, position.Config{
+			Symbol:            cfg.Symbol,
+			Coin:              cfg.Coins.Quote,
+			PricePrecision:    cfg.PricePrecision,
+			QuantityPrecision: cfg.QuantityPrecision,
+			BaseCoinForAmount: cfg.BaseCoinForAmount,
+			OrderAmount:       cfg.OrderAmount,
+			MaxOrdersAmount:   cfg.MaxOrdersAmount,
+			StopLossShare:     cfg.StopLossShare,
+		}, z),
+		trend: trendUnknown,
 	}
 }
 
+// Register adds the strategy to the registry as "macd"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "macd", NewConfigFromEnv, NewMACDStrategy)
+}
+
 func (s *MACDStrategy) Name() string {
 	return s.name + ": " + s.cfg.Symbol
 }
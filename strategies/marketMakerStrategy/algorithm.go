@@ -0,0 +1,276 @@
+package marketMakerStrategy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+func (s *MarketMakerStrategy) Start(ctx context.Context) error {
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// logic quotes both sides of the book around the mid price, quotes are only
+// replaced when they drift further than RefreshThreshold from the wanted price
+func (s *MarketMakerStrategy) logic(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticker, err := s.client.GetBookTicker(ctx, s.cfg.Symbol)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get book ticker",
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	if !ticker.BidPrice.IsPositive() || !ticker.AskPrice.IsPositive() {
+		s.z.Warnw(
+			"book is empty",
+			"bid", ticker.BidPrice,
+			"ask", ticker.AskPrice,
+		)
+
+		return
+	}
+
+	quoteFree, quoteLocked, err := s.client.GetBalance(ctx, s.cfg.Coins.Quote)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get balance",
+			"coin", s.cfg.Coins.Quote,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	baseFree, baseLocked, err := s.client.GetBalance(ctx, s.cfg.Coins.Base)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get balance",
+			"coin", s.cfg.Coins.Base,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	share := inventoryShare(quoteFree.Add(quoteLocked), baseFree.Add(baseLocked), midPrice(ticker), s.cfg.TargetShare)
+	q := newQuotes(ticker, s.cfg.Spread, share, s.cfg.TargetShare, s.cfg.Skew, s.cfg.PricePrecision)
+
+	amount := utils.TruncatePrecision(s.cfg.OrderAmount, s.cfg.QuantityPrecision)
+	if s.cfg.BaseCoinForAmount {
+		amount = utils.TruncatePrecision(utils.QuoteQtyFromBaseQty(q.mid, s.cfg.OrderAmount), s.cfg.QuantityPrecision)
+	}
+
+	s.z.Infow(
+		"quotes calculated",
+		"best_bid", ticker.BidPrice,
+		"best_ask", ticker.AskPrice,
+		"bid", q.bid,
+		"ask", q.ask,
+		"inventory_share", share.StringFixed(4),
+	)
+
+	open, err := s.client.GetOpenOrders(ctx, s.cfg.Symbol)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get open orders",
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	s.syncOrders(ctx, open)
+
+	s.quote(ctx, models.SideTypeBuy, q.bid, amount, q.mid, baseFree)
+	s.quote(ctx, models.SideTypeSell, q.ask, amount, q.mid, quoteFree)
+}
+
+// syncOrders forgets the quotes that left the book. Orders the strategy
+// didn't place are left alone, they may belong to other strategies or manual
+// trades on the symbol.
+func (s *MarketMakerStrategy) syncOrders(ctx context.Context, open []*models.Order) {
+	for side, order := range s.orders {
+		if containsOrder(open, order.OrderID) {
+			continue
+		}
+
+		delete(s.orders, side)
+
+		closed, err := s.client.GetOrder(ctx, s.cfg.Symbol, order.OrderID)
+		if err != nil {
+			s.z.Warnw(
+				"failed to get order",
+				"order_id", order.OrderID,
+				"error", err.Error(),
+			)
+
+			continue
+		}
+
+		s.z.Infow(
+			"quote closed",
+			"side", closed.Side,
+			"status", closed.Status,
+			"price", closed.Price,
+			"quantity", closed.ExecutedQuantity,
+		)
+	}
+}
+
+// quote keeps an order of the side at price, free is the balance the order
+// is paid with excluding the funds locked by the current quote
+func (s *MarketMakerStrategy) quote(ctx context.Context, side models.SideType, price, amount, mid, free decimal.Decimal) {
+	if order, ok := s.orders[side]; ok {
+		if !drifted(order, price, mid, s.cfg.RefreshThreshold) {
+			return
+		}
+
+		if err := s.client.CloseOrder(ctx, s.cfg.Symbol, order.OrderID); err != nil {
+			s.z.Warnw(
+				"failed to close order",
+				"side", order.Side,
+				"type", order.Type,
+				"price", order.Price,
+				"quantity", order.OrigQuantity,
+				"error", err.Error(),
+			)
+
+			return
+		}
+
+		delete(s.orders, side)
+		free = free.Add(lockedBy(order))
+	}
+
+	if !amount.IsPositive() {
+		s.z.Warnw(
+			"failed to place order",
+			"side", side,
+			"type", "limit maker",
+			"price", price,
+			"quantity", amount,
+			"error", "quantity is zero",
+		)
+
+		return
+	}
+
+	if required := lockedBy(&models.Order{Side: side, Price: price, OrigQuantity: amount}); free.LessThan(required) {
+		s.z.Infow(
+			"not enough balance to quote",
+			"side", side,
+			"required", required,
+			"free", free,
+		)
+
+		return
+	}
+
+	order, err := s.client.NewOrder(
+		ctx,
+		s.cfg.Symbol,
+		side,
+		models.OrderTypeLimitMaker,
+		models.TimeInForceTypeGTC,
+		price,
+		amount,
+	)
+	if err != nil {
+		s.z.Warnw(
+			"failed to place order",
+			"side", side,
+			"type", "limit maker",
+			"price", price,
+			"quantity", amount,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	s.orders[side] = order
+
+	s.z.Infow(
+		"new order",
+		"side", side,
+		"type", "limit maker",
+		"price", price,
+		"quantity", amount,
+	)
+}
+
+// lockedBy is what the unfilled part of an order keeps locked, the base coin
+// for buys and the quote coin for sells
+func lockedBy(order *models.Order) decimal.Decimal {
+	remaining := order.OrigQuantity.Sub(order.ExecutedQuantity)
+	if order.Side == models.SideTypeBuy {
+		return remaining.Mul(order.Price)
+	}
+
+	return remaining
+}
+
+func containsOrder(orders []*models.Order, id int64) bool {
+	for _, order := range orders {
+		if order.OrderID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Stop pulls the quotes from the book
+func (s *MarketMakerStrategy) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var failed int
+
+	for side, order := range s.orders {
+		if err := s.client.CloseOrder(ctx, s.cfg.Symbol, order.OrderID); err != nil {
+			s.z.Warnw(
+				"failed to close order",
+				"side", order.Side,
+				"type", order.Type,
+				"price", order.Price,
+				"quantity", order.OrigQuantity,
+				"error", err.Error(),
+			)
+
+			failed++
+
+			continue
+		}
+
+		delete(s.orders, side)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to close %d quotes", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package marketMakerStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+const testSymbol = "BNBUSDT"
+
+func newTestStrategy(c *mocks.HttpClient) *MarketMakerStrategy {
+	cfg := &Config{
+		Symbol:            testSymbol,
+		PricePrecision:    2,
+		QuantityPrecision: 3,
+		OrderAmount:       d("0.1"),
+		Spread:            d("0.002"),
+		RefreshThreshold:  d("0.0005"),
+		TargetShare:       d("0.5"),
+		Skew:              d("1"),
+	}
+	cfg.Coins.Quote = "BNB"
+	cfg.Coins.Base = "USDT"
+
+	return NewMarketMakerStrategy(c, cfg)
+}
+
+// newTestClient has a 99.9/100.1 book and a balanced inventory
+func newTestClient() *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, "100")
+	c.SetBookTicker(testSymbol, "99.9", "100.1")
+	c.SetBalance("BNB", "1", "0")
+	c.SetBalance("USDT", "100", "0")
+
+	return c
+}
+
+// assertQuotes checks the limit maker orders placed since the last ResetCalls
+func assertQuotes(t *testing.T, c *mocks.HttpClient, want map[models.SideType]string) {
+	t.Helper()
+
+	calls := c.CallsTo("NewOrder")
+	if len(calls) != len(want) {
+		t.Fatalf("placed %d orders, want %d: %v", len(calls), len(want), calls)
+	}
+
+	for _, call := range calls {
+		side, orderType, price := call.Args[1].(models.SideType), call.Args[2].(models.OrderType), call.Args[4].(decimal.Decimal)
+
+		if orderType != models.OrderTypeLimitMaker {
+			t.Errorf("placed %s order, want LIMIT_MAKER", orderType)
+		}
+
+		if w, ok := want[side]; !ok || !price.Equal(d(w)) {
+			t.Errorf("placed %s at %v, want %v", side, price, want[side])
+		}
+	}
+}
+
+func TestMarketMakerStrategy_logic(t *testing.T) {
+	c := newTestClient()
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+	assertQuotes(t, c, map[models.SideType]string{models.SideTypeBuy: "99.9", models.SideTypeSell: "100.1"})
+
+	// the mid moved 0.02, below the 0.05 threshold
+	c.ResetCalls()
+	c.SetBookTicker(testSymbol, "99.92", "100.12")
+	s.logic(context.Background())
+
+	assertQuotes(t, c, nil)
+
+	if n := len(c.CallsTo("CloseOrder")); n != 0 {
+		t.Errorf("canceled %d quotes within the threshold", n)
+	}
+
+	c.ResetCalls()
+	c.SetBookTicker(testSymbol, "100.9", "101.1")
+	s.logic(context.Background())
+
+	assertQuotes(t, c, map[models.SideType]string{models.SideTypeBuy: "100.89", models.SideTypeSell: "101.11"})
+
+	if n := len(c.CallsTo("CloseOrder")); n != 2 {
+		t.Errorf("canceled %d drifted quotes, want 2", n)
+	}
+
+	if open := c.OpenOrders(testSymbol); len(open) != 2 {
+		t.Errorf("%d open orders, want 2", len(open))
+	}
+}
+
+func TestMarketMakerStrategy_InventorySkew(t *testing.T) {
+	c := newTestClient()
+	c.SetBalance("BNB", "3", "0")
+
+	// 300 of 400 USDT are held in the coin, 0.25 above the target
+	newTestStrategy(c).logic(context.Background())
+
+	assertQuotes(t, c, map[models.SideType]string{models.SideTypeBuy: "99.87", models.SideTypeSell: "100.08"})
+}
+
+func TestMarketMakerStrategy_FilledQuote(t *testing.T) {
+	c := newTestClient()
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	bid := s.orders[models.SideTypeBuy]
+	if err := c.Fill(bid.OrderID); err != nil {
+		t.Fatal(err)
+	}
+
+	c.ResetCalls()
+	s.logic(context.Background())
+
+	if calls := c.CallsTo("GetOrder"); len(calls) != 1 || calls[0].Args[1] != bid.OrderID {
+		t.Errorf("GetOrder calls = %v, want the filled bid", calls)
+	}
+
+	assertQuotes(t, c, map[models.SideType]string{models.SideTypeBuy: "99.9"})
+}
+
+func TestMarketMakerStrategy_ForeignOrdersLeftAlone(t *testing.T) {
+	c := newTestClient()
+	ctx := context.Background()
+
+	// orders of other strategies or manual trades on the symbol
+	maker, _ := c.NewOrder(ctx, testSymbol, models.SideTypeSell, models.OrderTypeLimitMaker, models.TimeInForceTypeGTC, d("105"), d("0.1"))
+	limit, _ := c.NewLimitSellOrder(ctx, testSymbol, d("110"), d("0.1"))
+
+	s := newTestStrategy(c)
+	s.logic(ctx)
+
+	// the book moves far enough to replace both quotes
+	c.SetBookTicker(testSymbol, "101.9", "102.1")
+	c.ResetCalls()
+	s.logic(ctx)
+
+	if calls := c.CallsTo("CloseOrder"); len(calls) != 2 {
+		t.Errorf("CloseOrder calls = %v, want the two drifted quotes", calls)
+	}
+
+	for _, call := range c.CallsTo("CloseOrder") {
+		if id := call.Args[1]; id == maker.OrderID || id == limit.OrderID {
+			t.Errorf("canceled the order %d the strategy didn't place", id)
+		}
+	}
+
+	for _, order := range []*models.Order{maker, limit} {
+		if got, _ := c.GetOrder(ctx, testSymbol, order.OrderID); got.Status != models.OrderStatusTypeNew {
+			t.Errorf("order %d status = %v, want it untouched", order.OrderID, got.Status)
+		}
+	}
+}
+
+func TestMarketMakerStrategy_Balance(t *testing.T) {
+	tests := []struct {
+		name string
+		usdt string
+		bnb  string
+		want map[models.SideType]string
+	}{
+		// 10 of 15 USDT are in the coin, the ask is skewed towards the mid
+		{name: "no base coin for the bid", usdt: "5", bnb: "0.1", want: map[models.SideType]string{models.SideTypeSell: "100.09"}},
+		{name: "no coins for the ask", usdt: "100", bnb: "0", want: map[models.SideType]string{models.SideTypeBuy: "99.95"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient()
+			c.SetBalance("USDT", tt.usdt, "0")
+			c.SetBalance("BNB", tt.bnb, "0")
+
+			newTestStrategy(c).logic(context.Background())
+
+			assertQuotes(t, c, tt.want)
+		})
+	}
+}
+
+func TestMarketMakerStrategy_BookTickerFailure(t *testing.T) {
+	c := newTestClient()
+	c.Fail("GetBookTicker", errors.New("timeout"), 1)
+
+	newTestStrategy(c).logic(context.Background())
+
+	assertQuotes(t, c, nil)
+}
+
+func TestMarketMakerStrategy_Stop(t *testing.T) {
+	c := newTestClient()
+	s := newTestStrategy(c)
+
+	s.logic(context.Background())
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if open := c.OpenOrders(testSymbol); len(open) != 0 {
+		t.Errorf("%d quotes left after Stop", len(open))
+	}
+
+	if len(s.orders) != 0 {
+		t.Errorf("%d quotes still tracked after Stop", len(s.orders))
+	}
+}
@@ -0,0 +1,85 @@
+package marketMakerStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_MARKET_MAKER_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
+	PricePrecision    uint   `env:"STRATEGIES_MARKET_MAKER_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_MARKET_MAKER_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Interval          time.Duration   `env:"STRATEGIES_MARKET_MAKER_INTERVAL"             envDefault:"10s"`     // how often quotes are checked
+	BaseCoinForAmount bool            `env:"STRATEGIES_MARKET_MAKER_BASE_COIN_FOR_AMOUNT" envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
+	OrderAmount       decimal.Decimal `env:"STRATEGIES_MARKET_MAKER_ORDER_AMOUNT"         envDefault:"0.00005"` // quote coin amount of each quote
+	Spread            decimal.Decimal `env:"STRATEGIES_MARKET_MAKER_SPREAD"               envDefault:"0.002"`   // distance between bid and ask as a share of the mid price
+	RefreshThreshold  decimal.Decimal `env:"STRATEGIES_MARKET_MAKER_REFRESH_THRESHOLD"    envDefault:"0.0005"`  // quotes are replaced once they drift this share of the mid price
+	TargetShare       decimal.Decimal `env:"STRATEGIES_MARKET_MAKER_TARGET_SHARE"         envDefault:"0.5"`     // wanted share of the quote coin in the value of both coins
+	Skew              decimal.Decimal `env:"STRATEGIES_MARKET_MAKER_SKEW"                 envDefault:"1"`       // how much the inventory drift from the target shifts quotes, 0 disables
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if !cfg.OrderAmount.IsPositive() {
+		return fmt.Errorf("order amount must be positive, got %s", cfg.OrderAmount)
+	}
+
+	if !cfg.Spread.IsPositive() || cfg.Spread.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("spread must be between 0 and 1, got %s", cfg.Spread)
+	}
+
+	if cfg.RefreshThreshold.IsNegative() {
+		return fmt.Errorf("refresh threshold must not be negative, got %s", cfg.RefreshThreshold)
+	}
+
+	if cfg.TargetShare.IsNegative() || cfg.TargetShare.GreaterThan(decimal.NewFromInt(1)) {
+		return fmt.Errorf("target share must be between 0 and 1, got %s", cfg.TargetShare)
+	}
+
+	if cfg.Skew.IsNegative() {
+		return fmt.Errorf("skew must not be negative, got %s", cfg.Skew)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package marketMakerStrategy
+
+import (
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	one = decimal.NewFromInt(1)
+	two = decimal.NewFromInt(2)
+)
+
+// quotes are the prices the strategy wants its orders at
+type quotes struct {
+	mid decimal.Decimal
+	bid decimal.Decimal
+	ask decimal.Decimal
+}
+
+// inventoryShare is the share of the quote coin in the value of both coins,
+// an empty inventory is considered on target
+func inventoryShare(quoteCoin, baseCoin, mid, target decimal.Decimal) decimal.Decimal {
+	value := quoteCoin.Mul(mid)
+
+	total := value.Add(baseCoin)
+	if !total.IsPositive() {
+		return target
+	}
+
+	return value.Div(total)
+}
+
+func midPrice(ticker *models.BookTicker) decimal.Decimal {
+	return ticker.BidPrice.Add(ticker.AskPrice).Div(two)
+}
+
+// newQuotes places bid and ask spread apart around the mid price of the book.
+// The drift of the inventory share from the target multiplied by skew shifts
+// both quotes, holding too much widens the bid and narrows the ask so sells
+// fill first, holding too little does the opposite. The shift is capped at
+// the half spread, so the bid never gets above the mid price and the ask
+// never below it, which keeps both of them out of the opposite side of the book.
+func newQuotes(ticker *models.BookTicker, spread, share, target, skew decimal.Decimal, precision uint) quotes {
+	mid := midPrice(ticker)
+	half := mid.Mul(spread).Div(two)
+
+	shift := share.Sub(target).Mul(skew)
+	shift = decimal.Max(decimal.Min(shift, one), one.Neg())
+
+	return quotes{
+		mid: mid,
+		bid: mid.Sub(half.Mul(one.Add(shift))).RoundFloor(int32(precision)),
+		ask: mid.Add(half.Mul(one.Sub(shift))).RoundCeil(int32(precision)),
+	}
+}
+
+// drifted reports whether a resting order is further than threshold share of
+// the mid price from the wanted price
+func drifted(order *models.Order, price, mid, threshold decimal.Decimal) bool {
+	return order.Price.Sub(price).Abs().GreaterThan(mid.Mul(threshold))
+}
@@ -0,0 +1,73 @@
+package marketMakerStrategy
+
+import (
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+)
+
+var d = strategytest.D
+
+func Test_newQuotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		bid, ask string
+		share    string
+		skew     string
+		wantBid  string
+		wantAsk  string
+	}{
+		{name: "on target", bid: "99.9", ask: "100.1", share: "0.5", skew: "1", wantBid: "99.9", wantAsk: "100.1"},
+		{name: "too much coin", bid: "99.9", ask: "100.1", share: "0.75", skew: "1", wantBid: "99.87", wantAsk: "100.08"},
+		{name: "too little coin", bid: "99.9", ask: "100.1", share: "0.25", skew: "1", wantBid: "99.92", wantAsk: "100.13"},
+		{name: "skew disabled", bid: "99.9", ask: "100.1", share: "1", skew: "0", wantBid: "99.9", wantAsk: "100.1"},
+		{name: "shift is capped", bid: "99.9", ask: "100.1", share: "1", skew: "10", wantBid: "99.8", wantAsk: "100"},
+		{name: "rounded away from the mid", bid: "100", ask: "100.01", share: "0.5", skew: "1", wantBid: "99.9", wantAsk: "100.11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := &models.BookTicker{BidPrice: d(tt.bid), AskPrice: d(tt.ask)}
+
+			got := newQuotes(ticker, d("0.002"), d(tt.share), d("0.5"), d(tt.skew), 2)
+			if !got.bid.Equal(d(tt.wantBid)) || !got.ask.Equal(d(tt.wantAsk)) {
+				t.Errorf("newQuotes() = %v/%v, want %v/%v", got.bid, got.ask, tt.wantBid, tt.wantAsk)
+			}
+		})
+	}
+}
+
+func Test_inventoryShare(t *testing.T) {
+	tests := []struct {
+		name      string
+		quoteCoin string
+		baseCoin  string
+		want      string
+	}{
+		{name: "balanced", quoteCoin: "1", baseCoin: "100", want: "0.5"},
+		{name: "only coins", quoteCoin: "2", baseCoin: "0", want: "1"},
+		{name: "only base coin", quoteCoin: "0", baseCoin: "50", want: "0"},
+		{name: "empty is on target", quoteCoin: "0", baseCoin: "0", want: "0.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inventoryShare(d(tt.quoteCoin), d(tt.baseCoin), d("100"), d("0.3")); !got.Equal(d(tt.want)) {
+				t.Errorf("inventoryShare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_drifted(t *testing.T) {
+	order := &models.Order{Price: d("99.9")}
+
+	if drifted(order, d("99.95"), d("100"), d("0.0005")) {
+		t.Error("drifted() = true for 0.05 away with a 0.05 threshold")
+	}
+
+	if !drifted(order, d("99.96"), d("100"), d("0.0005")) {
+		t.Error("drifted() = false for 0.06 away with a 0.05 threshold")
+	}
+}
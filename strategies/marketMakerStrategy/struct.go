@@ -0,0 +1,42 @@
+package marketMakerStrategy
+
+import (
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"go.uber.org/zap"
+)
+
+type MarketMakerStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	test   bool
+	z      *zap.SugaredLogger
+
+	mu     sync.Mutex
+	orders map[models.SideType]*models.Order // resting quotes by side
+}
+
+func NewMarketMakerStrategy(c clients.HttpClient, cfg *Config) *MarketMakerStrategy {
+	z := zap.S().With("context", "MarketMakerStrategy", "symbol", cfg.Symbol)
+
+	return &MarketMakerStrategy{
+		name:   "market making strategy",
+		cfg:    cfg,
+		client: c,
+		z:      z,
+		orders: make(map[models.SideType]*models.Order),
+	}
+}
+
+// Register adds the strategy to the registry as "market_maker"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "market_maker", NewConfigFromEnv, NewMarketMakerStrategy)
+}
+
+func (s *MarketMakerStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol
+}
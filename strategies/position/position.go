@@ -0,0 +1,376 @@
+// Package position keeps the coins an indicator strategy bought itself and
+// its stop loss, so the strategy sells and stops out only those and never the
+// holdings of other strategies or manual trades.
+package position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// ErrBuyLimit is returned by Buy when the buy would exceed MaxOrdersAmount
+var ErrBuyLimit = errors.New("buy limit had been reached")
+
+type Config struct {
+	Symbol            string
+	Coin              string // traded coin of the symbol
+	PricePrecision    uint
+	QuantityPrecision uint
+	BaseCoinForAmount bool            // whether OrderAmount is in the base coin
+	OrderAmount       decimal.Decimal // quantity of a buy or its value in the base coin
+	MaxOrdersAmount   decimal.Decimal // base coin value the holdings and open buys of the symbol may reach
+	StopLossShare     decimal.Decimal // stop loss share of actual price
+}
+
+// Position is the quantity a strategy bought and didn't sell yet. It starts
+// flat, the coins held before the start aren't known to belong to it.
+type Position struct {
+	cfg    Config
+	client clients.HttpClient
+	z      *zap.SugaredLogger
+
+	mu       sync.Mutex
+	quantity decimal.Decimal         // bought and not sold yet
+	stops    map[int64]*models.Order // stop loss sells placed and not resolved yet
+	stopLoss decimal.Decimal         // price the position is sold at, zero until the first update
+}
+
+// New creates a flat position logging with the logger of the strategy
+func New(c clients.HttpClient, cfg Config, z *zap.SugaredLogger) *Position {
+	return &Position{
+		cfg:    cfg,
+		client: c,
+		z:      z,
+		stops:  make(map[int64]*models.Order),
+	}
+}
+
+// Quantity returns the quantity bought and not sold yet
+func (p *Position) Quantity() decimal.Decimal {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.quantity
+}
+
+// InPosition reports whether the strategy holds coins it bought
+func (p *Position) InPosition() bool {
+	return p.Quantity().IsPositive()
+}
+
+// OrderQuantity converts ORDER_AMOUNT into the quantity of a buy at the price
+func (p *Position) OrderQuantity(price decimal.Decimal) decimal.Decimal {
+	if p.cfg.BaseCoinForAmount {
+		return utils.TruncatePrecision(utils.QuoteQtyFromBaseQty(price, p.cfg.OrderAmount), p.cfg.QuantityPrecision)
+	}
+
+	return utils.TruncatePrecision(p.cfg.OrderAmount, p.cfg.QuantityPrecision)
+}
+
+// Buy buys the quantity with a market order if the buy limit allows it, the
+// filled quantity is added to the position. Failures are logged.
+func (p *Position) Buy(ctx context.Context, price, quantity decimal.Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	allowed, err := p.isBuyAllowedByLimit(ctx, price, quantity)
+	if err != nil {
+		p.z.Warnw(
+			"failed to check allowance",
+			"side", "buy",
+			"type", "market",
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return fmt.Errorf("p.isBuyAllowedByLimit: %w", err)
+	}
+
+	if !allowed {
+		p.z.Warnw(
+			"failed to place order",
+			"side", "buy",
+			"type", "market",
+			"price", price,
+			"quantity", quantity,
+			"error", ErrBuyLimit.Error(),
+		)
+
+		return ErrBuyLimit
+	}
+
+	order, err := p.client.NewMarketBuyOrder(ctx, p.cfg.Symbol, quantity)
+	if err != nil {
+		p.z.Warnw(
+			"failed to place order",
+			"side", "buy",
+			"type", "market",
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return fmt.Errorf("client.NewMarketBuyOrder: %w", err)
+	}
+
+	p.quantity = p.quantity.Add(order.ExecutedQuantity)
+
+	p.z.Infow(
+		"position increased",
+		"bought", order.ExecutedQuantity,
+		"position", p.quantity,
+	)
+
+	return nil
+}
+
+// Sell sells the quantity with a market order but never more than the
+// position. Failures are logged.
+func (p *Position) Sell(ctx context.Context, price, quantity decimal.Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sell(ctx, price, quantity)
+}
+
+// Close sells the whole position with a market order. Failures are logged.
+func (p *Position) Close(ctx context.Context, price decimal.Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sell(ctx, price, p.quantity)
+}
+
+func (p *Position) sell(ctx context.Context, price, quantity decimal.Decimal) error {
+	free, err := p.freeBalance(ctx)
+	if err != nil {
+		p.z.Warnw(
+			"failed to get balance",
+			"coin", p.cfg.Coin,
+			"error", err.Error(),
+		)
+
+		return err
+	}
+
+	quantity = decimal.Min(quantity, p.quantity, free)
+	if !quantity.IsPositive() {
+		p.z.Infow(
+			"nothing to sell",
+			"coin", p.cfg.Coin,
+			"position", p.quantity,
+			"balance", free,
+		)
+
+		return nil
+	}
+
+	order, err := p.client.NewMarketSellOrder(ctx, p.cfg.Symbol, quantity)
+	if err != nil {
+		p.z.Warnw(
+			"failed to place order",
+			"side", "sell",
+			"type", "market",
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return fmt.Errorf("client.NewMarketSellOrder: %w", err)
+	}
+
+	p.quantity = p.quantity.Sub(order.ExecutedQuantity)
+
+	p.z.Infow(
+		"position decreased",
+		"sold", order.ExecutedQuantity,
+		"position", p.quantity,
+	)
+
+	return nil
+}
+
+// freeBalance returns the free balance of the coin, a position left without
+// any free coin was sold outside of the strategy and is dropped
+func (p *Position) freeBalance(ctx context.Context) (decimal.Decimal, error) {
+	balance, _, err := p.client.GetBalance(ctx, p.cfg.Coin)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("client.GetBalance: %w", err)
+	}
+
+	free := utils.TruncatePrecision(balance, p.cfg.QuantityPrecision)
+
+	if !free.IsPositive() && p.quantity.IsPositive() {
+		p.z.Warnw(
+			"position is already closed",
+			"coin", p.cfg.Coin,
+			"position", p.quantity,
+			"balance", balance,
+		)
+
+		p.quantity = decimal.Zero
+	}
+
+	return free, nil
+}
+
+// isBuyAllowedByLimit checks that the open buys and the holdings of the symbol
+// stay within MaxOrdersAmount after the buy
+func (p *Position) isBuyAllowedByLimit(ctx context.Context, price, qty decimal.Decimal) (bool, error) {
+	available := p.cfg.MaxOrdersAmount
+
+	orders, err := p.client.GetOpenOrders(ctx, p.cfg.Symbol)
+	if err != nil {
+		return false, fmt.Errorf("client.GetOpenOrders: %w", err)
+	}
+
+	for _, order := range orders {
+		if order.Side == models.SideTypeBuy {
+			available = available.Sub(order.Price.Mul(order.OrigQuantity))
+		}
+	}
+
+	available = available.Sub(price.Mul(qty))
+
+	balance, locked, err := p.client.GetBalance(ctx, p.cfg.Coin)
+	if err != nil {
+		return false, fmt.Errorf("client.GetBalance: %w", err)
+	}
+
+	total := price.Mul(balance.Add(locked))
+
+	return !available.IsNegative() && total.LessThanOrEqual(p.cfg.MaxOrdersAmount), nil
+}
+
+// StopLoss sells the position once the price falls to the stop loss and moves
+// the stop loss to STOP_LOSS_SHARE of the price. The position is flat only
+// after the sell is placed, on a failure the stop loss stays to trigger again.
+//
+// @TODO implement using stop-loss binance orders instead of limit sell order
+func (p *Position) StopLoss(ctx context.Context) {
+	currentPrice, err := p.client.GetPrice(ctx, p.cfg.Symbol)
+	if err != nil {
+		p.z.Warnw(
+			"failed to get price for stop loss",
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous := p.stopLoss
+
+	if !previous.IsZero() && previous.GreaterThanOrEqual(currentPrice) {
+		p.z.Infow(
+			"stop loss triggered",
+			"current_price", currentPrice,
+			"stop_loss", previous,
+		)
+
+		if err := p.sellForStopLoss(ctx, currentPrice); err != nil {
+			p.z.Warnw(
+				"failed to sell for stop loss",
+				"error", err.Error(),
+			)
+
+			return
+		}
+	}
+
+	p.stopLoss = utils.RoundPrecision(currentPrice.Mul(p.cfg.StopLossShare), p.cfg.PricePrecision)
+
+	p.z.Infow(
+		"stop loss updated",
+		"previous", previous,
+		"current", p.stopLoss,
+	)
+}
+
+// sellForStopLoss replaces the stop loss sells still open with a limit sell
+// of the whole position at the price
+func (p *Position) sellForStopLoss(ctx context.Context, price decimal.Decimal) error {
+	p.closeStops(ctx)
+
+	free, err := p.freeBalance(ctx)
+	if err != nil {
+		return err
+	}
+
+	quantity := decimal.Min(p.quantity, free)
+	if !quantity.IsPositive() {
+		return nil
+	}
+
+	order, err := p.client.NewLimitSellOrder(ctx, p.cfg.Symbol, price, quantity)
+	if err != nil {
+		p.z.Warnw(
+			"failed to place order",
+			"side", "sell",
+			"type", "limit",
+			"price", price,
+			"quantity", quantity,
+			"error", err.Error(),
+		)
+
+		return fmt.Errorf("client.NewLimitSellOrder: %w", err)
+	}
+
+	p.stops[order.OrderID] = order
+	p.quantity = decimal.Zero
+
+	return nil
+}
+
+// closeStops cancels the stop loss sells placed before, what they didn't sell
+// is back in the position. A sell that can't be resolved is tried again on
+// the next trigger.
+func (p *Position) closeStops(ctx context.Context) {
+	for id, stop := range p.stops {
+		if err := p.client.CloseOrder(ctx, p.cfg.Symbol, id); err != nil {
+			// it may be filled already, its state tells
+			p.z.Warnw(
+				"failed to close order",
+				"side", stop.Side,
+				"type", stop.Type,
+				"price", stop.Price,
+				"quantity", stop.OrigQuantity,
+				"error", err.Error(),
+			)
+		}
+
+		order, err := p.client.GetOrder(ctx, p.cfg.Symbol, id)
+		if err != nil {
+			p.z.Warnw(
+				"failed to get order",
+				"order_id", id,
+				"error", err.Error(),
+			)
+
+			continue
+		}
+
+		if isOpen(order) {
+			continue
+		}
+
+		delete(p.stops, id)
+
+		p.quantity = p.quantity.Add(order.OrigQuantity.Sub(order.ExecutedQuantity))
+	}
+}
+
+func isOpen(order *models.Order) bool {
+	return order.Status == models.OrderStatusTypeNew || order.Status == models.OrderStatusTypePartiallyFilled
+}
@@ -0,0 +1,329 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const testSymbol = "BNBUSDT"
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func newTestPosition(c *mocks.HttpClient) *Position {
+	return New(c, Config{
+		Symbol:            testSymbol,
+		Coin:              "BNB",
+		PricePrecision:    2,
+		QuantityPrecision: 3,
+		OrderAmount:       d("0.1"),
+		MaxOrdersAmount:   decimal.NewFromInt(100),
+		StopLossShare:     d("0.9"),
+	}, zap.S())
+}
+
+// bought makes the position buy the quantity, the mock doesn't move balances
+// so the coins are put on the balance
+func bought(t *testing.T, p *Position, c *mocks.HttpClient, quantity, balance string) {
+	t.Helper()
+
+	if err := p.Buy(context.Background(), decimal.NewFromInt(1), d(quantity)); err != nil {
+		t.Fatalf("Buy() error = %v", err)
+	}
+
+	c.SetBalance("BNB", balance, "0")
+	c.ResetCalls()
+}
+
+func assertQuantity(t *testing.T, p *Position, want string) {
+	t.Helper()
+
+	if got := p.Quantity(); !got.Equal(d(want)) {
+		t.Errorf("position = %v, want %v", got, want)
+	}
+}
+
+func TestPosition_OrderQuantity(t *testing.T) {
+	p := newTestPosition(mocks.NewHttpClient())
+
+	if got := p.OrderQuantity(decimal.NewFromInt(300)); !got.Equal(d("0.1")) {
+		t.Errorf("OrderQuantity() = %v, want 0.1", got)
+	}
+
+	// 10 USDT worth of the coin at 300
+	p.cfg.BaseCoinForAmount, p.cfg.OrderAmount = true, decimal.NewFromInt(10)
+
+	if got := p.OrderQuantity(decimal.NewFromInt(300)); !got.Equal(d("0.033")) {
+		t.Errorf("OrderQuantity() in base coin = %v, want 0.033", got)
+	}
+}
+
+func TestPosition_Buy(t *testing.T) {
+	tests := []struct {
+		name     string
+		holdings string
+		openBuy  string
+		wantErr  error
+		want     string
+	}{
+		{name: "under the limit", holdings: "0", want: "0.1"},
+		{name: "holdings over the limit", holdings: "1", wantErr: ErrBuyLimit, want: "0"},
+		{name: "open buy orders use the limit", holdings: "0", openBuy: "0.5", wantErr: ErrBuyLimit, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetPrice(testSymbol, "170")
+			c.SetBalance("BNB", tt.holdings, "0")
+
+			if tt.openBuy != "" {
+				_, _ = c.NewLimitBuyOrder(context.Background(), testSymbol, decimal.NewFromInt(170), d(tt.openBuy))
+			}
+
+			p := newTestPosition(c)
+
+			if err := p.Buy(context.Background(), decimal.NewFromInt(170), d("0.1")); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Buy() error = %v, want %v", err, tt.wantErr)
+			}
+
+			assertQuantity(t, p, tt.want)
+		})
+	}
+}
+
+func TestPosition_BuyFailure(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.Fail("NewMarketBuyOrder", errors.New("insufficient balance"), 1)
+
+	p := newTestPosition(c)
+
+	if err := p.Buy(context.Background(), decimal.NewFromInt(170), d("0.1")); err == nil {
+		t.Error("Buy() returned no error")
+	}
+
+	if p.InPosition() {
+		t.Error("in position after a failed buy")
+	}
+}
+
+func TestPosition_Sell(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		balance  string
+		quantity string
+		wantSell string
+		want     string
+	}{
+		{name: "part of the position", position: "1", balance: "1", quantity: "0.1", wantSell: "0.1", want: "0.9"},
+		{name: "no more than the position", position: "0.045", balance: "2", quantity: "0.1", wantSell: "0.045", want: "0"},
+		{name: "no more than the free balance", position: "1", balance: "0.5", quantity: "2", wantSell: "0.5", want: "0.5"},
+		{name: "sold outside of the strategy", position: "1", balance: "0.0001", quantity: "0.1", want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			p := newTestPosition(c)
+			bought(t, p, c, tt.position, tt.balance)
+
+			if err := p.Sell(context.Background(), decimal.NewFromInt(200), d(tt.quantity)); err != nil {
+				t.Fatalf("Sell() error = %v", err)
+			}
+
+			calls := c.CallsTo("NewMarketSellOrder")
+
+			switch {
+			case tt.wantSell == "" && len(calls) != 0:
+				t.Errorf("sells = %v, want none", calls)
+			case tt.wantSell != "" && (len(calls) != 1 || !calls[0].Args[1].(decimal.Decimal).Equal(d(tt.wantSell))):
+				t.Errorf("sells = %v, want %s BNB", calls, tt.wantSell)
+			}
+
+			assertQuantity(t, p, tt.want)
+		})
+	}
+}
+
+func TestPosition_Close(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetBalance("BNB", "5", "0")
+
+	p := newTestPosition(c)
+
+	// coins held before aren't the position
+	if err := p.Close(context.Background(), decimal.NewFromInt(200)); err != nil || len(c.CallsTo("NewMarketSellOrder")) != 0 {
+		t.Fatalf("Close() of a flat position = %v, sells %v, want nothing sold", err, c.CallsTo("NewMarketSellOrder"))
+	}
+
+	bought(t, p, c, "1.234", "5")
+
+	c.Fail("NewMarketSellOrder", errors.New("timeout"), 1)
+
+	if err := p.Close(context.Background(), decimal.NewFromInt(200)); err == nil {
+		t.Fatal("Close() returned no error")
+	}
+
+	assertQuantity(t, p, "1.234")
+
+	if err := p.Close(context.Background(), decimal.NewFromInt(200)); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	calls := c.CallsTo("NewMarketSellOrder")
+	if len(calls) != 2 || !calls[1].Args[1].(decimal.Decimal).Equal(d("1.234")) {
+		t.Errorf("sells = %v, want 1.234 BNB", calls)
+	}
+
+	assertQuantity(t, p, "0")
+}
+
+func TestPosition_StopLoss(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, "300")
+
+	// an order of someone else on the symbol
+	manual, _ := c.NewLimitSellOrder(context.Background(), testSymbol, decimal.NewFromInt(400), decimal.NewFromInt(1))
+
+	p := newTestPosition(c)
+	bought(t, p, c, "1.234", "1.23456")
+
+	p.StopLoss(context.Background())
+
+	if !p.stopLoss.Equal(decimal.NewFromInt(270)) {
+		t.Fatalf("stop loss = %v, want 270", p.stopLoss)
+	}
+
+	if len(c.CallsTo("NewLimitSellOrder")) != 0 {
+		t.Fatal("stop loss sold before being triggered")
+	}
+
+	c.SetPrice(testSymbol, "260")
+	p.StopLoss(context.Background())
+
+	for _, call := range c.CallsTo("CloseOrder") {
+		if call.Args[1] == manual.OrderID {
+			t.Errorf("canceled the order %d the position didn't place", manual.OrderID)
+		}
+	}
+
+	calls := c.CallsTo("NewLimitSellOrder")
+	if len(calls) != 1 || !calls[0].Args[2].(decimal.Decimal).Equal(d("1.234")) {
+		t.Errorf("stop loss sells = %v, want 1.234 BNB", calls)
+	}
+
+	if p.InPosition() {
+		t.Error("still in position after stop loss")
+	}
+
+	if !p.stopLoss.Equal(decimal.NewFromInt(234)) {
+		t.Errorf("stop loss after trigger = %v, want 234", p.stopLoss)
+	}
+}
+
+func TestPosition_StopLossFailure(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, "300")
+
+	p := newTestPosition(c)
+	bought(t, p, c, "1", "1")
+
+	p.StopLoss(context.Background())
+
+	c.SetPrice(testSymbol, "260")
+	c.Fail("NewLimitSellOrder", errors.New("timeout"), 1)
+	p.StopLoss(context.Background())
+
+	if !p.InPosition() {
+		t.Fatal("position dropped although the stop loss sell failed")
+	}
+
+	if !p.stopLoss.Equal(decimal.NewFromInt(270)) {
+		t.Fatalf("stop loss = %v, want 270 kept to trigger again", p.stopLoss)
+	}
+
+	p.StopLoss(context.Background())
+
+	if calls := c.CallsTo("NewLimitSellOrder"); len(calls) != 2 {
+		t.Errorf("stop loss sells = %v, want a retry", calls)
+	}
+
+	if p.InPosition() {
+		t.Error("still in position after the retried stop loss")
+	}
+}
+
+func TestPosition_StopLossWithoutBalance(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, "300")
+
+	p := newTestPosition(c)
+	bought(t, p, c, "1", "1")
+
+	c.Fail("GetBalance", errors.New("timeout"), 0)
+
+	p.StopLoss(context.Background())
+	c.SetPrice(testSymbol, "100")
+	p.StopLoss(context.Background())
+
+	if len(c.CallsTo("NewLimitSellOrder")) != 0 {
+		t.Error("stop loss placed an order without knowing the balance")
+	}
+
+	if !p.InPosition() {
+		t.Error("position dropped without a sell")
+	}
+}
+
+func TestPosition_StopLossReplacesOwnSell(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice(testSymbol, "300")
+
+	p := newTestPosition(c)
+	bought(t, p, c, "1", "1")
+
+	p.StopLoss(context.Background())
+	c.SetPrice(testSymbol, "260")
+	p.StopLoss(context.Background())
+
+	// the limit sell at 260 is still open when the price falls through the next stop loss
+	stop := c.OpenOrders(testSymbol)[0]
+
+	c.ResetCalls()
+	c.SetPrice(testSymbol, "230")
+	p.StopLoss(context.Background())
+
+	closed := c.CallsTo("CloseOrder")
+	if len(closed) != 1 || closed[0].Args[1] != stop.OrderID {
+		t.Errorf("canceled %v, want the previous stop loss sell %d", closed, stop.OrderID)
+	}
+
+	calls := c.CallsTo("NewLimitSellOrder")
+	if len(calls) != 1 || !calls[0].Args[1].(decimal.Decimal).Equal(d("230")) || !calls[0].Args[2].(decimal.Decimal).Equal(d("1")) {
+		t.Errorf("stop loss sells = %v, want 1 BNB at 230", calls)
+	}
+
+	// a filled stop loss sell isn't sold again
+	if err := c.Fill(c.OpenOrders(testSymbol)[0].OrderID); err != nil {
+		t.Fatal(err)
+	}
+
+	c.ResetCalls()
+	c.SetPrice(testSymbol, "200")
+	p.StopLoss(context.Background())
+
+	if calls := c.CallsTo("NewLimitSellOrder"); len(calls) != 0 {
+		t.Errorf("stop loss sells after the fill = %v, want none", calls)
+	}
+
+	if len(p.stops) != 0 {
+		t.Errorf("%d stop loss sells left, want the filled one resolved", len(p.stops))
+	}
+}
@@ -0,0 +1,242 @@
+package rebalanceStrategy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+func (s *RebalanceStrategy) Start(ctx context.Context) error {
+	var driftTicks, scheduled <-chan time.Time
+
+	if s.cfg.Threshold.IsPositive() {
+		driftTicker := time.NewTicker(s.cfg.Interval)
+		defer driftTicker.Stop()
+
+		driftTicks = driftTicker.C
+
+		go s.logic(ctx, false)
+	}
+
+	var scheduleTimer *time.Timer
+
+	if s.cfg.Schedule.Schedule != nil {
+		scheduleTimer = time.NewTimer(time.Until(s.cfg.Schedule.Next(time.Now())))
+		defer scheduleTimer.Stop()
+
+		scheduled = scheduleTimer.C
+	}
+
+	for {
+		select {
+		case <-driftTicks:
+			go s.logic(ctx, false)
+		case <-scheduled:
+			go s.logic(ctx, true)
+
+			scheduleTimer.Reset(time.Until(s.cfg.Schedule.Next(time.Now())))
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// logic values the portfolio and rebalances it when a coin drifted past the
+// threshold, scheduled runs rebalance whatever the drift is
+func (s *RebalanceStrategy) logic(ctx context.Context, scheduled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadSymbols(ctx); err != nil {
+		s.z.Warnw("failed to get symbols", "error", err.Error())
+		return
+	}
+
+	holdings, err := s.holdings(ctx)
+	if err != nil {
+		s.z.Warnw("failed to value portfolio", "error", err.Error())
+		return
+	}
+
+	total := totalValue(holdings)
+	if !total.IsPositive() {
+		s.z.Warnw("portfolio is empty", "base_coin", s.helper.BaseCoin())
+		return
+	}
+
+	maxDrift := drift(holdings, s.cfg.Weights, total)
+
+	if !scheduled && maxDrift.LessThanOrEqual(s.cfg.Threshold) {
+		return
+	}
+
+	s.z.Infow(
+		"rebalance",
+		"total", total,
+		"base_coin", s.helper.BaseCoin(),
+		"drift", maxDrift,
+		"threshold", s.cfg.Threshold,
+		"scheduled", scheduled,
+	)
+
+	s.rebalance(ctx, holdings, total)
+}
+
+// loadSymbols fetches the trading rules of the weighted coins against the
+// base coin once, they are needed to round quantities and skip tiny orders
+func (s *RebalanceStrategy) loadSymbols(ctx context.Context) error {
+	if s.symbols != nil {
+		return nil
+	}
+
+	baseCoin := s.helper.BaseCoin()
+	coins := make(map[string]string)
+
+	for _, coin := range s.cfg.Weights.Coins() {
+		if coin != baseCoin {
+			coins[coin+baseCoin] = coin
+		}
+	}
+
+	symbols := make(map[string]*models.Symbol, len(coins))
+
+	if len(coins) > 0 {
+		names := make([]string, 0, len(coins))
+		for name := range coins {
+			names = append(names, name)
+		}
+
+		list, err := s.client.GetSymbols(ctx, names...)
+		if err != nil {
+			return fmt.Errorf("client.GetSymbols: %w", err)
+		}
+
+		for _, symbol := range list {
+			if coin, ok := coins[symbol.Symbol]; ok {
+				symbols[coin] = symbol
+			}
+		}
+	}
+
+	s.symbols = symbols
+
+	return nil
+}
+
+// holdings returns the weighted coins and the base coin with their balances
+// and prices, coins out of the weights are not part of the portfolio
+func (s *RebalanceStrategy) holdings(ctx context.Context) ([]holding, error) {
+	coins := s.cfg.Weights.Coins()
+	if _, ok := s.cfg.Weights[s.helper.BaseCoin()]; !ok {
+		coins = append(coins, s.helper.BaseCoin())
+	}
+
+	holdings := make([]holding, 0, len(coins))
+
+	for _, coin := range coins {
+		free, locked, err := s.client.GetBalance(ctx, coin)
+		if err != nil {
+			return nil, fmt.Errorf("client.GetBalance %s: %w", coin, err)
+		}
+
+		price, err := s.helper.Price(ctx, coin)
+		if err != nil {
+			return nil, fmt.Errorf("helper.Price %s: %w", coin, err)
+		}
+
+		holdings = append(holdings, holding{coin: coin, price: price, free: free, locked: locked})
+	}
+
+	return holdings, nil
+}
+
+// rebalance places the planned market orders, buys are cut to the free base
+// coin including what the sells brought
+func (s *RebalanceStrategy) rebalance(ctx context.Context, holdings []holding, total decimal.Decimal) {
+	baseCoin := s.helper.BaseCoin()
+
+	steps := make(map[string]decimal.Decimal, len(s.symbols))
+	for coin, symbol := range s.symbols {
+		steps[coin] = symbol.StepSize
+	}
+
+	available := decimal.Zero
+
+	for _, h := range holdings {
+		if h.coin == baseCoin {
+			available = h.free
+		}
+	}
+
+	for _, t := range plan(holdings, s.cfg.Weights, baseCoin, total, steps) {
+		symbol, ok := s.symbols[t.coin]
+		if !ok {
+			s.z.Warnw("order skipped", "coin", t.coin, "side", t.side, "quantity", t.quantity, "reason", "unknown symbol")
+			continue
+		}
+
+		quantity := t.quantity
+		if t.side == models.SideTypeBuy {
+			quantity = decimal.Min(quantity, utils.TruncateStep(available.Div(t.price), symbol.StepSize))
+		}
+
+		if reason := rejection(symbol, quantity, t.price); reason != "" {
+			s.z.Infow("order skipped", "coin", t.coin, "side", t.side, "quantity", quantity, "reason", reason)
+			continue
+		}
+
+		var (
+			order *models.Order
+			err   error
+		)
+
+		if t.side == models.SideTypeBuy {
+			order, err = s.client.NewMarketBuyOrder(ctx, symbol.Symbol, quantity)
+		} else {
+			order, err = s.client.NewMarketSellOrder(ctx, symbol.Symbol, quantity)
+		}
+
+		if err != nil {
+			s.z.Warnw(
+				"failed to place order",
+				"symbol", symbol.Symbol,
+				"side", t.side,
+				"type", "market",
+				"price", t.price,
+				"quantity", quantity,
+				"error", err.Error(),
+			)
+
+			continue
+		}
+
+		cost := order.CummulativeQuoteQuantity
+		if cost.IsZero() {
+			cost = quantity.Mul(t.price)
+		}
+
+		if t.side == models.SideTypeBuy {
+			available = available.Sub(cost)
+		} else {
+			available = available.Add(cost)
+		}
+
+		s.z.Infow(
+			"new order",
+			"symbol", symbol.Symbol,
+			"side", t.side,
+			"type", "market",
+			"price", t.price,
+			"quantity", quantity,
+			"cost", cost,
+		)
+	}
+}
+
+func (s *RebalanceStrategy) Stop(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,176 @@
+package rebalanceStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/helpers"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/schedule"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+	"golang.org/x/exp/slices"
+)
+
+var d = strategytest.D
+
+func newTestConfig() *Config {
+	return &Config{
+		Weights:   Weights{"BTC": d("0.5"), "ETH": d("0.3"), "USDT": d("0.2")},
+		Threshold: d("0.05"),
+		Interval:  1,
+	}
+}
+
+func newTestClient() *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+	c.SetPrice("BTCUSDT", "20000")
+	c.SetPrice("ETHUSDT", "1000")
+	c.SetSymbol(&models.Symbol{
+		Symbol: "BTCUSDT", Status: "TRADING", BaseAsset: "BTC", QuoteAsset: "USDT",
+		StepSize: d("0.0001"), MinQuantity: d("0.0001"), MinNotional: d("10"),
+	})
+	c.SetSymbol(&models.Symbol{
+		Symbol: "ETHUSDT", Status: "TRADING", BaseAsset: "ETH", QuoteAsset: "USDT",
+		StepSize: d("0.001"), MinQuantity: d("0.001"), MinNotional: d("10"),
+	})
+
+	return c
+}
+
+func newTestStrategy(c *mocks.HttpClient, cfg *Config) *RebalanceStrategy {
+	s, err := NewRebalanceStrategy(strategies.Dependencies{Binance: c, BinanceHelper: helpers.NewHelper(c, "USDT")}, cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// placed returns the market orders as "SIDE SYMBOL QUANTITY" in the order they were placed
+func placed(c *mocks.HttpClient) []string {
+	var orders []string
+	for _, order := range c.Orders() {
+		orders = append(orders, string(order.Side)+" "+order.Symbol+" "+order.OrigQuantity.String())
+	}
+
+	return orders
+}
+
+func TestConfig_validate(t *testing.T) {
+	daily, err := schedule.Parse("@daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strategytest.RunValidate(t, newTestConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "schedule only", Modify: func(cfg *Config) { cfg.Threshold, cfg.Schedule = decimal.Zero, daily }},
+		{Name: "no weights", Modify: func(cfg *Config) { cfg.Weights = Weights{} }, WantErr: true},
+		{Name: "zero weight", Modify: func(cfg *Config) { cfg.Weights["ETH"], cfg.Weights["USDT"] = decimal.Zero, d("0.5") }, WantErr: true},
+		{Name: "sum below 1", Modify: func(cfg *Config) { cfg.Weights["USDT"] = d("0.1") }, WantErr: true},
+		{Name: "negative threshold", Modify: func(cfg *Config) { cfg.Threshold = d("-0.1") }, WantErr: true},
+		{Name: "neither threshold nor schedule", Modify: func(cfg *Config) { cfg.Threshold = decimal.Zero }, WantErr: true},
+		{Name: "zero interval", Modify: func(cfg *Config) { cfg.Interval = 0 }, WantErr: true},
+	})
+}
+
+func TestRebalanceStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name      string
+		balances  map[string][2]string // free and locked
+		scheduled bool
+		fail      string
+		want      []string
+	}{
+		{
+			name:     "on target",
+			balances: map[string][2]string{"BTC": {"0.5", "0"}, "ETH": {"6", "0"}, "USDT": {"4000", "0"}},
+		},
+		{
+			name:     "drift within the threshold",
+			balances: map[string][2]string{"BTC": {"0.52", "0"}, "ETH": {"5.8", "0"}, "USDT": {"4000", "0"}},
+		},
+		{
+			name:     "sells fund the buys",
+			balances: map[string][2]string{"BTC": {"1", "0"}},
+			want:     []string{"SELL BTCUSDT 0.5", "BUY ETHUSDT 6"},
+		},
+		{
+			name:     "bigger buys go first",
+			balances: map[string][2]string{"USDT": {"20000", "0"}},
+			want:     []string{"BUY BTCUSDT 0.5", "BUY ETHUSDT 6"},
+		},
+		{
+			name:     "sells are cut to the free balance",
+			balances: map[string][2]string{"BTC": {"0.2", "0.8"}},
+			want:     []string{"SELL BTCUSDT 0.2", "BUY ETHUSDT 4"},
+		},
+		{
+			name:     "buys are cut to the base coin",
+			balances: map[string][2]string{"BTC": {"1", "0"}},
+			fail:     "NewMarketSellOrder",
+		},
+		{
+			name:      "scheduled run ignores the threshold",
+			balances:  map[string][2]string{"BTC": {"0.52", "0"}, "ETH": {"5.8", "0"}, "USDT": {"4000", "0"}},
+			scheduled: true,
+			want:      []string{"SELL BTCUSDT 0.015", "BUY ETHUSDT 0.26"},
+		},
+		{
+			name:      "orders below min notional are skipped",
+			balances:  map[string][2]string{"BTC": {"0.5004", "0"}, "ETH": {"5.995", "0"}, "USDT": {"4000", "0"}},
+			scheduled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient()
+			for coin, b := range tt.balances {
+				c.SetBalance(coin, b[0], b[1])
+			}
+
+			if tt.fail != "" {
+				c.Fail(tt.fail, errors.New("rejected"), 0)
+			}
+
+			s := newTestStrategy(c, newTestConfig())
+			s.logic(context.Background(), tt.scheduled)
+
+			if got := placed(c); !slices.Equal(got, tt.want) {
+				t.Errorf("placed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebalanceStrategy_logicLoadsSymbolsOnce(t *testing.T) {
+	c := newTestClient()
+	c.SetBalance("USDT", "1000", "0")
+
+	s := newTestStrategy(c, newTestConfig())
+	s.logic(context.Background(), false)
+	s.logic(context.Background(), false)
+
+	if calls := c.CallsTo("GetSymbols"); len(calls) != 1 {
+		t.Errorf("GetSymbols called %d times, want 1", len(calls))
+	}
+}
+
+func TestRebalanceStrategy_logicUnknownSymbol(t *testing.T) {
+	c := newTestClient()
+	c.SetBalance("USDT", "1000", "0")
+
+	cfg := newTestConfig()
+	cfg.Weights = Weights{"BTC": d("0.5"), "DOGE": d("0.5")}
+
+	s := newTestStrategy(c, cfg)
+	s.logic(context.Background(), true)
+
+	if len(c.Orders()) != 0 || s.symbols != nil {
+		t.Errorf("placed %v and loaded symbols %v, want nothing", placed(c), s.symbols)
+	}
+}
@@ -0,0 +1,60 @@
+package rebalanceStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/strategies/schedule"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Weights   Weights           `env:"STRATEGIES_REBALANCE_WEIGHTS,required"`                   // target shares of the coins in the portfolio value
+	Threshold decimal.Decimal   `env:"STRATEGIES_REBALANCE_THRESHOLD"        envDefault:"0.05"` // rebalance once a share drifts this far from its target, 0 disables
+	Schedule  schedule.Schedule `env:"STRATEGIES_REBALANCE_SCHEDULE"`                           // cron expression of rebalances regardless of the drift, empty disables
+	Interval  time.Duration     `env:"STRATEGIES_REBALANCE_INTERVAL"         envDefault:"5m"`   // how often the drift is checked
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if len(cfg.Weights) == 0 {
+		return fmt.Errorf("weights are required")
+	}
+
+	for coin, share := range cfg.Weights {
+		if !share.IsPositive() {
+			return fmt.Errorf("weight of %s must be positive, got %s", coin, share)
+		}
+	}
+
+	if sum := cfg.Weights.Sum(); !sum.Equal(decimal.NewFromInt(1)) {
+		return fmt.Errorf("weights must sum to 1, got %s", sum)
+	}
+
+	if cfg.Threshold.IsNegative() || cfg.Threshold.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("threshold must be between 0 and 1, got %s", cfg.Threshold)
+	}
+
+	if cfg.Threshold.IsZero() && cfg.Schedule.Schedule == nil {
+		return fmt.Errorf("either a threshold or a schedule is required")
+	}
+
+	if cfg.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", cfg.Interval)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package rebalanceStrategy
+
+import (
+	"sort"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+// holding is a coin of the portfolio, the price is in the base coin
+type holding struct {
+	coin   string
+	price  decimal.Decimal
+	free   decimal.Decimal
+	locked decimal.Decimal
+}
+
+func (h holding) value() decimal.Decimal {
+	return h.price.Mul(h.free.Add(h.locked))
+}
+
+// trade is a market order moving a coin toward its target against the base coin
+type trade struct {
+	coin     string
+	side     models.SideType
+	price    decimal.Decimal
+	quantity decimal.Decimal
+}
+
+func (t trade) value() decimal.Decimal {
+	return t.price.Mul(t.quantity)
+}
+
+// totalValue sums the values of the holdings
+func totalValue(holdings []holding) decimal.Decimal {
+	total := decimal.Zero
+	for _, h := range holdings {
+		total = total.Add(h.value())
+	}
+
+	return total
+}
+
+// drift returns the largest distance between the share of a coin in the
+// portfolio value and its target weight, unweighted coins target zero
+func drift(holdings []holding, weights Weights, total decimal.Decimal) decimal.Decimal {
+	largest := decimal.Zero
+
+	for _, h := range holdings {
+		d := h.value().Div(total).Sub(weights[h.coin]).Abs()
+		if d.GreaterThan(largest) {
+			largest = d
+		}
+	}
+
+	return largest
+}
+
+// plan returns one trade per coin that is off its target value, the base coin
+// is what the others are traded against so it gets to its target on its own.
+// Sells come first so their proceeds fund the buys, bigger trades go first.
+// Quantities are rounded down to the step and sells never exceed the free balance.
+func plan(holdings []holding, weights Weights, baseCoin string, total decimal.Decimal, steps map[string]decimal.Decimal) []trade {
+	trades := make([]trade, 0, len(holdings))
+
+	for _, h := range holdings {
+		if h.coin == baseCoin || !h.price.IsPositive() {
+			continue
+		}
+
+		diff := total.Mul(weights[h.coin]).Sub(h.value())
+
+		t := trade{
+			coin:     h.coin,
+			side:     models.SideTypeBuy,
+			price:    h.price,
+			quantity: utils.TruncateStep(diff.Abs().Div(h.price), steps[h.coin]),
+		}
+
+		if diff.IsNegative() {
+			t.side = models.SideTypeSell
+			t.quantity = decimal.Min(t.quantity, utils.TruncateStep(h.free, steps[h.coin]))
+		}
+
+		if t.quantity.IsPositive() {
+			trades = append(trades, t)
+		}
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool {
+		if trades[i].side != trades[j].side {
+			return trades[i].side == models.SideTypeSell
+		}
+
+		return trades[i].value().GreaterThan(trades[j].value())
+	})
+
+	return trades
+}
+
+// rejection returns why the exchange would reject the order, empty if it wouldn't
+func rejection(symbol *models.Symbol, quantity, price decimal.Decimal) string {
+	switch {
+	case symbol.Status != "" && symbol.Status != "TRADING":
+		return "symbol is " + symbol.Status
+	case !quantity.IsPositive():
+		return "quantity is zero"
+	case quantity.LessThan(symbol.MinQuantity):
+		return "quantity is below " + symbol.MinQuantity.String()
+	case quantity.Mul(price).LessThan(symbol.MinNotional):
+		return "value is below " + symbol.MinNotional.String()
+	default:
+		return ""
+	}
+}
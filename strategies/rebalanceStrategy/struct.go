@@ -0,0 +1,49 @@
+package rebalanceStrategy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/helpers"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"go.uber.org/zap"
+)
+
+type RebalanceStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	helper *helpers.Helper
+	test   bool
+	z      *zap.SugaredLogger
+
+	mu      sync.Mutex
+	symbols map[string]*models.Symbol // trading rules by coin, loaded on the first run
+}
+
+func NewRebalanceStrategy(deps strategies.Dependencies, cfg *Config) (*RebalanceStrategy, error) {
+	if deps.Binance == nil || deps.BinanceHelper == nil {
+		return nil, fmt.Errorf("binance is not enabled")
+	}
+
+	z := zap.S().With("context", "RebalanceStrategy", "weights", cfg.Weights.String())
+
+	return &RebalanceStrategy{
+		name:   "rebalance strategy",
+		cfg:    cfg,
+		client: deps.Binance,
+		helper: deps.BinanceHelper,
+		z:      z,
+	}, nil
+}
+
+// Register adds the strategy to the registry as "rebalance"
+func Register(r *strategies.Registry) {
+	strategies.RegisterWith(r, "rebalance", NewConfigFromEnv, NewRebalanceStrategy)
+}
+
+func (s *RebalanceStrategy) Name() string {
+	return s.name + ": " + s.cfg.Weights.String()
+}
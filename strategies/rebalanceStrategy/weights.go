@@ -0,0 +1,75 @@
+package rebalanceStrategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Weights are the target shares of the coins in the portfolio value,
+// written as "BTC:0.5,ETH:0.3,USDT:0.2"
+type Weights map[string]decimal.Decimal
+
+func (w *Weights) UnmarshalText(text []byte) error {
+	weights := make(Weights)
+
+	for _, item := range strings.Split(string(text), ",") {
+		coin, value, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			return fmt.Errorf("weight %q must be written as COIN:SHARE", item)
+		}
+
+		coin = strings.ToUpper(strings.TrimSpace(coin))
+		if coin == "" {
+			return fmt.Errorf("weight %q has no coin", item)
+		}
+
+		if _, ok := weights[coin]; ok {
+			return fmt.Errorf("coin %s is weighted twice", coin)
+		}
+
+		share, err := decimal.NewFromString(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("weight of %s: %w", coin, err)
+		}
+
+		weights[coin] = share
+	}
+
+	*w = weights
+
+	return nil
+}
+
+// Coins returns the weighted coins in alphabetical order
+func (w Weights) Coins() []string {
+	coins := make([]string, 0, len(w))
+	for coin := range w {
+		coins = append(coins, coin)
+	}
+
+	sort.Strings(coins)
+
+	return coins
+}
+
+// Sum is the total of the shares, valid weights sum to 1
+func (w Weights) Sum() decimal.Decimal {
+	sum := decimal.Zero
+	for _, share := range w {
+		sum = sum.Add(share)
+	}
+
+	return sum
+}
+
+func (w Weights) String() string {
+	items := make([]string, 0, len(w))
+	for _, coin := range w.Coins() {
+		items = append(items, coin+":"+w[coin].String())
+	}
+
+	return strings.Join(items, ",")
+}
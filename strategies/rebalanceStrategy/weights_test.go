@@ -0,0 +1,47 @@
+package rebalanceStrategy
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWeights_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "single coin", text: "BTC:1", want: "BTC:1"},
+		{name: "sorted by coin", text: "USDT:0.2,BTC:0.5,ETH:0.3", want: "BTC:0.5,ETH:0.3,USDT:0.2"},
+		{name: "spaces and lower case", text: " btc : 0.5 , usdt:0.5", want: "BTC:0.5,USDT:0.5"},
+		{name: "no share", text: "BTC", wantErr: true},
+		{name: "no coin", text: ":0.5", wantErr: true},
+		{name: "bad share", text: "BTC:half", wantErr: true},
+		{name: "duplicate coin", text: "BTC:0.5,btc:0.5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w Weights
+
+			err := w.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil && w.String() != tt.want {
+				t.Errorf("UnmarshalText() = %s, want %s", w, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeights_Sum(t *testing.T) {
+	w := Weights{"BTC": decimal.RequireFromString("0.5"), "ETH": decimal.RequireFromString("0.3"), "USDT": decimal.RequireFromString("0.2")}
+
+	if !w.Sum().Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Sum() = %s, want 1", w.Sum())
+	}
+}
@@ -0,0 +1,144 @@
+package strategies
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/helpers"
+)
+
+// Dependencies are the shared objects strategies are built from, clients of
+// disabled exchanges are nil
+type Dependencies struct {
+	Binance       clients.HttpClient
+	Bybit         clients.HttpClient
+	BinanceHelper *helpers.Helper
+}
+
+// Exchange returns the client of an enabled exchange by its name
+func (d Dependencies) Exchange(name string) (clients.HttpClient, error) {
+	var c clients.HttpClient
+
+	switch strings.ToLower(name) {
+	case "binance":
+		c = d.Binance
+	case "bybit":
+		c = d.Bybit
+	default:
+		return nil, fmt.Errorf("unknown exchange %q", name)
+	}
+
+	if c == nil {
+		return nil, fmt.Errorf("exchange %s is not enabled", name)
+	}
+
+	return c, nil
+}
+
+// With returns the dependencies with the clients wrapped by the middlewares
+// of their exchange, the helper is rebuilt on the wrapped binance client
+func (d Dependencies) With(middlewares func(exchange string) []clients.Middleware) Dependencies {
+	wrapped := d
+
+	if d.Binance != nil {
+		wrapped.Binance = clients.Chain(d.Binance, middlewares("binance")...)
+
+		if d.BinanceHelper != nil {
+			wrapped.BinanceHelper = helpers.NewHelper(wrapped.Binance, d.BinanceHelper.BaseCoin())
+		}
+	}
+
+	if d.Bybit != nil {
+		wrapped.Bybit = clients.Chain(d.Bybit, middlewares("bybit")...)
+	}
+
+	return wrapped
+}
+
+// Factory reads the configuration of a strategy and builds it
+type Factory func(deps Dependencies) (Strategy, error)
+
+// Registry keeps the strategies that can be enabled by their type name
+type Registry struct {
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds a strategy type built by newStrategy on the Binance client
+// from the config read by newConfig, building it fails while Binance is
+// disabled. It panics if the name is taken, which is a programming error.
+func Register[C any, S Strategy](
+	r *Registry,
+	name string,
+	newConfig func() (*C, error),
+	newStrategy func(c clients.HttpClient, cfg *C) S,
+) {
+	RegisterWith(r, name, newConfig, func(deps Dependencies, cfg *C) (S, error) {
+		c, err := deps.Exchange("binance")
+		if err != nil {
+			var s S
+			return s, err
+		}
+
+		return newStrategy(c, cfg), nil
+	})
+}
+
+// RegisterWith is Register for strategies that need more than the Binance
+// client, newStrategy fails if a dependency they need is missing
+func RegisterWith[C any, S Strategy](
+	r *Registry,
+	name string,
+	newConfig func() (*C, error),
+	newStrategy func(deps Dependencies, cfg *C) (S, error),
+) {
+	if _, ok := r.factories[name]; ok {
+		panic(fmt.Sprintf("strategy %q is already registered", name))
+	}
+
+	r.factories[name] = func(deps Dependencies) (Strategy, error) {
+		cfg, err := newConfig()
+		if err != nil {
+			return nil, fmt.Errorf("NewConfigFromEnv: %w", err)
+		}
+
+		strategy, err := newStrategy(deps, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return strategy, nil
+	}
+}
+
+// Names returns the registered type names in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// New builds the strategy registered under the name
+func (r *Registry) New(name string, deps Dependencies) (Strategy, error) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, available: %s", name, strings.Join(r.Names(), ", "))
+	}
+
+	strategy, err := factory(deps)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return strategy, nil
+}
@@ -0,0 +1,122 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/helpers"
+)
+
+type testConfig struct {
+	Symbol string
+}
+
+type testStrategy struct {
+	client clients.HttpClient
+	cfg    *testConfig
+}
+
+func (s *testStrategy) Name() string                    { return "test strategy: " + s.cfg.Symbol }
+func (s *testStrategy) Start(ctx context.Context) error { return nil }
+func (s *testStrategy) Stop(ctx context.Context) error  { return nil }
+
+func newTestStrategy(c clients.HttpClient, cfg *testConfig) *testStrategy {
+	return &testStrategy{client: c, cfg: cfg}
+}
+
+func TestRegistry(t *testing.T) {
+	errConfig := errors.New("STRATEGIES_BROKEN_SYMBOL is required")
+
+	r := NewRegistry()
+	Register(r, "test", func() (*testConfig, error) { return &testConfig{Symbol: "BNBUSDT"}, nil }, newTestStrategy)
+	Register(r, "broken", func() (*testConfig, error) { return nil, errConfig }, newTestStrategy)
+
+	if got, want := r.Names(), []string{"broken", "test"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+
+	c := mocks.NewHttpClient()
+
+	strategy, err := r.New("test", Dependencies{Binance: c})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if s := strategy.(*testStrategy); s.client != c || s.cfg.Symbol != "BNBUSDT" {
+		t.Errorf("New() = %+v, want the binance client and the config", s)
+	}
+
+	if _, err := r.New("broken", Dependencies{Binance: c}); !errors.Is(err, errConfig) {
+		t.Errorf("New() error = %v, want the config error", err)
+	}
+
+	if _, err := r.New("unknown", Dependencies{Binance: c}); err == nil {
+		t.Error("New() of an unknown strategy succeeded")
+	}
+
+	if _, err := r.New("test", Dependencies{Bybit: c}); err == nil {
+		t.Error("New() with binance disabled succeeded")
+	}
+}
+
+func TestRegister_duplicate(t *testing.T) {
+	r := NewRegistry()
+	Register(r, "test", func() (*testConfig, error) { return &testConfig{}, nil }, newTestStrategy)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the name twice didn't panic")
+		}
+	}()
+
+	Register(r, "test", func() (*testConfig, error) { return &testConfig{}, nil }, newTestStrategy)
+}
+
+func TestDependencies_Exchange(t *testing.T) {
+	c := mocks.NewHttpClient()
+	deps := Dependencies{Binance: c}
+
+	if got, err := deps.Exchange("Binance"); err != nil || got != c {
+		t.Errorf("Exchange(Binance) = %v, %v, want the binance client", got, err)
+	}
+
+	if _, err := deps.Exchange("bybit"); err == nil {
+		t.Error("Exchange() of a disabled exchange succeeded")
+	}
+
+	if _, err := deps.Exchange("kraken"); err == nil {
+		t.Error("Exchange() of an unknown exchange succeeded")
+	}
+}
+
+func TestDependencies_With(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice("BTCUSDT", "20000")
+
+	var exchanges, methods []string
+
+	deps := Dependencies{Binance: c, BinanceHelper: helpers.NewHelper(c, "USDT")}.With(func(exchange string) []clients.Middleware {
+		exchanges = append(exchanges, exchange)
+
+		return []clients.Middleware{clients.Intercept(func(ctx context.Context, call clients.Call, next func(context.Context) error) error {
+			methods = append(methods, call.Method)
+			return next(ctx)
+		})}
+	})
+
+	if deps.Bybit != nil {
+		t.Error("With() enabled bybit")
+	}
+
+	if _, err := deps.BinanceHelper.Price(context.Background(), "BTC"); err != nil {
+		t.Fatalf("Price() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(exchanges, []string{"binance"}) || !reflect.DeepEqual(methods, []string{"GetPrice"}) {
+		t.Errorf("wrapped exchanges = %v, calls = %v, want the binance GetPrice of the helper", exchanges, methods)
+	}
+}
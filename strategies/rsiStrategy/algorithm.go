@@ -0,0 +1,143 @@
+package rsiStrategy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/cinar/indicator"
+	"github.com/shopspring/decimal"
+)
+
+func (s *RSIStrategy) Start(ctx context.Context) error {
+	go s.position.StopLoss(ctx)
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	stopLossTicker := time.NewTicker(s.cfg.StopLossUpdatePeriod)
+	defer stopLossTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-stopLossTicker.C:
+			go s.position.StopLoss(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+type signal string
+
+const (
+	signalBuy       signal = "buy"
+	signalSell      signal = "sell"
+	signalDoNothing signal = "do-nothing"
+)
+
+type zone string
+
+const (
+	zoneUnknown    zone = "unknown"
+	zoneNeutral    zone = "neutral"
+	zoneOversold   zone = "oversold"
+	zoneOverbought zone = "overbought"
+)
+
+func (s *RSIStrategy) logic(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	klines, err := s.client.GetKlinesCloses(
+		ctx,
+		s.cfg.Symbol,
+		s.cfg.KlinesInterval,
+	)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get klines",
+			"interval", s.cfg.KlinesInterval,
+			"error", err.Error(),
+		)
+	}
+
+	// the first kline has no change, the average needs a full period of changes
+	if l := len(klines); l <= s.cfg.Period {
+		s.z.Warnw(
+			"not enough klines received",
+			"count", l,
+		)
+
+		return
+	}
+
+	signal, zone, rsi := s.getSignal(klines)
+	price := utils.RoundPrecision(decimal.NewFromFloat(klines[len(klines)-1]), s.cfg.PricePrecision)
+
+	s.z.Infow(
+		"new signal",
+		"signal", signal,
+		"price", price,
+		"RSI", rsi,
+		"zone", zone,
+	)
+
+	// a rejected or failed signal is given again on the next tick while the
+	// RSI stays in the zone
+	if s.act(ctx, signal, price) {
+		s.zone = zone
+	}
+}
+
+// getSignal buys when the RSI enters the oversold zone and sells when it
+// enters the overbought one, staying in a zone gives no new signal. The zone
+// seen first after the start is only remembered. The RSI of a series without
+// any change is undefined and counts as neutral.
+func (s *RSIStrategy) getSignal(klines []float64) (signal, zone, float64) {
+	_, rsiValues := indicator.RsiPeriod(s.cfg.Period, klines)
+
+	rsi := rsiValues[len(rsiValues)-1]
+
+	current := zoneNeutral
+
+	switch {
+	case rsi <= s.cfg.Oversold:
+		current = zoneOversold
+	case rsi >= s.cfg.Overbought:
+		current = zoneOverbought
+	}
+
+	switch {
+	case s.zone == zoneUnknown || s.zone == current:
+		return signalDoNothing, current, rsi
+	case current == zoneOversold:
+		return signalBuy, current, rsi
+	case current == zoneOverbought:
+		return signalSell, current, rsi
+	default:
+		return signalDoNothing, current, rsi
+	}
+}
+
+// act trades on the signal and tells whether the signal is done with
+func (s *RSIStrategy) act(ctx context.Context, sig signal, price decimal.Decimal) bool {
+	amount := s.position.OrderQuantity(price)
+
+	switch sig {
+	case signalBuy:
+		return s.confirmer.Allows(ctx, timeframes.Up) && s.position.Buy(ctx, price, amount) == nil
+	case signalSell:
+		return s.confirmer.Allows(ctx, timeframes.Down) && s.position.Sell(ctx, price, amount) == nil
+	default:
+		return true
+	}
+}
+
+func (s *RSIStrategy) Stop(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,230 @@
+package rsiStrategy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+func newTestConfig() *Config {
+	cfg := strategytest.NewConfig[Config]()
+	cfg.Period, cfg.Overbought, cfg.Oversold = 14, 70, 30
+
+	return cfg
+}
+
+var (
+	rising  = strategytest.Rising(30)
+	falling = strategytest.Falling(30)
+	zigzag  = strategytest.Series(30, func(i int) float64 { return 150 + float64(i%2) })
+	flat    = strategytest.Flat(30)
+)
+
+func TestConfig_validate(t *testing.T) {
+	strategytest.RunValidate(t, newTestConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "short period", Modify: func(cfg *Config) { cfg.Period = 1 }, WantErr: true},
+		{Name: "swapped thresholds", Modify: func(cfg *Config) { cfg.Oversold, cfg.Overbought = 70, 30 }, WantErr: true},
+		{Name: "overbought above 100", Modify: func(cfg *Config) { cfg.Overbought = 120 }, WantErr: true},
+	})
+}
+
+func TestRSIStrategy_getSignal(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous zone
+		closes   []float64
+		want     signal
+		wantZone zone
+	}{
+		{name: "enters oversold", previous: zoneNeutral, closes: falling, want: signalBuy, wantZone: zoneOversold},
+		{name: "enters overbought", previous: zoneNeutral, closes: rising, want: signalSell, wantZone: zoneOverbought},
+		{name: "from overbought to oversold", previous: zoneOverbought, closes: falling, want: signalBuy, wantZone: zoneOversold},
+		{name: "stays oversold", previous: zoneOversold, closes: falling, want: signalDoNothing, wantZone: zoneOversold},
+		{name: "stays overbought", previous: zoneOverbought, closes: rising, want: signalDoNothing, wantZone: zoneOverbought},
+		{name: "leaves oversold", previous: zoneOversold, closes: zigzag, want: signalDoNothing, wantZone: zoneNeutral},
+		{name: "oversold at the start", previous: zoneUnknown, closes: falling, want: signalDoNothing, wantZone: zoneOversold},
+		{name: "no change", previous: zoneOversold, closes: flat, want: signalDoNothing, wantZone: zoneNeutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRSIStrategy(mocks.NewHttpClient(), newTestConfig())
+			s.zone = tt.previous
+
+			got, zone, rsi := s.getSignal(tt.closes)
+			if got != tt.want || zone != tt.wantZone {
+				t.Errorf("getSignal() = %v, %v (RSI %v), want %v, %v", got, zone, rsi, tt.want, tt.wantZone)
+			}
+		})
+	}
+}
+
+func TestRSIStrategy_getSignalThresholds(t *testing.T) {
+	// gains and losses of the zigzag are balanced
+	s := NewRSIStrategy(mocks.NewHttpClient(), newTestConfig())
+	s.zone = zoneNeutral
+
+	if _, _, rsi := s.getSignal(zigzag); math.Abs(rsi-50) > 5 {
+		t.Fatalf("RSI = %v, want about 50", rsi)
+	}
+
+	s.cfg.Oversold, s.cfg.Overbought = 60, 90
+	if got, _, _ := s.getSignal(zigzag); got != signalBuy {
+		t.Errorf("getSignal() = %v with oversold 60, want buy", got)
+	}
+
+	s.cfg.Oversold, s.cfg.Overbought = 10, 40
+	if got, _, _ := s.getSignal(zigzag); got != signalSell {
+		t.Errorf("getSignal() = %v with overbought 40, want sell", got)
+	}
+}
+
+func TestRSIStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name          string
+		closes        []float64
+		position      string
+		holdings      string
+		baseCoin      bool
+		wantBuy       string
+		wantSell      string
+		klinesFailure error
+	}{
+		{name: "buy when oversold", closes: falling, wantBuy: "0.1"},
+		{name: "sell when overbought", closes: rising, position: "1", wantSell: "0.1"},
+		{name: "sell no more than bought", closes: rising, position: "0.045", holdings: "1", wantSell: "0.045"},
+		{name: "coins not bought aren't sold", closes: rising, holdings: "1"},
+		{name: "nothing to sell", closes: rising},
+		{name: "neutral", closes: zigzag, position: "1"},
+		{name: "amount in base coin", closes: falling, baseCoin: true, wantBuy: "0.058"},
+		{name: "not enough klines", closes: falling[:14]},
+		{name: "klines failure", closes: falling, klinesFailure: errors.New("timeout")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(strategytest.Symbol, "15m", tt.closes)
+			c.SetPrice(strategytest.Symbol, "200")
+
+			if tt.klinesFailure != nil {
+				c.Fail("GetKlinesCloses", tt.klinesFailure, 1)
+			}
+
+			cfg := newTestConfig()
+			cfg.BaseCoinForAmount = tt.baseCoin
+
+			if tt.baseCoin {
+				// 10 USDT worth of the coin at the last close of 171
+				cfg.OrderAmount = decimal.NewFromInt(10)
+			}
+
+			s := NewRSIStrategy(c, cfg)
+			s.zone = zoneNeutral
+
+			if tt.position != "" {
+				strategytest.Hold(t, s.position, c, tt.position)
+			}
+
+			if tt.holdings != "" {
+				c.SetBalance(strategytest.Coin, tt.holdings, "0")
+			}
+
+			s.logic(context.Background())
+
+			strategytest.AssertMarketOrder(t, c.CallsTo("NewMarketBuyOrder"), tt.wantBuy)
+			strategytest.AssertMarketOrder(t, c.CallsTo("NewMarketSellOrder"), tt.wantSell)
+		})
+	}
+}
+
+func TestRSIStrategy_TrendConfirmation(t *testing.T) {
+	tests := []struct {
+		name    string
+		trend   []float64
+		wantBuy bool
+	}{
+		{name: "higher timeframe agrees", trend: strategytest.Rising(60), wantBuy: true},
+		{name: "higher timeframe disagrees", trend: strategytest.Falling(60)},
+		{name: "no higher timeframe klines"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(strategytest.Symbol, "15m", falling)
+
+			if tt.trend != nil {
+				c.SetCloses(strategytest.Symbol, "4h", tt.trend)
+			}
+
+			cfg := newTestConfig()
+			cfg.TrendIntervals, cfg.TrendPeriod = []string{"4h"}, 20
+
+			s := NewRSIStrategy(c, cfg)
+			s.zone = zoneNeutral
+			s.logic(context.Background())
+
+			if got := len(c.CallsTo("NewMarketBuyOrder")) == 1; got != tt.wantBuy {
+				t.Errorf("buy placed = %v, want %v", got, tt.wantBuy)
+			}
+		})
+	}
+}
+
+func TestRSIStrategy_ConsecutiveOversold(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice(strategytest.Symbol, "200")
+
+	s := NewRSIStrategy(c, newTestConfig())
+
+	tick := func(closes []float64) {
+		c.SetCloses(strategytest.Symbol, "15m", closes)
+		s.logic(context.Background())
+	}
+
+	tick(zigzag)
+
+	for i := 0; i < 3; i++ {
+		tick(falling)
+	}
+
+	if calls := c.CallsTo("NewMarketBuyOrder"); len(calls) != 1 {
+		t.Fatalf("buys after consecutive oversold ticks = %d, want 1", len(calls))
+	}
+
+	// leaving the zone and entering it again is a new signal
+	tick(zigzag)
+	tick(falling)
+
+	if calls := c.CallsTo("NewMarketBuyOrder"); len(calls) != 2 {
+		t.Errorf("buys after oversold again = %d, want 2", len(calls))
+	}
+}
+
+func TestRSIStrategy_FailedBuyRetried(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetPrice(strategytest.Symbol, "200")
+	c.SetCloses(strategytest.Symbol, "15m", falling)
+	c.Fail("NewMarketBuyOrder", errors.New("timeout"), 1)
+
+	s := NewRSIStrategy(c, newTestConfig())
+	s.zone = zoneNeutral
+
+	s.logic(context.Background())
+	s.logic(context.Background())
+	s.logic(context.Background())
+
+	if calls := c.CallsTo("NewMarketBuyOrder"); len(calls) != 2 {
+		t.Errorf("buys = %d, want the failed one and a retry", len(calls))
+	}
+
+	if !s.position.InPosition() {
+		t.Error("not in position after the retried buy")
+	}
+}
@@ -0,0 +1,82 @@
+package rsiStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_RSI_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
+	PricePrecision    uint   `env:"STRATEGIES_RSI_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_RSI_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Interval             time.Duration   `env:"STRATEGIES_RSI_INTERVAL"                envDefault:"5m"`      // polling interval
+	StopLossUpdatePeriod time.Duration   `env:"STRATEGIES_RSI_STOP_LOSS_UPDATE_PERIOD" envDefault:"180m"`    // how often to update stop loss
+	StopLossShare        decimal.Decimal `env:"STRATEGIES_RSI_STOP_LOSS_SHARE"         envDefault:"0.85"`    // stop loss share of actual price
+	BaseCoinForAmount    bool            `env:"STRATEGIES_RSI_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
+	OrderAmount          decimal.Decimal `env:"STRATEGIES_RSI_ORDER_AMOUNT"            envDefault:"0.00005"` // quote coin amount for placing order
+	MaxOrdersAmount      decimal.Decimal `env:"STRATEGIES_RSI_MAX_ORDERS_AMOUNT"       envDefault:"100"`     // amount available for trading
+	KlinesInterval       string          `env:"STRATEGIES_RSI_KLINES_INTERVAL"         envDefault:"15m"`     // klines interval
+	Period               int             `env:"STRATEGIES_RSI_PERIOD"                  envDefault:"14"`      // number of klines RSI is averaged over
+	Overbought           float64         `env:"STRATEGIES_RSI_OVERBOUGHT"              envDefault:"70"`      // RSI at or above which the coin is sold
+	Oversold             float64         `env:"STRATEGIES_RSI_OVERSOLD"                envDefault:"30"`      // RSI at or below which the coin is bought
+	TrendIntervals       []string        `env:"STRATEGIES_RSI_TREND_INTERVALS"         envSeparator:","`     // higher timeframes whose trend must agree with signals, e.g. 4h,1d
+	TrendPeriod          int             `env:"STRATEGIES_RSI_TREND_PERIOD"            envDefault:"50"`      // EMA period of the higher timeframes trend
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.Period < 2 {
+		return fmt.Errorf("period must be at least 2, got %d", cfg.Period)
+	}
+
+	if cfg.Oversold <= 0 || cfg.Overbought >= 100 || cfg.Oversold >= cfg.Overbought {
+		return fmt.Errorf("thresholds must satisfy 0 < oversold < overbought < 100, got %v and %v", cfg.Oversold, cfg.Overbought)
+	}
+
+	if len(cfg.TrendIntervals) > 0 && cfg.TrendPeriod < 1 {
+		return fmt.Errorf("trend period must be positive, got %d", cfg.TrendPeriod)
+	}
+
+	return nil
+}
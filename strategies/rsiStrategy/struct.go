@@ -0,0 +1,58 @@
+package rsiStrategy
+
+import (
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/position"
+	"github.com/Minish144/crypto-trading-bot/strategies/timeframes"
+	"go.uber.org/zap"
+)
+
+type RSIStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	test   bool
+	z      *zap.SugaredLogger
+
+	confirmer *timeframes.Confirmer
+	position  *position.Position
+
+	mu   sync.Mutex
+	zone zone // zone of the RSI at the last tick a signal was done with
+}
+
+func NewRSIStrategy(c clients.HttpClient, cfg *Config) *RSIStrategy {
+	z := zap.S().With("context", "RSIStrategy", "symbol", cfg.Symbol)
+
+	return &RSIStrategy{
+		name:   "RSI strategy",
+		cfg:    cfg,
+		client: c,
+		z:      z,
+
+		confirmer: timeframes.NewConfirmer("rsi", c, cfg.Symbol, cfg.TrendIntervals, timeframes.EMATrend(cfg.TrendPeriod)),
+		position: position.New(c, position.Config{
+			Symbol:            cfg.Symbol,
+			Coin:              cfg.Coins.Quote,
+			PricePrecision:    cfg.PricePrecision,
+			QuantityPrecision: cfg.QuantityPrecision,
+			BaseCoinForAmount: cfg.BaseCoinForAmount,
+			OrderAmount:       cfg.OrderAmount,
+			MaxOrdersAmount:   cfg.MaxOrdersAmount,
+			StopLossShare:     cfg.StopLossShare,
+		}, z),
+		zone: zoneUnknown,
+	}
+}
+
+// Register adds the strategy to the registry as "rsi"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "rsi", NewConfigFromEnv, NewRSIStrategy)
+}
+
+func (s *RSIStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol
+}
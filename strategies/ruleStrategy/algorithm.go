@@ -0,0 +1,86 @@
+package ruleStrategy
+
+import (
+	"context"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+func (s *RuleStrategy) Start(ctx context.Context) error {
+	go s.position.StopLoss(ctx)
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	stopLossTicker := time.NewTicker(s.cfg.StopLossUpdatePeriod)
+	defer stopLossTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-stopLossTicker.C:
+			go s.position.StopLoss(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// logic opens the position when the entry rule holds and closes it when the
+// exit rule holds, both rules are evaluated on every tick
+func (s *RuleStrategy) logic(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	klines, err := s.client.GetKlines(
+		ctx,
+		s.cfg.Symbol,
+		s.cfg.KlinesInterval,
+	)
+	if err != nil {
+		s.z.Warnw(
+			"failed to get klines",
+			"interval", s.cfg.KlinesInterval,
+			"error", err.Error(),
+		)
+	}
+
+	if l, need := len(klines), max(s.cfg.Entry.lookback(), s.cfg.Exit.lookback()); l < need {
+		s.z.Warnw(
+			"not enough klines received",
+			"count", l,
+			"need", need,
+		)
+
+		return
+	}
+
+	series := newSeries(klines)
+	entry, exit := s.cfg.Entry.holds(series), s.cfg.Exit.holds(series)
+	price := utils.RoundPrecision(decimal.NewFromFloat(klines[len(klines)-1].Close), s.cfg.PricePrecision)
+
+	s.z.Infow(
+		"rules evaluated",
+		"entry_rule", s.cfg.Entry.String(),
+		"entry", entry,
+		"exit_rule", s.cfg.Exit.String(),
+		"exit", exit,
+		"price", price,
+		"in_position", s.position.InPosition(),
+	)
+
+	switch {
+	case entry && !s.position.InPosition():
+		_ = s.position.Buy(ctx, price, s.position.OrderQuantity(price))
+	case exit && s.position.InPosition():
+		_ = s.position.Close(ctx, price)
+	}
+}
+
+func (s *RuleStrategy) Stop(ctx context.Context) error {
+	return nil
+}
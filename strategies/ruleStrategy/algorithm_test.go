@@ -0,0 +1,117 @@
+package ruleStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+func mustRule(t *testing.T, text string) rule {
+	t.Helper()
+
+	var r rule
+	if err := r.UnmarshalText([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func newTestConfig(t *testing.T) *Config {
+	t.Helper()
+
+	cfg := strategytest.NewConfig[Config]()
+	cfg.Entry = mustRule(t, "close > sma(10) AND rsi(14) > 50")
+	cfg.Exit = mustRule(t, "close < sma(10)")
+
+	return cfg
+}
+
+var (
+	rising  = strategytest.Rising(30)
+	falling = strategytest.Falling(30)
+)
+
+func TestRuleStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name          string
+		closes        []float64
+		position      string
+		holdings      string
+		baseCoin      bool
+		wantBuy       string
+		wantSell      string
+		klinesFailure error
+	}{
+		{name: "entry rule buys", closes: rising, wantBuy: "0.1"},
+		{name: "no entry while in position", closes: rising, position: "1"},
+		{name: "exit rule sells the position", closes: falling, position: "1.234", holdings: "5", wantSell: "1.234"},
+		{name: "no exit without position", closes: falling, holdings: "1"},
+		{name: "amount in base coin", closes: rising, baseCoin: true, wantBuy: "0.077"},
+		{name: "not enough klines", closes: rising[:14]},
+		{name: "klines failure", closes: rising, klinesFailure: errors.New("timeout")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(strategytest.Symbol, "15m", tt.closes)
+
+			if tt.klinesFailure != nil {
+				c.Fail("GetKlines", tt.klinesFailure, 1)
+			}
+
+			cfg := newTestConfig(t)
+			cfg.BaseCoinForAmount = tt.baseCoin
+
+			if tt.baseCoin {
+				// 10 USDT worth of the coin at the last close of 129
+				cfg.OrderAmount = decimal.NewFromInt(10)
+			}
+
+			s := NewRuleStrategy(c, cfg)
+
+			if tt.position != "" {
+				strategytest.Hold(t, s.position, c, tt.position)
+			}
+
+			if tt.holdings != "" {
+				c.SetBalance(strategytest.Coin, tt.holdings, "0")
+			}
+
+			s.logic(context.Background())
+
+			strategytest.AssertMarketOrder(t, c.CallsTo("NewMarketBuyOrder"), tt.wantBuy)
+			strategytest.AssertMarketOrder(t, c.CallsTo("NewMarketSellOrder"), tt.wantSell)
+		})
+	}
+}
+
+func TestRuleStrategy_RoundTrip(t *testing.T) {
+	c := mocks.NewHttpClient()
+	s := NewRuleStrategy(c, newTestConfig(t))
+
+	// the mock doesn't move balances, the bought coins are there from the start
+	c.SetBalance(strategytest.Coin, "0.1", "0")
+
+	for _, closes := range [][]float64{rising, rising, falling, falling, rising} {
+		c.SetCloses(strategytest.Symbol, "15m", closes)
+		s.logic(context.Background())
+	}
+
+	if got := len(c.CallsTo("NewMarketBuyOrder")); got != 2 {
+		t.Errorf("placed %d buys, want 2", got)
+	}
+
+	if got := len(c.CallsTo("NewMarketSellOrder")); got != 1 {
+		t.Errorf("placed %d sells, want 1", got)
+	}
+
+	if !s.position.InPosition() {
+		t.Error("not in position after the last buy")
+	}
+}
@@ -0,0 +1,59 @@
+package ruleStrategy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	Symbol            string `env:"STRATEGIES_RULE_SYMBOL"          envDefault:"BTCUSDT"` // trading pair symbol
+	PricePrecision    uint   `env:"STRATEGIES_RULE_PRICE_PRECISION" envDefault:"3"`       // price decimal places
+	QuantityPrecision uint   `env:"STRATEGIES_RULE_QTY_PRECISION"   envDefault:"3"`       // quantity decimal places
+	Coins             struct {
+		Quote string
+		Base  string
+	}
+	Interval             time.Duration   `env:"STRATEGIES_RULE_INTERVAL"                envDefault:"5m"`      // polling interval
+	StopLossUpdatePeriod time.Duration   `env:"STRATEGIES_RULE_STOP_LOSS_UPDATE_PERIOD" envDefault:"180m"`    // how often to update stop loss
+	StopLossShare        decimal.Decimal `env:"STRATEGIES_RULE_STOP_LOSS_SHARE"         envDefault:"0.85"`    // stop loss share of actual price
+	BaseCoinForAmount    bool            `env:"STRATEGIES_RULE_BASE_COIN_FOR_AMOUNT"    envDefault:"false"`   // whether to use base coin for ORDER_AMOUNT
+	OrderAmount          decimal.Decimal `env:"STRATEGIES_RULE_ORDER_AMOUNT"            envDefault:"0.00005"` // quote coin amount for placing order
+	MaxOrdersAmount      decimal.Decimal `env:"STRATEGIES_RULE_MAX_ORDERS_AMOUNT"       envDefault:"100"`     // amount available for trading
+	KlinesInterval       string          `env:"STRATEGIES_RULE_KLINES_INTERVAL"         envDefault:"15m"`     // klines interval
+	Entry                rule            `env:"STRATEGIES_RULE_ENTRY,required"`                               // buy rule, e.g. rsi(14) < 30 AND close > sma(200)
+	Exit                 rule            `env:"STRATEGIES_RULE_EXIT,required"`                                // sell rule of the position, e.g. rsi(14) > 70
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	sym, err := utils.ConvertSymbol(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	quote, err := utils.GetQuoteCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	base, err := utils.GetBaseCoin(cfg.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("utils.ConvertSymbol: %w", err)
+	}
+
+	cfg.Symbol = sym
+	cfg.Coins.Quote = quote
+	cfg.Coins.Base = base
+
+	cfg.OrderAmount = utils.TruncatePrecision(cfg.OrderAmount, cfg.QuantityPrecision)
+
+	return cfg, nil
+}
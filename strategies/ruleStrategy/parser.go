@@ -0,0 +1,281 @@
+package ruleStrategy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize splits a rule into numbers, identifiers, comparison operators,
+// parentheses and commas. The grammar has no subtraction, a minus is the sign
+// of the number right after it.
+func tokenize(rule string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(rule); {
+		c := rune(rule[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case strings.ContainsRune("<>=!", c):
+			j := i + 1
+			if j < len(rule) && rule[j] == '=' {
+				j++
+			}
+
+			op := rule[i:j]
+			if _, ok := comparisons[op]; !ok {
+				return nil, fmt.Errorf("unknown operator %q at %d", op, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i = j
+		case unicode.IsDigit(c) || c == '.' || c == '-' && i+1 < len(rule) && isNumeric(rule[i+1]):
+			j := i + 1
+			for j < len(rule) && isNumeric(rule[j]) {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: rule[i:j], pos: i})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(rule) && (unicode.IsLetter(rune(rule[j])) || unicode.IsDigit(rune(rule[j])) || rule[j] == '_') {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: rule[i:j], pos: i})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", c, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(rule)}), nil
+}
+
+func isNumeric(c byte) bool {
+	return unicode.IsDigit(rune(c)) || c == '.'
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or         = and { "OR" and }
+//	and        = not { "AND" not }
+//	not        = "NOT" not | "(" or ")" | comparison
+//	comparison = value operator value
+//	value      = number | name [ "(" [ number { "," number } ] ")" ]
+//	number     = [ "-" ] digits [ "." digits ]
+//	operator   = "<" | "<=" | ">" | ">=" | "==" | "!=" | "crosses_above" | "crosses_below"
+//
+// keywords are case insensitive
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parseRule parses a rule such as "rsi(14) < 30 AND close > sma(200)"
+func parseRule(rule string) (condition, error) {
+	tokens, err := tokenize(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+
+	return cond, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) or() (condition, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orCondition{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) and() (condition, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("AND") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andCondition{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) not() (condition, error) {
+	if p.keyword("NOT") {
+		cond, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+
+		return notCondition{cond}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if t := p.next(); t.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at %d", t.pos)
+		}
+
+		return cond, nil
+	}
+
+	return p.comparison()
+}
+
+func (p *parser) comparison() (condition, error) {
+	left, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.next()
+
+	op := strings.ToLower(t.text)
+	if t.kind != tokenOperator && !(t.kind == tokenIdent && (op == opCrossesAbove || op == opCrossesBelow)) {
+		return nil, fmt.Errorf("expected comparison operator at %d", t.pos)
+	}
+
+	right, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison{left: left, op: op, right: right}, nil
+}
+
+func (p *parser) value() (value, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", t.text, t.pos)
+		}
+
+		return constant(n), nil
+	case tokenIdent:
+		name := strings.ToLower(t.text)
+
+		var args []float64
+
+		if p.peek().kind == tokenLParen {
+			p.next()
+
+			for p.peek().kind != tokenRParen {
+				if len(args) > 0 {
+					if c := p.next(); c.kind != tokenComma {
+						return nil, fmt.Errorf("expected \",\" at %d", c.pos)
+					}
+				}
+
+				a := p.next()
+				if a.kind != tokenNumber {
+					return nil, fmt.Errorf("expected number argument of %s at %d", name, a.pos)
+				}
+
+				n, err := strconv.ParseFloat(a.text, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid number %q at %d", a.text, a.pos)
+				}
+
+				args = append(args, n)
+			}
+
+			p.next()
+		}
+
+		return newIndicator(name, args)
+	default:
+		return nil, fmt.Errorf("expected value at %d", t.pos)
+	}
+}
@@ -0,0 +1,81 @@
+package ruleStrategy
+
+import "testing"
+
+func Test_parseRule(t *testing.T) {
+	tests := []struct {
+		rule         string
+		want         string
+		wantLookback int
+	}{
+		{rule: "rsi(14) < 30 AND close > sma(200)", want: "(rsi(14) < 30 AND close > sma(200))", wantLookback: 200},
+		{rule: "close > 1 OR close > 2 AND close > 3", want: "(close > 1 OR (close > 2 AND close > 3))", wantLookback: 1},
+		{rule: "(close > 1 OR close > 2) AND close > 3", want: "((close > 1 OR close > 2) AND close > 3)", wantLookback: 1},
+		{rule: "not (close >= ema(9))", want: "NOT close >= ema(9)", wantLookback: 9},
+		{rule: "RSI(14) Crosses_Above 30", want: "rsi(14) crosses_above 30", wantLookback: 16},
+		{rule: "close crosses_below bb_lower(20, 2.5)", want: "close crosses_below bb_lower(20, 2.5)", wantLookback: 21},
+		{rule: "macd crosses_above macd_signal", want: "macd crosses_above macd_signal", wantLookback: 35},
+		{rule: "volume != 0.5", want: "volume != 0.5", wantLookback: 1},
+		{rule: "macd < -0.5", want: "macd < -0.5", wantLookback: 26},
+		{rule: "-.5 < macd_signal AND macd>-2", want: "(-0.5 < macd_signal AND macd > -2)", wantLookback: 34},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := parseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("parseRule() error = %v", err)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("parseRule() = %v, want %v", got, tt.want)
+			}
+
+			if got.lookback() != tt.wantLookback {
+				t.Errorf("lookback() = %v, want %v", got.lookback(), tt.wantLookback)
+			}
+
+			// the printed rule parses into the same rule
+			again, err := parseRule(got.String())
+			if err != nil || again.String() != tt.want {
+				t.Errorf("parseRule(%q) = %v, %v", got.String(), again, err)
+			}
+		})
+	}
+}
+
+func Test_parseRuleErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"close",
+		"rsi(14) <",
+		"rsi(14) < 30 AND",
+		"close = 1",
+		"close $ 1",
+		"close > 1)",
+		"(close > 1",
+		"close > 1 2",
+		"foo(1) > 2",
+		"sma() > 1",
+		"sma(0) > 1",
+		"sma(2.5) > 1",
+		"sma(1 2) > 1",
+		"sma(close) > 1",
+		"close(1) > 1",
+		"bb_upper(20) > close",
+		"close > 1..2",
+		"close > -",
+		"close > - 1",
+		"close > --1",
+		"-close > 1",
+		"sma(-5) > 1",
+	}
+
+	for _, rule := range tests {
+		t.Run(rule, func(t *testing.T) {
+			if got, err := parseRule(rule); err == nil {
+				t.Errorf("parseRule() = %v, want error", got)
+			}
+		})
+	}
+}
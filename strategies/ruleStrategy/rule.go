@@ -0,0 +1,310 @@
+package ruleStrategy
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/cinar/indicator"
+)
+
+// series are the kline values a rule is evaluated over, oldest first
+type series struct {
+	open   []float64
+	high   []float64
+	low    []float64
+	close  []float64
+	volume []float64
+}
+
+func newSeries(klines []*models.Kline) *series {
+	s := &series{
+		open:   make([]float64, len(klines)),
+		high:   make([]float64, len(klines)),
+		low:    make([]float64, len(klines)),
+		close:  make([]float64, len(klines)),
+		volume: make([]float64, len(klines)),
+	}
+
+	for i, k := range klines {
+		s.open[i], s.high[i], s.low[i], s.close[i], s.volume[i] = k.Open, k.High, k.Low, k.Close, k.Volume
+	}
+
+	return s
+}
+
+// value is a number per kline, e.g. the close price or an indicator
+type value interface {
+	values(s *series) []float64
+	lookback() int // klines needed for the last value to be meaningful
+	String() string
+}
+
+// condition is a part of a rule that holds or not at the last kline
+type condition interface {
+	holds(s *series) bool
+	lookback() int
+	String() string
+}
+
+type constant float64
+
+func (c constant) values(s *series) []float64 {
+	res := make([]float64, len(s.close))
+	for i := range res {
+		res[i] = float64(c)
+	}
+
+	return res
+}
+
+func (c constant) lookback() int { return 1 }
+
+func (c constant) String() string { return strconv.FormatFloat(float64(c), 'f', -1, 64) }
+
+const (
+	opCrossesAbove = "crosses_above"
+	opCrossesBelow = "crosses_below"
+)
+
+var comparisons = map[string]func(a, b float64) bool{
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// comparison compares the last values, crossings compare the last two
+type comparison struct {
+	left  value
+	op    string
+	right value
+}
+
+func (c comparison) holds(s *series) bool {
+	l, r := c.left.values(s), c.right.values(s)
+
+	n := len(l)
+	if n == 0 || len(r) != n {
+		return false
+	}
+
+	switch c.op {
+	case opCrossesAbove:
+		return n > 1 && l[n-2] <= r[n-2] && l[n-1] > r[n-1]
+	case opCrossesBelow:
+		return n > 1 && l[n-2] >= r[n-2] && l[n-1] < r[n-1]
+	default:
+		// comparisons with NaN are false, so undefined indicators never hold
+		return comparisons[c.op](l[n-1], r[n-1])
+	}
+}
+
+func (c comparison) lookback() int {
+	n := max(c.left.lookback(), c.right.lookback())
+	if c.op == opCrossesAbove || c.op == opCrossesBelow {
+		n++
+	}
+
+	return n
+}
+
+func (c comparison) String() string {
+	return c.left.String() + " " + c.op + " " + c.right.String()
+}
+
+type andCondition struct{ left, right condition }
+
+func (c andCondition) holds(s *series) bool { return c.left.holds(s) && c.right.holds(s) }
+
+func (c andCondition) lookback() int { return max(c.left.lookback(), c.right.lookback()) }
+
+func (c andCondition) String() string {
+	return "(" + c.left.String() + " AND " + c.right.String() + ")"
+}
+
+type orCondition struct{ left, right condition }
+
+func (c orCondition) holds(s *series) bool { return c.left.holds(s) || c.right.holds(s) }
+
+func (c orCondition) lookback() int { return max(c.left.lookback(), c.right.lookback()) }
+
+func (c orCondition) String() string {
+	return "(" + c.left.String() + " OR " + c.right.String() + ")"
+}
+
+type notCondition struct{ cond condition }
+
+func (c notCondition) holds(s *series) bool { return !c.cond.holds(s) }
+
+func (c notCondition) lookback() int { return c.cond.lookback() }
+
+func (c notCondition) String() string { return "NOT " + c.cond.String() }
+
+// indicatorSpec describes an indicator function usable in rules
+type indicatorSpec struct {
+	args     []string // names of the arguments, all required
+	lookback func(args []float64) int
+	values   func(s *series, args []float64) []float64
+}
+
+var indicators = map[string]indicatorSpec{
+	"open":   {values: func(s *series, _ []float64) []float64 { return s.open }},
+	"high":   {values: func(s *series, _ []float64) []float64 { return s.high }},
+	"low":    {values: func(s *series, _ []float64) []float64 { return s.low }},
+	"close":  {values: func(s *series, _ []float64) []float64 { return s.close }},
+	"volume": {values: func(s *series, _ []float64) []float64 { return s.volume }},
+	"sma": {
+		args:     []string{"period"},
+		lookback: func(args []float64) int { return int(args[0]) },
+		values:   func(s *series, args []float64) []float64 { return indicator.Sma(int(args[0]), s.close) },
+	},
+	"ema": {
+		args:     []string{"period"},
+		lookback: func(args []float64) int { return int(args[0]) },
+		values:   func(s *series, args []float64) []float64 { return indicator.Ema(int(args[0]), s.close) },
+	},
+	"rsi": {
+		args:     []string{"period"},
+		lookback: func(args []float64) int { return int(args[0]) + 1 },
+		values: func(s *series, args []float64) []float64 {
+			_, rsi := indicator.RsiPeriod(int(args[0]), s.close)
+			return rsi
+		},
+	},
+	"highest": {
+		args:     []string{"period"},
+		lookback: func(args []float64) int { return int(args[0]) },
+		values:   func(s *series, args []float64) []float64 { return indicator.Max(int(args[0]), s.high) },
+	},
+	"lowest": {
+		args:     []string{"period"},
+		lookback: func(args []float64) int { return int(args[0]) },
+		values:   func(s *series, args []float64) []float64 { return indicator.Min(int(args[0]), s.low) },
+	},
+	"macd": {
+		lookback: func([]float64) int { return 26 },
+		values: func(s *series, _ []float64) []float64 {
+			macd, _ := indicator.Macd(s.close)
+			return macd
+		},
+	},
+	"macd_signal": {
+		lookback: func([]float64) int { return 34 },
+		values: func(s *series, _ []float64) []float64 {
+			_, signal := indicator.Macd(s.close)
+			return signal
+		},
+	},
+	"bb_upper": {
+		args:     []string{"period", "deviation"},
+		lookback: func(args []float64) int { return int(args[0]) },
+		values:   func(s *series, args []float64) []float64 { return band(s.close, int(args[0]), args[1]) },
+	},
+	"bb_lower": {
+		args:     []string{"period", "deviation"},
+		lookback: func(args []float64) int { return int(args[0]) },
+		values:   func(s *series, args []float64) []float64 { return band(s.close, int(args[0]), -args[1]) },
+	},
+}
+
+// band is the Bollinger band deviations standard deviations away from the SMA
+func band(closes []float64, period int, deviations float64) []float64 {
+	sma := indicator.Sma(period, closes)
+	std := indicator.StdFromSma(period, closes, sma)
+
+	res := make([]float64, len(closes))
+	for i := range res {
+		dev := std[i]
+		if math.IsNaN(dev) {
+			dev = 0
+		}
+
+		res[i] = sma[i] + deviations*dev
+	}
+
+	return res
+}
+
+// indicatorValue is an indicator with its arguments
+type indicatorValue struct {
+	name string
+	args []float64
+	spec indicatorSpec
+}
+
+func newIndicator(name string, args []float64) (value, error) {
+	spec, ok := indicators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown indicator %q", name)
+	}
+
+	if len(args) != len(spec.args) {
+		return nil, fmt.Errorf("%s takes %d arguments (%s), got %d", name, len(spec.args), strings.Join(spec.args, ", "), len(args))
+	}
+
+	for i, a := range args {
+		if a <= 0 {
+			return nil, fmt.Errorf("%s of %s must be positive, got %v", spec.args[i], name, a)
+		}
+
+		if spec.args[i] == "period" && a != math.Trunc(a) {
+			return nil, fmt.Errorf("period of %s must be an integer, got %v", name, a)
+		}
+	}
+
+	return indicatorValue{name: name, args: args, spec: spec}, nil
+}
+
+func (v indicatorValue) values(s *series) []float64 {
+	return v.spec.values(s, v.args)
+}
+
+func (v indicatorValue) lookback() int {
+	if v.spec.lookback == nil {
+		return 1
+	}
+
+	return v.spec.lookback(v.args)
+}
+
+func (v indicatorValue) String() string {
+	if len(v.spec.args) == 0 {
+		return v.name
+	}
+
+	args := make([]string, len(v.args))
+	for i, a := range v.args {
+		args[i] = strconv.FormatFloat(a, 'f', -1, 64)
+	}
+
+	return v.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// rule is a condition parsed from the configuration
+type rule struct {
+	condition
+}
+
+func (r *rule) UnmarshalText(text []byte) error {
+	cond, err := parseRule(string(text))
+	if err != nil {
+		return fmt.Errorf("rule %q: %w", text, err)
+	}
+
+	*r = rule{condition: cond}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package ruleStrategy
+
+import "testing"
+
+func closesSeries(closes ...float64) *series {
+	volume := make([]float64, len(closes))
+	for i := range volume {
+		volume[i] = 1
+	}
+
+	return &series{open: closes, high: closes, low: closes, close: closes, volume: volume}
+}
+
+func Test_condition_holds(t *testing.T) {
+	var (
+		rising  = make([]float64, 40)
+		falling = make([]float64, 40)
+		flat    = make([]float64, 40)
+	)
+
+	for i := range rising {
+		rising[i], falling[i], flat[i] = 100+float64(i), 200-float64(i), 100
+	}
+
+	tests := []struct {
+		name   string
+		rule   string
+		closes []float64
+		want   bool
+	}{
+		{name: "above sma", rule: "close > sma(10)", closes: rising, want: true},
+		{name: "below sma", rule: "close < sma(10)", closes: rising},
+		{name: "overbought", rule: "rsi(14) > 70", closes: rising, want: true},
+		{name: "and", rule: "rsi(14) > 70 AND close > ema(5)", closes: rising, want: true},
+		{name: "and fails", rule: "rsi(14) > 70 AND close > 1000", closes: rising},
+		{name: "or", rule: "close > 1000 OR close >= highest(5)", closes: rising, want: true},
+		{name: "or fails", rule: "close > 1000 OR close < lowest(5)", closes: rising},
+		{name: "not", rule: "NOT close > sma(10)", closes: rising},
+		{name: "precedence", rule: "close > 1000 AND close > 0 OR close > 0", closes: rising, want: true},
+		{name: "parentheses", rule: "close > 1000 AND (close > 0 OR close > 0)", closes: rising},
+		{name: "undefined rsi is false", rule: "rsi(14) < 30", closes: flat},
+		{name: "undefined rsi is false both ways", rule: "rsi(14) >= 30", closes: flat},
+		{name: "flat bands", rule: "close >= bb_upper(20, 2) AND close <= bb_lower(20, 2)", closes: flat, want: true},
+		{name: "crosses above", rule: "close crosses_above sma(3)", closes: []float64{100, 100, 100, 90, 110}, want: true},
+		{name: "already above", rule: "close crosses_above sma(3)", closes: []float64{100, 100, 90, 110, 120}},
+		{name: "crosses below", rule: "close crosses_below 95", closes: []float64{100, 100, 100, 100, 90}, want: true},
+		{name: "single kline never crosses", rule: "close crosses_below 95", closes: []float64{90}},
+		{name: "negative constant", rule: "close > -1 AND -1 < close", closes: rising, want: true},
+		{name: "falling macd below negative constant", rule: "macd < -0.5", closes: falling, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := parseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("parseRule() error = %v", err)
+			}
+
+			if got := cond.holds(closesSeries(tt.closes...)); got != tt.want {
+				t.Errorf("holds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
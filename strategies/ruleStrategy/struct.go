@@ -0,0 +1,52 @@
+package ruleStrategy
+
+import (
+	"sync"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"github.com/Minish144/crypto-trading-bot/strategies/position"
+	"go.uber.org/zap"
+)
+
+type RuleStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	test   bool
+	z      *zap.SugaredLogger
+
+	position *position.Position
+
+	mu sync.Mutex
+}
+
+func NewRuleStrategy(c clients.HttpClient, cfg *Config) *RuleStrategy {
+	z := zap.S().With("context", "RuleStrategy", "symbol", cfg.Symbol)
+	return &RuleStrategy{
+		name:   "rule strategy",
+		cfg:    cfg,
+		client: c,
+		z:      z,
+
+		position: position.New(c, position.Config{
+			Symbol:            cfg.Symbol,
+			Coin:              cfg.Coins.Quote,
+			PricePrecision:    cfg.PricePrecision,
+			QuantityPrecision: cfg.QuantityPrecision,
+			BaseCoinForAmount: cfg.BaseCoinForAmount,
+			OrderAmount:       cfg.OrderAmount,
+			MaxOrdersAmount:   cfg.MaxOrdersAmount,
+			StopLossShare:     cfg.StopLossShare,
+		}, z),
+	}
+}
+
+// Register adds the strategy to the registry as "rule"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "rule", NewConfigFromEnv, NewRuleStrategy)
+}
+
+func (s *RuleStrategy) Name() string {
+	return s.name + ": " + s.cfg.Symbol
+}
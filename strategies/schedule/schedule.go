@@ -0,0 +1,39 @@
+// Package schedule parses cron schedules of strategies
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule is a standard five fields cron expression ("minute hour day
+// month weekday") or a descriptor such as "@daily" or "@every 6h"
+type Schedule struct {
+	cron.Schedule
+	spec string
+}
+
+func Parse(spec string) (Schedule, error) {
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("cron.ParseStandard: %w", err)
+	}
+
+	return Schedule{Schedule: sched, spec: spec}, nil
+}
+
+func (s *Schedule) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = parsed
+
+	return nil
+}
+
+func (s Schedule) String() string {
+	return s.spec
+}
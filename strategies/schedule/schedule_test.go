@@ -0,0 +1,46 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2022, time.June, 1, 10, 30, 0, 0, time.UTC) // Wednesday
+
+	tests := []struct {
+		spec    string
+		want    time.Time
+		wantErr bool
+	}{
+		{spec: "0 0 * * *", want: time.Date(2022, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 9 * * 1", want: time.Date(2022, time.June, 6, 9, 0, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2022, time.June, 1, 10, 45, 0, 0, time.UTC)},
+		{spec: "@every 6h", want: from.Add(6 * time.Hour)},
+		{spec: "0 0 * *", wantErr: true},
+		{spec: "every day", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			var s Schedule
+
+			err := s.UnmarshalText([]byte(tt.spec))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+
+			if s.String() != tt.spec {
+				t.Errorf("String() = %q, want %q", s.String(), tt.spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package strategytest provides the fixtures shared by the tests of the
+// strategies: the symbol and config they trade with, kline series, a position
+// bought before the test and checks of the orders placed on the mock client.
+package strategytest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/strategies/position"
+	"github.com/shopspring/decimal"
+)
+
+// Symbol traded by the tests of the indicator strategies
+const (
+	Symbol   = "BNBUSDT"
+	Coin     = "BNB" // traded coin of the symbol
+	BaseCoin = "USDT"
+)
+
+// D parses a decimal written in the test
+func D(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// NewConfig returns a config of an indicator strategy trading Symbol with the
+// settings the indicator strategies share, the test sets the ones of the
+// strategy. The fields are set by name, C must have all of them.
+func NewConfig[C any]() *C {
+	cfg := new(C)
+	v := reflect.ValueOf(cfg).Elem()
+
+	for name, value := range map[string]interface{}{
+		"Symbol":            Symbol,
+		"PricePrecision":    uint(2),
+		"QuantityPrecision": uint(3),
+		"StopLossShare":     D("0.9"),
+		"OrderAmount":       D("0.1"),
+		"MaxOrdersAmount":   decimal.NewFromInt(100),
+		"KlinesInterval":    "15m",
+	} {
+		v.FieldByName(name).Set(reflect.ValueOf(value))
+	}
+
+	coins := v.FieldByName("Coins")
+	coins.FieldByName("Quote").SetString(Coin)
+	coins.FieldByName("Base").SetString(BaseCoin)
+
+	return cfg
+}
+
+// Series returns n closes made by f
+func Series(n int, f func(i int) float64) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = f(i)
+	}
+
+	return closes
+}
+
+// Rising returns n closes growing by 1 from 100
+func Rising(n int) []float64 {
+	return Series(n, func(i int) float64 { return 100 + float64(i) })
+}
+
+// Falling returns n closes dropping by 1 from 200
+func Falling(n int) []float64 {
+	return Series(n, func(i int) float64 { return 200 - float64(i) })
+}
+
+// Flat returns n closes of 150
+func Flat(n int) []float64 {
+	return Series(n, func(int) float64 { return 150 })
+}
+
+// Hold makes the position buy the quantity of Coin before the test. The mock
+// doesn't move balances, so the coins are put on the balance, and the calls
+// of the buy are reset.
+func Hold(t testing.TB, p *position.Position, c *mocks.HttpClient, quantity string) {
+	t.Helper()
+
+	// the price only has to pass the buy limit, the mock fills at its own one
+	if err := p.Buy(context.Background(), decimal.NewFromInt(1), D(quantity)); err != nil {
+		t.Fatalf("Buy() error = %v", err)
+	}
+
+	c.SetBalance(Coin, quantity, "0")
+	c.ResetCalls()
+}
+
+// AssertMarketOrder checks the calls placing market orders: none if want is
+// empty, otherwise a single one of the quantity
+func AssertMarketOrder(t testing.TB, calls []mocks.Call, want string) {
+	t.Helper()
+
+	if want == "" {
+		if len(calls) != 0 {
+			t.Errorf("unexpected orders %v", calls)
+		}
+
+		return
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("placed %d orders, want 1", len(calls))
+	}
+
+	if got := calls[0].Args[1].(decimal.Decimal); !got.Equal(D(want)) {
+		t.Errorf("order quantity = %v, want %v", got, want)
+	}
+}
+
+// ConfigCase modifies a valid config, validation fails if WantErr
+type ConfigCase[C any] struct {
+	Name    string
+	Modify  func(cfg *C)
+	WantErr bool
+}
+
+// RunValidate checks that the config of newConfig is valid and validates it
+// modified by every case
+func RunValidate[C any](t *testing.T, newConfig func() *C, validate func(cfg *C) error, cases []ConfigCase[C]) {
+	t.Helper()
+
+	if err := validate(newConfig()); err != nil {
+		t.Fatalf("validate() of the test config error = %v", err)
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			cfg := newConfig()
+			tt.Modify(cfg)
+
+			if err := validate(cfg); (err != nil) != tt.WantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.WantErr)
+			}
+		})
+	}
+}
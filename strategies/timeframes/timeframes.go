@@ -0,0 +1,230 @@
+// Package timeframes lets indicator strategies confirm a signal of their own
+// klines interval with the trend of higher timeframes, e.g. a 15m buy only
+// when the 4h and 1d trends are up.
+package timeframes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/metrics"
+	"github.com/cinar/indicator"
+	"go.uber.org/zap"
+)
+
+type Direction string
+
+const (
+	Up      Direction = "up"
+	Down    Direction = "down"
+	Neutral Direction = "neutral"
+)
+
+func (d Direction) value() float64 {
+	switch d {
+	case Up:
+		return 1
+	case Down:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// TrendFunc tells the direction of a timeframe from its closes
+type TrendFunc func(closes []float64) Direction
+
+// EMATrend is up while the last close is above the EMA of period klines and
+// down while it is below
+func EMATrend(period int) TrendFunc {
+	return func(closes []float64) Direction {
+		if len(closes) < period {
+			return Neutral
+		}
+
+		ema := indicator.Ema(period, closes)
+		last := closes[len(closes)-1]
+
+		switch {
+		case last > ema[len(ema)-1]:
+			return Up
+		case last < ema[len(ema)-1]:
+			return Down
+		default:
+			return Neutral
+		}
+	}
+}
+
+type cacheEntry struct {
+	closes  []float64
+	expires time.Time
+}
+
+// Cache keeps the closes of every interval until the last fetched kline
+// closes, so a 4h trend is requested once per 4h kline and not every tick
+type Cache struct {
+	client clients.HttpClient
+	symbol string
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewCache(c clients.HttpClient, symbol string) *Cache {
+	return &Cache{
+		client:  c,
+		symbol:  symbol,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Closes returns the close prices of the interval klines, callers must not modify them
+func (c *Cache) Closes(ctx context.Context, interval string) ([]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[interval]; ok && c.now().Before(entry.expires) {
+		return entry.closes, nil
+	}
+
+	klines, err := c.client.GetKlines(ctx, c.symbol, interval)
+	if err != nil {
+		return nil, fmt.Errorf("client.GetKlines: %w", err)
+	}
+
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("no %s klines received", interval)
+	}
+
+	closes := make([]float64, len(klines))
+	for i, k := range klines {
+		closes[i] = k.Close
+	}
+
+	c.entries[interval] = cacheEntry{
+		closes:  closes,
+		expires: time.UnixMilli(klines[len(klines)-1].CloseTime),
+	}
+
+	return closes, nil
+}
+
+// Trend is the direction of an interval
+type Trend struct {
+	Interval  string
+	Direction Direction
+}
+
+// Decision is a signal checked against the trends of the confirmation timeframes
+type Decision struct {
+	Signal    Direction
+	Trends    []Trend
+	Confirmed bool
+}
+
+// Confirmer confirms signals when the trends of all its intervals agree with them
+type Confirmer struct {
+	strategy  string
+	symbol    string
+	intervals []string
+	trend     TrendFunc
+	cache     *Cache
+	z         *zap.SugaredLogger
+}
+
+// NewConfirmer creates a confirmer for a strategy, without intervals every
+// signal is confirmed
+func NewConfirmer(strategy string, c clients.HttpClient, symbol string, intervals []string, trend TrendFunc) *Confirmer {
+	return &Confirmer{
+		strategy:  strategy,
+		symbol:    symbol,
+		intervals: intervals,
+		trend:     trend,
+		cache:     NewCache(c, symbol),
+		z:         zap.S().With("context", "timeframes.Confirmer", "strategy", strategy, "symbol", symbol),
+	}
+}
+
+// Confirm checks the signal against every interval. Neutral signals are never
+// confirmed, a failure to get the klines of any interval rejects the signal.
+func (c *Confirmer) Confirm(ctx context.Context, signal Direction) (Decision, error) {
+	decision := Decision{
+		Signal:    signal,
+		Trends:    make([]Trend, 0, len(c.intervals)),
+		Confirmed: signal != Neutral,
+	}
+
+	for _, interval := range c.intervals {
+		closes, err := c.cache.Closes(ctx, interval)
+		if err != nil {
+			decision.Confirmed = false
+			c.record(decision)
+
+			return decision, fmt.Errorf("cache.Closes: %w", err)
+		}
+
+		trend := c.trend(closes)
+		decision.Trends = append(decision.Trends, Trend{Interval: interval, Direction: trend})
+		decision.Confirmed = decision.Confirmed && trend == signal
+
+		metrics.TimeframeTrend.WithLabelValues(c.strategy, c.symbol, interval).Set(trend.value())
+	}
+
+	c.record(decision)
+
+	return decision, nil
+}
+
+// Allows tells whether the signal is confirmed, a failure to confirm it is
+// logged and rejects the signal
+func (c *Confirmer) Allows(ctx context.Context, signal Direction) bool {
+	decision, err := c.Confirm(ctx, signal)
+	if err != nil {
+		c.z.Warnw(
+			"failed to confirm signal",
+			"signal", signal,
+			"error", err.Error(),
+		)
+
+		return false
+	}
+
+	return decision.Confirmed
+}
+
+func (c *Confirmer) record(decision Decision) {
+	result := "rejected"
+	if decision.Confirmed {
+		result = "confirmed"
+	}
+
+	metrics.SignalDecisions.WithLabelValues(c.strategy, c.symbol, string(decision.Signal), result).Inc()
+
+	if len(c.intervals) == 0 {
+		return
+	}
+
+	c.z.Infow(
+		"timeframes decision",
+		"signal", decision.Signal,
+		"trends", decision.String(),
+		"decision", result,
+	)
+}
+
+// String lists the trends as "4h=up 1d=down"
+func (d Decision) String() string {
+	parts := make([]string, len(d.Trends))
+	for i, trend := range d.Trends {
+		parts[i] = trend.Interval + "=" + string(trend.Direction)
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,187 @@
+package timeframes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/metrics"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+const testSymbol = "BTCUSDT"
+
+var (
+	rising  = strategytest.Rising(60)
+	falling = strategytest.Falling(60)
+)
+
+// klinesClosingAt builds klines of the closes with the last one closing at closeTime
+func klinesClosingAt(closes []float64, closeTime time.Time) []*models.Kline {
+	klines := make([]*models.Kline, len(closes))
+	for i, cl := range closes {
+		klines[i] = &models.Kline{Close: cl, CloseTime: closeTime.Add(time.Duration(i-len(closes)+1) * time.Hour).UnixMilli()}
+	}
+
+	return klines
+}
+
+func TestEMATrend(t *testing.T) {
+	tests := []struct {
+		name   string
+		closes []float64
+		want   Direction
+	}{
+		{name: "above the average", closes: rising, want: Up},
+		{name: "below the average", closes: falling, want: Down},
+		{name: "on the average", closes: strategytest.Series(60, func(int) float64 { return 5 }), want: Neutral},
+		{name: "not enough klines", closes: rising[:10], want: Neutral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EMATrend(20)(tt.closes); got != tt.want {
+				t.Errorf("EMATrend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCache_Closes(t *testing.T) {
+	now := time.Date(2022, time.June, 1, 10, 30, 0, 0, time.UTC)
+
+	c := mocks.NewHttpClient()
+	c.SetKlines(testSymbol, "4h", klinesClosingAt(rising, now.Add(time.Hour)))
+
+	cache := NewCache(c, testSymbol)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		closes, err := cache.Closes(context.Background(), "4h")
+		if err != nil {
+			t.Fatalf("Closes() error = %v", err)
+		}
+
+		if len(closes) != len(rising) || closes[len(closes)-1] != rising[len(rising)-1] {
+			t.Fatalf("Closes() = %v, want the klines closes", closes)
+		}
+	}
+
+	if got := len(c.CallsTo("GetKlines")); got != 1 {
+		t.Errorf("fetched klines %d times before the last kline closed, want 1", got)
+	}
+
+	if _, err := cache.Closes(context.Background(), "1d"); err == nil {
+		t.Error("Closes() without klines returned no error")
+	}
+
+	now = now.Add(time.Hour)
+
+	if _, err := cache.Closes(context.Background(), "4h"); err != nil {
+		t.Fatalf("Closes() error = %v", err)
+	}
+
+	if got := len(c.CallsTo("GetKlines")); got != 3 {
+		t.Errorf("fetched klines %d times after the last kline closed, want 3", got)
+	}
+}
+
+func TestConfirmer_Confirm(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []string
+		signal    Direction
+		want      bool
+		wantLog   string
+		failure   error
+		wantErr   bool
+	}{
+		{name: "no intervals", signal: Up, want: true},
+		{name: "neutral signal", signal: Neutral},
+		{name: "all agree", intervals: []string{"4h", "1w"}, signal: Up, want: true, wantLog: "4h=up 1w=up"},
+		{name: "one disagrees", intervals: []string{"4h", "1d"}, signal: Up, wantLog: "4h=up 1d=down"},
+		{name: "down confirmed", intervals: []string{"1d"}, signal: Down, want: true, wantLog: "1d=down"},
+		{name: "klines failure", intervals: []string{"4h", "1M"}, signal: Up, wantErr: true},
+		{name: "klines request failure", intervals: []string{"4h"}, signal: Up, failure: errors.New("timeout"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mocks.NewHttpClient()
+			c.SetCloses(testSymbol, "4h", rising)
+			c.SetCloses(testSymbol, "1w", rising)
+			c.SetCloses(testSymbol, "1d", falling)
+
+			if tt.failure != nil {
+				c.Fail("GetKlines", tt.failure, 1)
+			}
+
+			strategy := "test " + tt.name
+			confirmer := NewConfirmer(strategy, c, testSymbol, tt.intervals, EMATrend(20))
+
+			decision, err := confirmer.Confirm(context.Background(), tt.signal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Confirm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if decision.Confirmed != tt.want {
+				t.Errorf("Confirm() confirmed = %v, want %v", decision.Confirmed, tt.want)
+			}
+
+			if tt.wantLog != "" && decision.String() != tt.wantLog {
+				t.Errorf("Decision.String() = %q, want %q", decision.String(), tt.wantLog)
+			}
+
+			result := "rejected"
+			if tt.want {
+				result = "confirmed"
+			}
+
+			counter := metrics.SignalDecisions.WithLabelValues(strategy, testSymbol, string(tt.signal), result)
+			if got := testutil.ToFloat64(counter); got != 1 {
+				t.Errorf("%s decisions counter = %v, want 1", result, got)
+			}
+		})
+	}
+}
+
+func TestConfirmer_TrendMetric(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetCloses(testSymbol, "1d", falling)
+
+	confirmer := NewConfirmer("test trend metric", c, testSymbol, []string{"1d"}, EMATrend(20))
+
+	if _, err := confirmer.Confirm(context.Background(), Up); err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.TimeframeTrend.WithLabelValues("test trend metric", testSymbol, "1d")); got != -1 {
+		t.Errorf("trend gauge = %v, want -1", got)
+	}
+}
+
+func TestConfirmer_Allows(t *testing.T) {
+	c := mocks.NewHttpClient()
+	c.SetCloses(testSymbol, "4h", rising)
+
+	confirmer := NewConfirmer("test allows", c, testSymbol, []string{"4h"}, EMATrend(20))
+
+	if !confirmer.Allows(context.Background(), Up) {
+		t.Error("Allows() = false for a signal the trend agrees with")
+	}
+
+	if confirmer.Allows(context.Background(), Down) {
+		t.Error("Allows() = true for a signal the trend disagrees with")
+	}
+
+	// the cached closes expired long ago
+	c.Fail("GetKlines", errors.New("timeout"), 1)
+
+	if confirmer.Allows(context.Background(), Up) {
+		t.Error("Allows() = true without the klines")
+	}
+}
@@ -0,0 +1,241 @@
+package triangularStrategy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/shopspring/decimal"
+)
+
+func (s *TriangularStrategy) Start(ctx context.Context) error {
+	go s.logic(ctx)
+
+	logicTicker := time.NewTicker(s.cfg.Interval)
+	defer logicTicker.Stop()
+
+	for {
+		select {
+		case <-logicTicker.C:
+			go s.logic(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *TriangularStrategy) logic(ctx context.Context) {
+	if !s.mu.TryLock() {
+		// a cycle is still being executed, the books it saw are gone anyway
+		return
+	}
+	defer s.mu.Unlock()
+
+	if s.cycles == nil || time.Since(s.loadedAt) >= s.cfg.RefreshPeriod {
+		if err := s.loadCycles(ctx); err != nil {
+			s.z.Warnw("failed to load cycles", "error", err.Error())
+			return
+		}
+	}
+
+	list, err := s.client.GetBookTickers(ctx)
+	if err != nil {
+		s.z.Warnw("failed to get book tickers", "error", err.Error())
+		return
+	}
+
+	tickers := make(map[string]*models.BookTicker, len(list))
+	for _, ticker := range list {
+		tickers[ticker.Symbol] = ticker
+	}
+
+	best, profit, ok := s.bestCycle(tickers)
+	if !ok || profit.LessThan(s.cfg.MinProfit) {
+		return
+	}
+
+	legs, _ := best.simulate(tickers, s.cfg.OrderAmount, s.cfg.Fee)
+
+	s.z.Infow(
+		"triangular opportunity",
+		"cycle", best.String(),
+		"profit", profit,
+		"amount", s.cfg.OrderAmount,
+		"expected", legs[2].received,
+	)
+
+	if s.cfg.DryRun {
+		return
+	}
+
+	for i, e := range best {
+		if reason := e.rejection(legs[i]); reason != "" {
+			s.z.Infow("cycle skipped", "cycle", best.String(), "symbol", e.symbol.Symbol, "reason", reason)
+			return
+		}
+	}
+
+	s.execute(ctx, best, tickers)
+}
+
+// loadCycles builds the graph of the trading symbols and the cycles through the start coin
+func (s *TriangularStrategy) loadCycles(ctx context.Context) error {
+	symbols, err := s.client.GetSymbols(ctx)
+	if err != nil {
+		return fmt.Errorf("client.GetSymbols: %w", err)
+	}
+
+	var coins map[string]bool
+	if len(s.cfg.Coins) > 0 {
+		coins = map[string]bool{s.cfg.StartCoin: true}
+		for _, coin := range s.cfg.Coins {
+			coins[coin] = true
+		}
+	}
+
+	s.cycles = findCycles(newGraph(symbols, coins), s.cfg.StartCoin)
+	s.loadedAt = time.Now()
+
+	s.z.Infow("cycles loaded", "symbols", len(symbols), "cycles", len(s.cycles))
+
+	return nil
+}
+
+// bestCycle returns the most profitable cycle at the top of the books
+func (s *TriangularStrategy) bestCycle(tickers map[string]*models.BookTicker) (cycle, decimal.Decimal, bool) {
+	var (
+		best   cycle
+		profit decimal.Decimal
+		found  bool
+	)
+
+	for _, c := range s.cycles {
+		rate, ok := c.rate(tickers, s.cfg.Fee)
+		if !ok {
+			continue
+		}
+
+		if p := rate.Sub(decimal.NewFromInt(1)); !found || p.GreaterThan(profit) {
+			best, profit, found = c, p, true
+		}
+	}
+
+	return best, profit, found
+}
+
+// execute places the legs one after another as IOC orders at the prices seen,
+// each leg converts what the previous one actually received. A leg that fills
+// nothing leaves an intermediate coin, it is converted back to the start coin
+// with a market order.
+func (s *TriangularStrategy) execute(ctx context.Context, c cycle, tickers map[string]*models.BookTicker) {
+	amount := s.cfg.OrderAmount
+
+	for i, e := range c {
+		l := e.quote(tickers[e.symbol.Symbol], amount, s.cfg.Fee)
+
+		order, err := s.client.NewOrder(ctx, e.symbol.Symbol, l.side, models.OrderTypeLimit, models.TimeInForceTypeIOC, l.price, l.quantity)
+		if err != nil {
+			s.z.Warnw(
+				"failed to place order",
+				"cycle", c.String(),
+				"symbol", e.symbol.Symbol,
+				"side", l.side,
+				"type", "limit",
+				"price", l.price,
+				"quantity", l.quantity,
+				"error", err.Error(),
+			)
+		}
+
+		received := s.received(e, order)
+		if !received.IsPositive() {
+			s.z.Warnw("cycle broken", "cycle", c.String(), "leg", i+1, "held", e.from, "amount", amount)
+			s.unwind(ctx, c, i, amount, tickers)
+
+			return
+		}
+
+		amount = received
+	}
+
+	s.z.Infow(
+		"cycle executed",
+		"cycle", c.String(),
+		"amount", s.cfg.OrderAmount,
+		"received", amount,
+		"profit", amount.Sub(s.cfg.OrderAmount),
+	)
+}
+
+// received is what the order brought of the coin the edge leads to, after the fee
+func (s *TriangularStrategy) received(e edge, order *models.Order) decimal.Decimal {
+	if order == nil {
+		return decimal.Zero
+	}
+
+	keep := decimal.NewFromInt(1).Sub(s.cfg.Fee)
+
+	if e.side() == models.SideTypeSell {
+		return order.CummulativeQuoteQuantity.Mul(keep)
+	}
+
+	return order.ExecutedQuantity.Mul(keep)
+}
+
+// unwind converts the amount held before the broken leg back to the start coin:
+// after the first leg it undoes the first one, after the second it finishes the
+// third one. Both are market orders, getting out matters more than the price.
+func (s *TriangularStrategy) unwind(ctx context.Context, c cycle, broken int, amount decimal.Decimal, tickers map[string]*models.BookTicker) {
+	var e edge
+
+	switch broken {
+	case 0:
+		return
+	case 1:
+		e = c[0].reverse()
+	default:
+		e = c[2]
+	}
+
+	l := e.quote(tickers[e.symbol.Symbol], amount, s.cfg.Fee)
+	if reason := e.rejection(l); reason != "" {
+		s.z.Warnw("failed to unwind cycle", "cycle", c.String(), "symbol", e.symbol.Symbol, "amount", amount, "error", reason)
+		return
+	}
+
+	var err error
+
+	if l.side == models.SideTypeBuy {
+		_, err = s.client.NewMarketBuyOrder(ctx, e.symbol.Symbol, l.quantity)
+	} else {
+		_, err = s.client.NewMarketSellOrder(ctx, e.symbol.Symbol, l.quantity)
+	}
+
+	if err != nil {
+		s.z.Errorw(
+			"failed to unwind cycle",
+			"cycle", c.String(),
+			"symbol", e.symbol.Symbol,
+			"side", l.side,
+			"type", "market",
+			"quantity", l.quantity,
+			"error", err.Error(),
+		)
+
+		return
+	}
+
+	s.z.Warnw(
+		"cycle unwound",
+		"cycle", c.String(),
+		"symbol", e.symbol.Symbol,
+		"side", l.side,
+		"type", "market",
+		"quantity", l.quantity,
+	)
+}
+
+func (s *TriangularStrategy) Stop(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,161 @@
+package triangularStrategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients/mocks"
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+	"golang.org/x/exp/slices"
+)
+
+func newTestConfig() *Config {
+	return &Config{
+		StartCoin:     "USDT",
+		Interval:      1,
+		RefreshPeriod: time.Hour,
+		OrderAmount:   d("100"),
+		Fee:           d("0.001"),
+		MinProfit:     d("0.001"),
+	}
+}
+
+// newTestClient lists the test symbols and quotes them with the test tickers
+func newTestClient() *mocks.HttpClient {
+	c := mocks.NewHttpClient()
+
+	for _, symbol := range testSymbols() {
+		c.SetSymbol(symbol)
+	}
+
+	for symbol, ticker := range testTickers() {
+		c.SetPrice(symbol, ticker.BidPrice.String())
+		c.SetBookTicker(symbol, ticker.BidPrice.String(), ticker.AskPrice.String())
+	}
+
+	return c
+}
+
+func orders(c *mocks.HttpClient) []string {
+	var result []string
+	for _, order := range c.Orders() {
+		result = append(result, order.Symbol+" "+string(order.Side)+" "+string(order.Type)+" "+order.OrigQuantity.String())
+	}
+
+	return result
+}
+
+func TestConfig_validate(t *testing.T) {
+	strategytest.RunValidate(t, newTestConfig, (*Config).validate, []strategytest.ConfigCase[Config]{
+		{Name: "restricted coins", Modify: func(cfg *Config) { cfg.Coins = []string{"BTC", "ETH"} }},
+		{Name: "no start coin", Modify: func(cfg *Config) { cfg.StartCoin = "" }, WantErr: true},
+		{Name: "one coin", Modify: func(cfg *Config) { cfg.Coins = []string{"BTC"} }, WantErr: true},
+		{Name: "zero amount", Modify: func(cfg *Config) { cfg.OrderAmount = decimal.Zero }, WantErr: true},
+		{Name: "fee of 1", Modify: func(cfg *Config) { cfg.Fee = d("1") }, WantErr: true},
+		{Name: "negative profit", Modify: func(cfg *Config) { cfg.MinProfit = d("-0.1") }, WantErr: true},
+	})
+}
+
+func TestTriangularStrategy_logic(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config, c *mocks.HttpClient)
+		want   []string
+	}{
+		{
+			name: "executes the profitable cycle",
+			want: []string{"BTCUSDT BUY LIMIT 0.005", "ETHBTC BUY LIMIT 0.0999", "ETHUSDT SELL LIMIT 0.0998"},
+		},
+		{
+			name:   "dry run only logs",
+			modify: func(cfg *Config, c *mocks.HttpClient) { cfg.DryRun = true },
+		},
+		{
+			name:   "profit below the minimum",
+			modify: func(cfg *Config, c *mocks.HttpClient) { cfg.MinProfit = d("0.02") },
+		},
+		{
+			name:   "legs below min notional are skipped",
+			modify: func(cfg *Config, c *mocks.HttpClient) { cfg.OrderAmount = d("5") },
+		},
+		{
+			name:   "no symbols",
+			modify: func(cfg *Config, c *mocks.HttpClient) { c.Fail("GetSymbols", errors.New("timeout"), 0) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, c := newTestConfig(), newTestClient()
+			if tt.modify != nil {
+				tt.modify(cfg, c)
+			}
+
+			s := NewTriangularStrategy(c, cfg)
+			s.logic(context.Background())
+
+			if got := orders(c); !slices.Equal(got, tt.want) {
+				t.Errorf("placed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriangularStrategy_logicLoadsCyclesOnce(t *testing.T) {
+	c := newTestClient()
+
+	cfg := newTestConfig()
+	cfg.DryRun = true
+
+	s := NewTriangularStrategy(c, cfg)
+	s.logic(context.Background())
+	s.logic(context.Background())
+
+	if calls := c.CallsTo("GetSymbols"); len(calls) != 1 {
+		t.Errorf("GetSymbols called %d times, want 1", len(calls))
+	}
+}
+
+func TestTriangularStrategy_executeUnwinds(t *testing.T) {
+	tests := []struct {
+		name  string
+		stale func(tickers map[string]*models.BookTicker)
+		want  []string
+	}{
+		{
+			name: "second leg expired, the first one is undone",
+			stale: func(tickers map[string]*models.BookTicker) {
+				tickers["ETHBTC"].AskPrice = d("0.049")
+			},
+			want: []string{"BTCUSDT BUY LIMIT 0.005", "ETHBTC BUY LIMIT 0.1019", "BTCUSDT SELL MARKET 0.00499"},
+		},
+		{
+			name: "third leg expired, it is finished at market",
+			stale: func(tickers map[string]*models.BookTicker) {
+				tickers["ETHUSDT"].BidPrice = d("1030")
+			},
+			want: []string{"BTCUSDT BUY LIMIT 0.005", "ETHBTC BUY LIMIT 0.0999", "ETHUSDT SELL LIMIT 0.0998", "ETHUSDT SELL MARKET 0.0998"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient()
+			s := NewTriangularStrategy(c, newTestConfig())
+
+			// the books moved between the scan and the orders
+			tickers := testTickers()
+			tt.stale(tickers)
+
+			s.execute(context.Background(), findCycles(newGraph(testSymbols(), nil), "USDT")[0], tickers)
+
+			if got := orders(c); !slices.Equal(got, tt.want) {
+				t.Errorf("placed %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package triangularStrategy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+	"github.com/shopspring/decimal"
+)
+
+type Config struct {
+	StartCoin     string          `env:"STRATEGIES_TRIANGULAR_START_COIN"     envDefault:"USDT"`  // coin every cycle starts and ends in
+	Coins         []string        `env:"STRATEGIES_TRIANGULAR_COINS"          envSeparator:","`   // coins the cycles may go through, empty means all
+	Interval      time.Duration   `env:"STRATEGIES_TRIANGULAR_INTERVAL"       envDefault:"5s"`    // how often the books are scanned
+	RefreshPeriod time.Duration   `env:"STRATEGIES_TRIANGULAR_REFRESH_PERIOD" envDefault:"1h"`    // how often the symbols are reloaded from exchange info
+	OrderAmount   decimal.Decimal `env:"STRATEGIES_TRIANGULAR_ORDER_AMOUNT"   envDefault:"20"`    // start coin amount put through a cycle
+	Fee           decimal.Decimal `env:"STRATEGIES_TRIANGULAR_FEE"            envDefault:"0.001"` // taker fee share of every leg
+	MinProfit     decimal.Decimal `env:"STRATEGIES_TRIANGULAR_MIN_PROFIT"     envDefault:"0.001"` // profit share after fees a cycle needs
+	DryRun        bool            `env:"STRATEGIES_TRIANGULAR_DRY_RUN"        envDefault:"true"`  // only log the opportunities
+}
+
+func NewConfigFromEnv() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	cfg.StartCoin = strings.ToUpper(strings.TrimSpace(cfg.StartCoin))
+
+	for i := range cfg.Coins {
+		cfg.Coins[i] = strings.ToUpper(strings.TrimSpace(cfg.Coins[i]))
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("cfg.validate: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) validate() error {
+	if cfg.StartCoin == "" {
+		return fmt.Errorf("start coin is required")
+	}
+
+	if len(cfg.Coins) > 0 && len(cfg.Coins) < 2 {
+		return fmt.Errorf("a cycle needs two coins besides %s, got %v", cfg.StartCoin, cfg.Coins)
+	}
+
+	if !cfg.OrderAmount.IsPositive() {
+		return fmt.Errorf("order amount must be positive, got %s", cfg.OrderAmount)
+	}
+
+	if cfg.Fee.IsNegative() || cfg.Fee.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("fee must be between 0 and 1, got %s", cfg.Fee)
+	}
+
+	if cfg.MinProfit.IsNegative() {
+		return fmt.Errorf("min profit must not be negative, got %s", cfg.MinProfit)
+	}
+
+	return nil
+}
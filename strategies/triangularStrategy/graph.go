@@ -0,0 +1,180 @@
+package triangularStrategy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/utils"
+	"github.com/shopspring/decimal"
+)
+
+// edge converts one coin into another through a symbol. Symbols keep the
+// exchange naming: the price is in the quote asset per one base asset, so
+// the base asset is sold and the quote asset buys it.
+type edge struct {
+	from, to string
+	symbol   *models.Symbol
+}
+
+func (e edge) side() models.SideType {
+	if e.from == e.symbol.BaseAsset {
+		return models.SideTypeSell
+	}
+
+	return models.SideTypeBuy
+}
+
+func (e edge) reverse() edge {
+	return edge{from: e.to, to: e.from, symbol: e.symbol}
+}
+
+// leg is an order of a cycle estimated from the top of the book
+type leg struct {
+	side     models.SideType
+	price    decimal.Decimal
+	quantity decimal.Decimal // in the base asset of the symbol
+	received decimal.Decimal // of the coin the edge leads to, after the fee
+}
+
+// quote estimates the order converting the amount of the from coin at the
+// top of the book, the quantity is rounded down to the step of the symbol
+func (e edge) quote(ticker *models.BookTicker, amount, fee decimal.Decimal) leg {
+	keep := decimal.NewFromInt(1).Sub(fee)
+
+	if e.side() == models.SideTypeSell {
+		quantity := utils.TruncateStep(amount, e.symbol.StepSize)
+
+		return leg{
+			side:     models.SideTypeSell,
+			price:    ticker.BidPrice,
+			quantity: quantity,
+			received: quantity.Mul(ticker.BidPrice).Mul(keep),
+		}
+	}
+
+	if !ticker.AskPrice.IsPositive() {
+		return leg{side: models.SideTypeBuy, price: ticker.AskPrice}
+	}
+
+	quantity := utils.TruncateStep(amount.Div(ticker.AskPrice), e.symbol.StepSize)
+
+	return leg{
+		side:     models.SideTypeBuy,
+		price:    ticker.AskPrice,
+		quantity: quantity,
+		received: quantity.Mul(keep),
+	}
+}
+
+// rejection returns why the exchange would reject the order, empty if it wouldn't
+func (e edge) rejection(l leg) string {
+	switch {
+	case !l.price.IsPositive():
+		return "no price"
+	case !l.quantity.IsPositive():
+		return "quantity is zero"
+	case l.quantity.LessThan(e.symbol.MinQuantity):
+		return "quantity is below " + e.symbol.MinQuantity.String()
+	case l.quantity.Mul(l.price).LessThan(e.symbol.MinNotional):
+		return "value is below " + e.symbol.MinNotional.String()
+	default:
+		return ""
+	}
+}
+
+// cycle is three conversions ending in the coin the first one starts from
+type cycle [3]edge
+
+func (c cycle) String() string {
+	return strings.Join([]string{c[0].from, c[0].to, c[1].to, c[2].to}, "->")
+}
+
+// simulate walks the amount through the cycle at the top of the books, ok is
+// false if a book is missing
+func (c cycle) simulate(tickers map[string]*models.BookTicker, amount, fee decimal.Decimal) ([3]leg, bool) {
+	var legs [3]leg
+
+	for i, e := range c {
+		ticker, ok := tickers[e.symbol.Symbol]
+		if !ok {
+			return legs, false
+		}
+
+		legs[i] = e.quote(ticker, amount, fee)
+		amount = legs[i].received
+	}
+
+	return legs, true
+}
+
+// rate is what one unit of the start coin turns into after the whole cycle
+// and its fees, ignoring steps. ok is false if a book is missing or empty.
+func (c cycle) rate(tickers map[string]*models.BookTicker, fee decimal.Decimal) (decimal.Decimal, bool) {
+	keep := decimal.NewFromInt(1).Sub(fee)
+	rate := decimal.NewFromInt(1)
+
+	for _, e := range c {
+		ticker, ok := tickers[e.symbol.Symbol]
+		if !ok || !ticker.BidPrice.IsPositive() || !ticker.AskPrice.IsPositive() {
+			return decimal.Zero, false
+		}
+
+		if e.side() == models.SideTypeSell {
+			rate = rate.Mul(ticker.BidPrice)
+		} else {
+			rate = rate.Div(ticker.AskPrice)
+		}
+
+		rate = rate.Mul(keep)
+	}
+
+	return rate, true
+}
+
+// newGraph links the coins through the trading symbols, if coins is not empty
+// only the symbols between the listed coins are used
+func newGraph(symbols []*models.Symbol, coins map[string]bool) map[string][]edge {
+	graph := make(map[string][]edge)
+
+	for _, symbol := range symbols {
+		if symbol.Status != "TRADING" {
+			continue
+		}
+
+		if len(coins) > 0 && (!coins[symbol.BaseAsset] || !coins[symbol.QuoteAsset]) {
+			continue
+		}
+
+		graph[symbol.BaseAsset] = append(graph[symbol.BaseAsset], edge{from: symbol.BaseAsset, to: symbol.QuoteAsset, symbol: symbol})
+		graph[symbol.QuoteAsset] = append(graph[symbol.QuoteAsset], edge{from: symbol.QuoteAsset, to: symbol.BaseAsset, symbol: symbol})
+	}
+
+	for coin := range graph {
+		edges := graph[coin]
+		sort.Slice(edges, func(i, j int) bool { return edges[i].to < edges[j].to })
+	}
+
+	return graph
+}
+
+// findCycles returns every cycle from the start coin through two other coins and back
+func findCycles(graph map[string][]edge, start string) []cycle {
+	var cycles []cycle
+
+	for _, first := range graph[start] {
+		for _, second := range graph[first.to] {
+			if second.to == start {
+				continue
+			}
+
+			for _, third := range graph[second.to] {
+				if third.to == start {
+					cycles = append(cycles, cycle{first, second, third})
+				}
+			}
+		}
+	}
+
+	return cycles
+}
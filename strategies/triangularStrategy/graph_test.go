@@ -0,0 +1,115 @@
+package triangularStrategy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Minish144/crypto-trading-bot/models"
+	"github.com/Minish144/crypto-trading-bot/strategies/strategytest"
+	"github.com/shopspring/decimal"
+)
+
+var d = strategytest.D
+
+func testSymbols() []*models.Symbol {
+	return []*models.Symbol{
+		{Symbol: "BTCUSDT", Status: "TRADING", BaseAsset: "BTC", QuoteAsset: "USDT", StepSize: d("0.00001"), MinNotional: d("10")},
+		{Symbol: "ETHBTC", Status: "TRADING", BaseAsset: "ETH", QuoteAsset: "BTC", StepSize: d("0.0001"), MinNotional: d("0.0001")},
+		{Symbol: "ETHUSDT", Status: "TRADING", BaseAsset: "ETH", QuoteAsset: "USDT", StepSize: d("0.0001"), MinNotional: d("10")},
+		{Symbol: "BNBUSDT", Status: "TRADING", BaseAsset: "BNB", QuoteAsset: "USDT", StepSize: d("0.001"), MinNotional: d("10")},
+		{Symbol: "BNBBTC", Status: "BREAK", BaseAsset: "BNB", QuoteAsset: "BTC", StepSize: d("0.001"), MinNotional: d("0.0001")},
+	}
+}
+
+// testTickers make USDT->BTC->ETH->USDT earn about 2% before fees
+func testTickers() map[string]*models.BookTicker {
+	return map[string]*models.BookTicker{
+		"BTCUSDT": {Symbol: "BTCUSDT", BidPrice: d("19999"), AskPrice: d("20000")},
+		"ETHBTC":  {Symbol: "ETHBTC", BidPrice: d("0.0499"), AskPrice: d("0.05")},
+		"ETHUSDT": {Symbol: "ETHUSDT", BidPrice: d("1020"), AskPrice: d("1021")},
+		"BNBUSDT": {Symbol: "BNBUSDT", BidPrice: d("300"), AskPrice: d("301")},
+	}
+}
+
+func cycleNames(cycles []cycle) []string {
+	names := make([]string, len(cycles))
+	for i, c := range cycles {
+		names[i] = c.String()
+	}
+
+	return names
+}
+
+func Test_findCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		coins map[string]bool
+		want  []string
+	}{
+		{name: "all coins", want: []string{"USDT->BTC->ETH->USDT", "USDT->ETH->BTC->USDT"}},
+		{name: "restricted coins", coins: map[string]bool{"USDT": true, "BTC": true, "BNB": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cycleNames(findCycles(newGraph(testSymbols(), tt.coins), "USDT"))
+			if len(got) != len(tt.want) || len(got) > 0 && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findCycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cycle_rate(t *testing.T) {
+	cycles := findCycles(newGraph(testSymbols(), nil), "USDT")
+	tickers := testTickers()
+
+	tests := []struct {
+		name string
+		fee  string
+		want string
+	}{
+		{name: "without fees", fee: "0", want: "1.02"},
+		{name: "with fees", fee: "0.001", want: "1.016943"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := cycles[0].rate(tickers, d(tt.fee))
+			if !ok || !rate.Round(6).Equal(d(tt.want)) {
+				t.Errorf("rate() = %v, %v, want %s", rate, ok, tt.want)
+			}
+		})
+	}
+
+	delete(tickers, "ETHBTC")
+
+	if _, ok := cycles[0].rate(tickers, decimal.Zero); ok {
+		t.Error("rate() without a book succeeded")
+	}
+}
+
+func Test_cycle_simulate(t *testing.T) {
+	c := findCycles(newGraph(testSymbols(), nil), "USDT")[0]
+
+	legs, ok := c.simulate(testTickers(), d("100"), d("0.001"))
+	if !ok {
+		t.Fatal("simulate() found no books")
+	}
+
+	want := []struct {
+		side     models.SideType
+		quantity string
+		received string
+	}{
+		{side: models.SideTypeBuy, quantity: "0.005", received: "0.004995"},
+		{side: models.SideTypeBuy, quantity: "0.0999", received: "0.0998001"},
+		{side: models.SideTypeSell, quantity: "0.0998", received: "101.694204"},
+	}
+
+	for i, w := range want {
+		if legs[i].side != w.side || !legs[i].quantity.Equal(d(w.quantity)) || !legs[i].received.Equal(d(w.received)) {
+			t.Errorf("leg %d = %+v, want %s %s receiving %s", i+1, legs[i], w.side, w.quantity, w.received)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package triangularStrategy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Minish144/crypto-trading-bot/clients"
+	"github.com/Minish144/crypto-trading-bot/strategies"
+	"go.uber.org/zap"
+)
+
+type TriangularStrategy struct {
+	name   string
+	cfg    *Config
+	client clients.HttpClient
+	test   bool
+	z      *zap.SugaredLogger
+
+	mu       sync.Mutex
+	cycles   []cycle   // cycles through the start coin, built from exchange info
+	loadedAt time.Time // when the cycles were built
+}
+
+func NewTriangularStrategy(c clients.HttpClient, cfg *Config) *TriangularStrategy {
+	z := zap.S().With("context", "TriangularStrategy", "start_coin", cfg.StartCoin, "dry_run", cfg.DryRun)
+
+	return &TriangularStrategy{
+		name:   "triangular arbitrage strategy",
+		cfg:    cfg,
+		client: c,
+		z:      z,
+	}
+}
+
+// Register adds the strategy to the registry as "triangular"
+func Register(r *strategies.Registry) {
+	strategies.Register(r, "triangular", NewConfigFromEnv, NewTriangularStrategy)
+}
+
+func (s *TriangularStrategy) Name() string {
+	return s.name + ": " + s.cfg.StartCoin
+}
@@ -1,5 +1,11 @@
 package utils
 
-func QuoteQtyFromBaseQty(basePrice float64, baseAmount float64) float64 {
-	return baseAmount / basePrice
+import "github.com/shopspring/decimal"
+
+func QuoteQtyFromBaseQty(basePrice decimal.Decimal, baseAmount decimal.Decimal) decimal.Decimal {
+	if basePrice.IsZero() {
+		return decimal.Zero
+	}
+
+	return baseAmount.Div(basePrice)
 }
@@ -2,8 +2,9 @@ package utils
 
 import (
 	"fmt"
-	"math"
 	"strconv"
+
+	"github.com/shopspring/decimal"
 )
 
 func StringToFloat64(s string) (float64, error) {
@@ -24,7 +25,48 @@ func IntToString(i int) string {
 	return fmt.Sprintf("%d", i)
 }
 
-func RoundPrecision(f float64, precision uint) float64 {
-	ratio := math.Pow(10, float64(precision))
-	return math.Round(f*ratio) / ratio
+func StringToDecimal(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to parse decimal: %w", err)
+	}
+
+	return d, nil
+}
+
+// DecimalToString formats d with exactly precision digits after the point,
+// dropping the digits that don't fit instead of rounding them up, use it for
+// quantities
+func DecimalToString(d decimal.Decimal, precision uint) string {
+	return TruncatePrecision(d, precision).StringFixed(int32(precision))
+}
+
+// PriceToString formats the price with exactly precision digits after the
+// point, rounding the digits that don't fit
+func PriceToString(price decimal.Decimal, precision uint) string {
+	return RoundPrecision(price, precision).StringFixed(int32(precision))
+}
+
+// RoundPrecision rounds half away from zero, use it for prices
+func RoundPrecision(d decimal.Decimal, precision uint) decimal.Decimal {
+	return d.Round(int32(precision))
+}
+
+// TruncatePrecision rounds towards zero, use it for quantities so that
+// an order never asks for more than what is available
+func TruncatePrecision(d decimal.Decimal, precision uint) decimal.Decimal {
+	return d.Truncate(int32(precision))
+}
+
+// maxStepPrecision is the most decimal places an exchange accepts
+const maxStepPrecision = 8
+
+// TruncateStep rounds towards zero to a multiple of the step of a symbol,
+// without a step it truncates to the most decimal places an exchange accepts
+func TruncateStep(d, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return TruncatePrecision(d, maxStepPrecision)
+	}
+
+	return d.Div(step).Floor().Mul(step)
 }
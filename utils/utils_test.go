@@ -2,6 +2,8 @@ package utils
 
 import (
 	"testing"
+
+	"github.com/shopspring/decimal"
 )
 
 // @TODO implement tests
@@ -18,3 +20,105 @@ func TestFloat64ToString(t *testing.T) {
 func TestIntToString(t *testing.T) {
 	t.Skip()
 }
+
+func TestStringToDecimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "exchange price", s: "0.10000000", want: "0.1"},
+		{name: "integer", s: "42", want: "42"},
+		{name: "not a number", s: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StringToDecimal(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StringToDecimal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("StringToDecimal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalToString(t *testing.T) {
+	tests := []struct {
+		name      string
+		d         decimal.Decimal
+		precision uint
+		want      string
+	}{
+		{name: "pads zeros", d: decimal.RequireFromString("1.5"), precision: 3, want: "1.500"},
+		{name: "drops extra digits", d: decimal.RequireFromString("0.12999"), precision: 2, want: "0.12"},
+		{name: "no fraction", d: decimal.RequireFromString("7.9"), precision: 0, want: "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecimalToString(tt.d, tt.precision); got != tt.want {
+				t.Errorf("DecimalToString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceToString(t *testing.T) {
+	tests := []struct {
+		name      string
+		price     decimal.Decimal
+		precision uint
+		want      string
+	}{
+		{name: "pads zeros", price: decimal.RequireFromString("1.5"), precision: 3, want: "1.500"},
+		{name: "rounds up", price: decimal.RequireFromString("0.12999"), precision: 2, want: "0.13"},
+		{name: "rounds down", price: decimal.RequireFromString("0.12499"), precision: 2, want: "0.12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PriceToString(tt.price, tt.precision); got != tt.want {
+				t.Errorf("PriceToString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundPrecision(t *testing.T) {
+	// 1.005 can't be represented as float64 and used to round down to 1.00
+	got := RoundPrecision(decimal.RequireFromString("1.005"), 2)
+	if !got.Equal(decimal.RequireFromString("1.01")) {
+		t.Errorf("RoundPrecision() = %v, want 1.01", got)
+	}
+}
+
+func TestTruncatePrecision(t *testing.T) {
+	// quantity computed from a quote amount must never be rounded up
+	got := TruncatePrecision(QuoteQtyFromBaseQty(decimal.NewFromInt(3), decimal.NewFromInt(10)), 3)
+	if !got.Equal(decimal.RequireFromString("3.333")) {
+		t.Errorf("TruncatePrecision() = %v, want 3.333", got)
+	}
+}
+
+func TestTruncateStep(t *testing.T) {
+	tests := []struct {
+		d, step, want string
+	}{
+		{d: "1.23456", step: "0.01", want: "1.23"},
+		{d: "1.23456", step: "0.05", want: "1.2"},
+		{d: "7", step: "5", want: "5"},
+		{d: "0.123456789", step: "0", want: "0.12345678"},
+	}
+
+	for _, tt := range tests {
+		got := TruncateStep(decimal.RequireFromString(tt.d), decimal.RequireFromString(tt.step))
+		if !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("TruncateStep(%s, %s) = %v, want %s", tt.d, tt.step, got, tt.want)
+		}
+	}
+}